@@ -1,35 +1,128 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"math/rand"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/akr411/doit/internal/export"
 	"github.com/akr411/doit/internal/models"
 	"github.com/akr411/doit/internal/storage"
 	"github.com/akr411/doit/internal/ui"
 	"github.com/akr411/doit/internal/utils"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
 )
 
-// Character limits
-const (
-	MaxTitleLength       = 100
-	MaxDescriptionLength = 500
-)
+// stringList is a flag value collecting repeated string flags
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
 
 var (
 	title       string
 	description string
 	deadline    string
+	tags        string
+	project     string
+	focus       string
+	dayFlag     string
+	dueDay      time.Time
+	subtasks    stringList
+	attachments stringList
+	repeat      string
+	remind      string
+	waitingOn   string
+	plan        string
+	points      int
 	listMode    bool
+	thenList    bool
+	reviewMode  bool
+	statsMode   bool
+	randomMode  bool
+	dbFlag      string
+	whereMode   bool
+	encryptMode bool
+	decryptMode bool
+	compactMode bool
+	plainMode   bool
+	countMode   bool
+	jsonOutput  bool
+	searchQuery string
+	inArchive   bool
+	exportPath  string
+	taskwarrior string
+	jsonlPath   string
+	importPath  string
+	todoImport  string
+	csvExport   string
+	csvImport   string
+	csvReplace  bool
+	carryOver   bool
+	planNext    int
+	doneOverdue bool
+	snoozeAll   string
+	doctorMode  bool
+	fixMode     bool
+	schedule    int
+	dedupe      bool
+	doneID      string
+	doneAt      string
+	doneNote    string
+	replaceText string
+	mergePath   string
+	dryRun      bool
+	wrapTitles  bool
+	bothDates   bool
+	markdown    bool
+	tableLayout bool
+	autoExpand  bool
+	foldUp      bool
+	pagedList   bool
+	emptyToForm bool
+	doneFirst   bool
+	strictMode  bool
+	warnDays    int
+	soonDays    int
+	morningHour int
+	staleDays   int
+	staleNudge  bool
+	weeklyGoal  int
+	capacity    int
+	weekDay     string
+	weekStart   time.Weekday
+	timezone    string
+	midnight    bool
+	confirmOver int
+	showZone    bool
+	verbose     bool
 	showHelp    bool
 )
 
+// timer logs how long each stage takes with -verbose, and is nil otherwise
+var timer *utils.Timer
+
 func init() {
 	flag.StringVar(&title, "title", "", "Title of the todo")
 	flag.StringVar(&title, "t", "", "Title of the todo")
@@ -40,8 +133,99 @@ func init() {
 	flag.StringVar(&deadline, "deadline", "", "Deadline for the todo")
 	flag.StringVar(&deadline, "n", "", "Deadline for the todo")
 
+	flag.StringVar(&tags, "tags", "", "Comma separated tags for the todo")
+	flag.StringVar(&tags, "g", "", "Comma separated tags for the todo")
+
+	flag.BoolVar(&strictMode, "require-deadline", false, "Reject new todos without a deadline")
+
+	flag.StringVar(&remind, "remind", "", "Show the todo as due soon this long before its deadline (e.g. 1h)")
+
+	flag.StringVar(&project, "project", "", "Project the todo belongs to")
+
+	flag.StringVar(&repeat, "repeat", "", "Repeat the todo: daily, weekdays, weekly or monthly")
+
+	flag.Var(&subtasks, "subtask", "Subtask for the todo (repeatable)")
+	flag.Var(&attachments, "attach", "File path or reference to attach to the todo (repeatable)")
+	flag.StringVar(&waitingOn, "waiting-on", "", "Mark the todo as waiting on someone else")
+	flag.StringVar(&plan, "plan", "", "Day to work on the todo, apart from its deadline (YYYY-MM-DD, today or tomorrow)")
+	flag.IntVar(&points, "points", 0, "Estimated effort of the todo in points")
+
 	flag.BoolVar(&listMode, "list", false, "List all todos")
 	flag.BoolVar(&listMode, "l", false, "List all todos")
+	flag.StringVar(&searchQuery, "search", "", "Find todos whose title, description, tags, project or note contain QUERY")
+	flag.BoolVar(&inArchive, "archived", false, "With -search, also search the archived todos")
+	flag.BoolVar(&plainMode, "plain", false, "Print the list as plain text without colors, for screen readers and logs")
+	flag.BoolVar(&countMode, "count", false, "Print the total, incomplete, overdue, due today and completed counts")
+	flag.BoolVar(&jsonOutput, "json", false, "With -count, print the counts as a JSON object")
+
+	flag.BoolVar(&thenList, "then-list", false, "Open the list after creating a todo")
+	flag.StringVar(&focus, "focus", "", "Open the list showing only the todos of PROJECT")
+	flag.StringVar(&dayFlag, "day", "", "List the todos due on DAY (YYYY-MM-DD, today or tomorrow), in the list view with -list")
+
+	flag.BoolVar(&reviewMode, "review", false, "Review what was completed today")
+	flag.BoolVar(&statsMode, "stats", false, "Show completion statistics")
+	flag.BoolVar(&randomMode, "random", false, "Suggest a random incomplete todo to work on next")
+	flag.BoolVar(&carryOver, "carryover", false, "Move unfinished todos due before today to the end of today")
+	flag.IntVar(&planNext, "plan-next", 0, "Plan the N most urgent incomplete todos for today")
+	flag.BoolVar(&doneOverdue, "complete-overdue", false, "Complete every overdue todo")
+	flag.StringVar(&snoozeAll, "snooze-overdue", "", "Push every overdue todo's deadline forward by DURATION (e.g. 1d), or to the end of today with eod")
+
+	flag.BoolVar(&doctorMode, "doctor", false, "Check the database for problems")
+	flag.BoolVar(&fixMode, "fix", false, "With -doctor, repair the problems that are safe to fix")
+
+	flag.IntVar(&schedule, "schedule", 0, "Give todos without a deadline one, at most N per business day")
+	flag.BoolVar(&dedupe, "dedupe", false, "Merge incomplete todos with the same title")
+
+	flag.StringVar(&doneID, "done", "", "Complete the todo with this ID, ID prefix or title text")
+	flag.StringVar(&doneAt, "at", "", "When the -done todo was completed (e.g. \"2025-11-15 20:00\")")
+	flag.StringVar(&doneNote, "note", "", "A note on what was done, stored with the -done todo")
+
+	flag.StringVar(&replaceText, "replace", "", "Replace text in all titles and descriptions (-replace OLD NEW)")
+	flag.BoolVar(&dryRun, "dry-run", false, "Preview changes without saving them")
+
+	flag.StringVar(&mergePath, "merge", "", "Import todos and streak from another doit database")
+
+	flag.StringVar(&exportPath, "export-streak", "", "Write the streak as JSON to FILE (- for stdout)")
+	flag.StringVar(&taskwarrior, "export-taskwarrior", "", "Write all todos in Taskwarrior's import format to FILE (- for stdout)")
+	flag.StringVar(&jsonlPath, "export-jsonl", "", "Write all todos to FILE as one JSON object per line (- for stdout)")
+	flag.StringVar(&importPath, "import-streak", "", "Replace the streak with JSON from FILE (- for stdin)")
+	flag.StringVar(&todoImport, "import", "", "Add todos from a JSON array in FILE (- for stdin), keeping their timestamps")
+	flag.StringVar(&csvExport, "export-csv", "", "Write all todos to FILE as CSV (- for stdout)")
+	flag.StringVar(&csvImport, "import-csv", "", "Add todos from CSV in FILE (- for stdin), skipping malformed rows")
+	flag.BoolVar(&csvReplace, "csv-overwrite", false, "With -import-csv, update stored todos with the same ID instead of skipping them")
+
+	flag.StringVar(&timezone, "tz", "", "Timezone for absolute deadlines (e.g. UTC, +02:00, Europe/Berlin)")
+	flag.BoolVar(&midnight, "from-midnight", false, "Count relative deadlines in days or longer from the start of today")
+	flag.BoolVar(&showZone, "show-zone", false, "Show deadlines in the zone they were set in")
+
+	flag.BoolVar(&wrapTitles, "wrap", false, "Wrap long titles in the list instead of truncating them")
+	flag.BoolVar(&bothDates, "both-dates", false, "Show deadlines in the list as the date and the days left")
+
+	flag.BoolVar(&markdown, "markdown", false, "Render descriptions as basic markdown in the list")
+
+	flag.BoolVar(&tableLayout, "table", false, "Show the list as a table with tags and due in columns")
+	flag.BoolVar(&autoExpand, "auto-expand", false, "Expand the selected todo in the list while it is selected")
+	flag.BoolVar(&foldUp, "fold-upcoming", false, "Start the list with the upcoming deadlines folded into a count")
+	flag.BoolVar(&pagedList, "paged", false, "Load the list a page of upcoming deadlines at a time, for very large databases")
+	flag.BoolVar(&emptyToForm, "empty-to-form", false, "Open the new todo form when listing an empty database")
+	flag.BoolVar(&doneFirst, "completed-first", false, "List completed todos above the incomplete ones")
+	flag.IntVar(&warnDays, "warn-days", ui.DefaultWarnDays, "Color todos with at most N days left orange")
+	flag.IntVar(&soonDays, "soon-days", ui.DefaultSoonDays, "Color todos with fewer than N days left red")
+	flag.IntVar(&morningHour, "morning", utils.DefaultMorningHour, "Hour of the day S in the list snoozes todos to tomorrow")
+	flag.IntVar(&staleDays, "stale-days", int(storage.DefaultStaleAfter.Hours()/24), "Mark todos without a deadline untouched for this many days as stale in the list (0 disables)")
+	flag.BoolVar(&staleNudge, "stale-nudge", false, "Report the stale todos when the list opens")
+	flag.IntVar(&confirmOver, "confirm-over", ui.DefaultConfirmThreshold, "Ask to type the count before deleting more than N marked todos")
+	flag.IntVar(&weeklyGoal, "weekly-goal", -1, "Set how many todos to complete a week, 0 to clear the goal")
+	flag.IntVar(&capacity, "capacity", -1, "Set how many points of todos fit in a week, 0 to clear the capacity")
+	flag.StringVar(&weekDay, "week-start", "monday", "Day the week of the weekly goal starts on")
+
+	flag.StringVar(&dbFlag, "db", "", "Path to the database file (overrides $"+DBPathEnv+")")
+	flag.BoolVar(&whereMode, "where", false, "Print the database path and exit")
+	flag.BoolVar(&encryptMode, "encrypt", false, "Encrypt the todos in the database with a passphrase; IDs and deadlines stay readable")
+	flag.BoolVar(&decryptMode, "decrypt", false, "Store the todos in the database unencrypted again")
+	flag.BoolVar(&compactMode, "compact", false, "Shrink the database file by copying it without its free space")
+
+	flag.BoolVar(&verbose, "verbose", false, "Log how long opening, loading, sorting and rendering take to stderr")
 
 	flag.BoolVar(&showHelp, "help", false, "Show help")
 	flag.BoolVar(&showHelp, "h", false, "Show help")
@@ -55,75 +239,595 @@ func main() {
 		os.Exit(0)
 	}
 
+	if whereMode {
+		if err := printWhere(os.Stdout, dbFlag, os.Getenv(DBPathEnv)); err != nil {
+			log.Fatal("Failed to get database path:", err)
+		}
+		return
+	}
+
+	if err := utils.CheckFormatEnv(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, using the default date format\n", err)
+	}
+
+	if morningHour < 1 || morningHour > 23 {
+		fmt.Println("Error: -morning must be an hour from 1 to 23")
+		os.Exit(1)
+	}
+
+	if staleDays < 0 {
+		fmt.Println("Error: -stale-days cannot be negative")
+		os.Exit(1)
+	}
+
+	day, err := utils.ParseWeekday(weekDay)
+	if err != nil {
+		fmt.Println("Error: -week-start:", err)
+		os.Exit(1)
+	}
+	weekStart = day
+
 	dbPath, err := getDBPath()
 	if err != nil {
 		log.Fatal("Failed to get database path:", err)
 	}
 
+	if compactMode {
+		if err := runCompact(os.Stdout, dbPath); err != nil {
+			log.Fatal("Failed to compact the database:", err)
+		}
+		return
+	}
+
+	if verbose {
+		timer = utils.NewTimer(os.Stderr, time.Now)
+	}
+
+	stop := timer.Stage("open")
 	store, err := storage.NewBoltStorage(dbPath)
+	stop()
 	if err != nil {
 		log.Fatal("Failed to initialize storage:", err)
 	}
-	defer store.Close()
+	store.SetTimer(timer)
+	closeStore := shutdown(store)
+	defer closeStore()
+
+	stopSignals := handleSignals(closeStore)
+	defer stopSignals()
+
+	if store.Encrypted() {
+		passphrase, err := readPassphrase("Passphrase: ")
+		if err == nil {
+			err = store.Unlock(passphrase)
+		}
+		if err != nil {
+			closeStore()
+			log.Fatal("Failed to unlock the database:", err)
+		}
+	}
+
+	if encryptMode {
+		if err := runEncrypt(store, readPassphrase); err != nil {
+			log.Fatal("Failed to encrypt the database:", err)
+		}
+		return
+	}
+
+	if decryptMode {
+		if err := store.Decrypt(); err != nil {
+			log.Fatal("Failed to decrypt the database:", err)
+		}
+		fmt.Println("✔ Database decrypted")
+		return
+	}
+
+	if retention, ok := autoArchiveRetention(os.Getenv(AutoArchiveEnv)); ok {
+		if _, err := storage.AutoArchive(store, retention, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to archive old todos: %v\n", err)
+		}
+	}
+
+	if reviewMode {
+		if err := printReview(store, time.Now()); err != nil {
+			log.Fatal("Failed to build review:", err)
+		}
+		return
+	}
+
+	if weeklyGoal >= 0 {
+		if err := runWeeklyGoal(store, weeklyGoal); err != nil {
+			log.Fatal("Failed to set the weekly goal:", err)
+		}
+		return
+	}
+
+	if capacity >= 0 {
+		if err := runCapacity(store, capacity); err != nil {
+			log.Fatal("Failed to set the weekly capacity:", err)
+		}
+		return
+	}
+
+	if statsMode {
+		if err := printStats(store, time.Now()); err != nil {
+			log.Fatal("Failed to build stats:", err)
+		}
+		return
+	}
+
+	if planNext > 0 {
+		if _, err := runPlanNext(store, planNext, time.Now()); err != nil {
+			log.Fatal("Failed to plan todos:", err)
+		}
+		return
+	}
+
+	if carryOver {
+		if err := runCarryOver(store, time.Now()); err != nil {
+			log.Fatal("Failed to carry over todos:", err)
+		}
+		return
+	}
+
+	if doneOverdue {
+		if _, err := runCompleteOverdue(store, time.Now()); err != nil {
+			log.Fatal("Failed to complete overdue todos:", err)
+		}
+		return
+	}
+
+	if snoozeAll != "" {
+		if _, err := runSnoozeOverdue(store, snoozeAll, time.Now()); err != nil {
+			log.Fatal("Failed to snooze overdue todos:", err)
+		}
+		return
+	}
+
+	if doctorMode {
+		if err := runDoctor(store, time.Now(), fixMode); err != nil {
+			log.Fatal("Failed to check the database:", err)
+		}
+		return
+	}
+
+	if schedule > 0 {
+		if err := runSchedule(store, schedule, time.Now(), dryRun); err != nil {
+			log.Fatal("Failed to schedule todos:", err)
+		}
+		return
+	}
+
+	if dedupe {
+		if err := runDedupe(store, dryRun); err != nil {
+			log.Fatal("Failed to merge duplicates:", err)
+		}
+		return
+	}
+
+	if doneID != "" || doneAt != "" || doneNote != "" {
+		if doneID == "" {
+			fmt.Println("Error: -at and -note require -done ID")
+			closeStore()
+			os.Exit(1)
+		}
+		if err := runDone(store, doneID, doneAt, doneNote, time.Now()); err != nil {
+			log.Fatal("Failed to complete todo:", err)
+		}
+		return
+	}
+
+	if randomMode {
+		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+		if err := printRandom(store, rng); err != nil {
+			log.Fatal("Failed to pick a todo:", err)
+		}
+		return
+	}
+
+	if exportPath != "" {
+		if err := withFile(exportPath, os.Stdout, os.Create, func(f *os.File) error {
+			return exportStreak(store, f)
+		}); err != nil {
+			log.Fatal("Failed to export streak:", err)
+		}
+		return
+	}
+
+	if jsonlPath != "" {
+		if err := withFile(jsonlPath, os.Stdout, os.Create, func(f *os.File) error {
+			w := bufio.NewWriter(f)
+			if err := export.ExportJSONL(w, store); err != nil {
+				return err
+			}
+			return w.Flush()
+		}); err != nil {
+			log.Fatal("Failed to export todos:", err)
+		}
+		return
+	}
+
+	if taskwarrior != "" {
+		if err := withFile(taskwarrior, os.Stdout, os.Create, func(f *os.File) error {
+			todos, err := store.GetAllTodos()
+			if err != nil {
+				return err
+			}
+			return export.ExportTaskwarrior(f, todos)
+		}); err != nil {
+			log.Fatal("Failed to export todos:", err)
+		}
+		return
+	}
+
+	if csvExport != "" {
+		if err := withFile(csvExport, os.Stdout, os.Create, func(f *os.File) error {
+			todos, err := store.GetAllTodos()
+			if err != nil {
+				return err
+			}
+			return export.ExportCSV(f, todos)
+		}); err != nil {
+			log.Fatal("Failed to export todos:", err)
+		}
+		return
+	}
+
+	if importPath != "" {
+		if err := withFile(importPath, os.Stdin, os.Open, func(f *os.File) error {
+			return importStreak(store, f)
+		}); err != nil {
+			log.Fatal("Failed to import streak:", err)
+		}
+		fmt.Println("✔ Streak imported")
+		return
+	}
+
+	if todoImport != "" {
+		var count int
+		if err := withFile(todoImport, os.Stdin, os.Open, func(f *os.File) error {
+			var err error
+			count, err = importTodos(store, f)
+			return err
+		}); err != nil {
+			log.Fatal("Failed to import todos:", err)
+		}
+		fmt.Printf("✔ Imported %d todos\n", count)
+		return
+	}
+
+	if csvImport != "" {
+		if err := withFile(csvImport, os.Stdin, os.Open, func(f *os.File) error {
+			return importCSV(os.Stdout, store, f, csvReplace)
+		}); err != nil {
+			log.Fatal("Failed to import todos:", err)
+		}
+		return
+	}
+
+	if mergePath != "" {
+		if err := mergeDatabase(store, dbPath, mergePath, readPassphrase); err != nil {
+			log.Fatal("Failed to merge database:", err)
+		}
+		return
+	}
+
+	if replaceText != "" {
+		if flag.NArg() < 1 {
+			fmt.Println("Error: -replace requires the replacement text (-replace OLD NEW)")
+			closeStore()
+			os.Exit(1)
+		}
+		replacement := flag.Arg(0)
+		// Allow flags such as -dry-run after the replacement text
+		flag.CommandLine.Parse(flag.Args()[1:])
+
+		if err := runReplace(store, replaceText, replacement, dryRun); err != nil {
+			log.Fatal("Failed to replace text:", err)
+		}
+		return
+	}
+
+	if dayFlag != "" {
+		day, err := utils.ParseDay(dayFlag, time.Now())
+		if err != nil {
+			fmt.Println("Error:", err)
+			closeStore()
+			os.Exit(1)
+		}
+		if listMode {
+			dueDay = day
+			runList(store)
+			return
+		}
+		if err := printDay(store, day); err != nil {
+			log.Fatal("Failed to list todos:", err)
+		}
+		return
+	}
+
+	if searchQuery != "" {
+		scope := storage.ScopeActive
+		if inArchive {
+			scope = storage.ScopeAll
+		}
+		if err := printSearch(store, searchQuery, scope); err != nil {
+			log.Fatal("Failed to search todos:", err)
+		}
+		return
+	}
 
-	if listMode {
-		p := tea.NewProgram(ui.NewListModel(store), tea.WithAltScreen())
-		if _, err := p.Run(); err != nil {
-			log.Fatal("Error running list view:", err)
+	if countMode {
+		if err := printCounts(os.Stdout, store, time.Now(), jsonOutput); err != nil {
+			log.Fatal("Failed to count todos:", err)
 		}
 		return
 	}
 
+	if plainMode {
+		if err := printList(os.Stdout, store, focus); err != nil {
+			log.Fatal("Failed to list todos:", err)
+		}
+		return
+	}
+
+	if listMode || focus != "" {
+		runList(store)
+		return
+	}
+
 	if title == "" && description == "" {
-		p := tea.NewProgram(ui.NewFormModel(store), tea.WithAltScreen())
-		if _, err := p.Run(); err != nil {
+		form := ui.NewFormModel(store)
+		form.SetRequireDeadline(strictMode)
+		p := tea.NewProgram(form, tea.WithAltScreen())
+		if err := runProgram(p); err != nil {
 			log.Fatal("Error running form view:", err)
 		}
 		return
 	}
 
-	if title == "" || description == "" {
-		fmt.Println("Error: Both title (-t) and description (-d) are required")
-		printHelp()
+	launchList, err := run(store)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		if errors.Is(err, errMissingFields) {
+			printHelp()
+		}
+		closeStore()
 		os.Exit(1)
 	}
 
-	if len(title) > MaxTitleLength {
-		fmt.Printf("Error: Title exceeds maximum length of %d characters (current: %d)\n", MaxTitleLength, len(title))
-		os.Exit(1)
+	if launchList {
+		runList(store)
 	}
+}
 
-	if len(description) > MaxDescriptionLength {
-		fmt.Printf("Error: Description exceeds maximum length of %d characters (current: %d)\n", MaxDescriptionLength, len(description))
-		os.Exit(1)
+// flagLocation returns the zone given with -tz, or local time
+func flagLocation() (*time.Location, error) {
+	if timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := utils.ParseLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %w", err)
+	}
+	return loc, nil
+}
+
+var errMissingFields = errors.New("both title (-t) and description (-d) are required")
+
+// run creates a todo from the command-line flags, reporting whether the list
+// view should be launched afterwards
+func run(store storage.Storage) (bool, error) {
+	if title == "" || description == "" {
+		return false, errMissingFields
+	}
+	// Like the form, a title of only spaces is no title
+	title, description := strings.TrimSpace(title), strings.TrimSpace(description)
+	if title == "" {
+		return false, fmt.Errorf("title is required")
+	}
+	if description == "" {
+		return false, fmt.Errorf("description is required")
+	}
+
+	if len(title) > models.MaxTitleLength {
+		return false, fmt.Errorf("title exceeds maximum length of %d characters (current: %d)", models.MaxTitleLength, len(title))
+	}
+
+	if len(description) > models.MaxDescriptionLength {
+		return false, fmt.Errorf("description exceeds maximum length of %d characters (current: %d)", models.MaxDescriptionLength, len(description))
+	}
+
+	loc, err := flagLocation()
+	if err != nil {
+		return false, err
 	}
 
 	var deadlineTime *time.Time
 	if deadline != "" {
-		parsed, err := utils.ParseDeadline(deadline)
+		parsed, err := utils.ParseDeadlineWith(deadline, utils.DeadlineOptions{Location: loc, FromMidnight: midnight})
 		if err != nil {
-			log.Fatal("Invalid deadline format: ", err)
+			return false, err
 		}
 		deadlineTime = parsed
 	}
+	if deadlineTime == nil && strictMode {
+		return false, ui.ErrDeadlineRequired
+	}
+
+	recurrence, err := models.ParseRecurrence(repeat)
+	if err != nil {
+		return false, err
+	}
+	if recurrence != models.RecurNone && deadlineTime == nil {
+		return false, fmt.Errorf("a deadline (-n) is required for repeating todos")
+	}
+
+	var reminderBefore time.Duration
+	if remind != "" {
+		if deadlineTime == nil {
+			return false, fmt.Errorf("a deadline (-n) is required for reminders")
+		}
+		reminderBefore, err = utils.ParseLeadTime(remind)
+		if err != nil {
+			return false, fmt.Errorf("invalid reminder: %w", err)
+		}
+	}
+
+	if points < 0 {
+		return false, fmt.Errorf("points cannot be negative")
+	}
+
+	var plannedFor *time.Time
+	if plan != "" {
+		day, err := utils.ParseDay(plan, time.Now())
+		if err != nil {
+			return false, err
+		}
+		plannedFor = &day
+	}
 
 	todo := models.Todo{
-		ID:          generateID(),
-		Title:       title,
-		Description: description,
-		Deadline:    deadlineTime,
-		CreatedAt:   time.Now(),
-		Completed:   false,
+		ID:             models.NewID(),
+		Title:          title,
+		Description:    description,
+		Deadline:       deadlineTime,
+		Tags:           utils.ParseTags(tags),
+		Project:        strings.TrimSpace(project),
+		Subtasks:       parseSubtasks(subtasks),
+		Attachments:    attachments,
+		Recurrence:     recurrence,
+		Waiting:        waitingOn != "",
+		WaitingOn:      waitingOn,
+		CreatedAt:      time.Now(),
+		Completed:      false,
+		ReminderBefore: reminderBefore,
+		PlannedFor:     plannedFor,
+		Points:         points,
 	}
 
 	if err := store.SaveTodo(&todo); err != nil {
-		log.Fatal("Failed to save todo:", err)
+		return false, fmt.Errorf("failed to save todo: %w", err)
 	}
 
 	fmt.Printf("✔ Todo created successfully!\n")
 	fmt.Printf("Title: %s\n", todo.Title)
+	fmt.Printf("ID: %s\n", todo.ID)
+	if len(todo.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(todo.Tags, ", "))
+	}
+	if todo.Project != "" {
+		fmt.Printf("Project: %s\n", todo.Project)
+	}
 	if deadlineTime != nil {
-		fmt.Printf("Deadline: %s\n", deadlineTime.Format("2006-01-02 15:04"))
+		fmt.Printf("Deadline: %s\n", utils.FormatTime(deadlineTime.Local(), utils.FormatOptsFromEnv()))
+	}
+	if plannedFor != nil {
+		fmt.Printf("Planned: %s\n", utils.FormatDate(*plannedFor, utils.FormatOptsFromEnv()))
+	}
+	if todo.Points > 0 {
+		fmt.Printf("Points: %d\n", todo.Points)
+	}
+
+	return thenList, nil
+}
+
+// runList launches the list view
+func runList(store storage.Storage) {
+	options := ui.ListOptions{
+		FitMode:         ui.FitTruncate,
+		Markdown:        markdown,
+		ShowZone:        showZone,
+		Table:           tableLayout,
+		AutoExpand:      autoExpand,
+		FoldUpcoming:    foldUp,
+		Paged:           pagedList,
+		EmptyToForm:     emptyToForm,
+		RequireDeadline: strictMode,
+		WarnDays:        warnDays,
+		SoonDays:        soonDays,
+		MorningHour:     morningHour,
+		StaleAfter:      time.Duration(staleDays) * 24 * time.Hour,
+		StaleNudge:      staleNudge,
+		WeekStart:       weekStart,
+		ConfirmOver:     confirmOver,
+		Focus:           strings.TrimSpace(focus),
+		Day:             dueDay,
+		CompletedFirst:  doneFirst,
+		Timer:           timer,
+	}
+	if wrapTitles {
+		options.FitMode = ui.FitWrap
+	}
+	if bothDates {
+		options.DeadlineMode = ui.DeadlineBoth
+	}
+
+	p := tea.NewProgram(ui.NewListModel(store, options), tea.WithAltScreen())
+	if err := runProgram(p); err != nil {
+		log.Fatal("Error running list view:", err)
+	}
+}
+
+var (
+	programMu     sync.Mutex
+	activeProgram *tea.Program
+)
+
+// runProgram runs a bubbletea program, registering it so that a signal quits
+// it and restores the terminal instead of exiting underneath it. Being
+// interrupted by a signal is not an error.
+func runProgram(p *tea.Program) error {
+	programMu.Lock()
+	activeProgram = p
+	programMu.Unlock()
+
+	defer func() {
+		programMu.Lock()
+		activeProgram = nil
+		programMu.Unlock()
+	}()
+
+	_, err := p.Run()
+	if errors.Is(err, tea.ErrInterrupted) || errors.Is(err, tea.ErrProgramKilled) {
+		return nil
+	}
+	return err
+}
+
+// shutdown returns a function that closes the store exactly once, however
+// many exit paths call it. Close waits for a running transaction, so a
+// signal never closes the database in the middle of a write.
+func shutdown(store storage.Storage) func() error {
+	return sync.OnceValue(store.Close)
+}
+
+// handleSignals closes the store and exits on SIGINT or SIGTERM. While a
+// bubbletea program is running it is quit instead, so the terminal is
+// restored and main closes the store on its way out.
+func handleSignals(closeStore func() error) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		for range signals {
+			programMu.Lock()
+			p := activeProgram
+			programMu.Unlock()
+
+			if p != nil {
+				p.Quit()
+				continue
+			}
+
+			closeStore()
+			os.Exit(1)
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(signals)
 	}
 }
 
@@ -135,8 +839,8 @@ func printHelp() {
 	fmt.Println("  doit -t \"Title\" -d \"Description\" [-n DEADLINE]")
 	fmt.Println()
 	fmt.Println("Options:")
-	fmt.Printf("  -t string    Title of the todo (required, max %d chars)\n", MaxTitleLength)
-	fmt.Printf("  -d string    Description of the todo (required, max %d chars)\n", MaxDescriptionLength)
+	fmt.Printf("  -t string    Title of the todo (required, max %d chars)\n", models.MaxTitleLength)
+	fmt.Printf("  -d string    Description of the todo (required, max %d chars)\n", models.MaxDescriptionLength)
 	fmt.Println("  -n string    Deadline for todo")
 
 	deadlineHelp := utils.FormatDeadlineHelp()
@@ -146,7 +850,76 @@ func printHelp() {
 			fmt.Println("              ", line)
 		}
 	}
+	fmt.Println("  -require-deadline  Reject new todos without a deadline")
+	fmt.Println("  -repeat      Repeat the todo: daily, weekdays, weekly or monthly")
+	fmt.Println("  -remind      Show the todo as due soon this long before its deadline (e.g. 1h, 3d)")
+	fmt.Println("  -subtask     Subtask for the todo (repeatable)")
+	fmt.Println("  -waiting-on WHO  Mark the todo as waiting on someone else")
+	fmt.Println("  -plan DAY    Plan to work on the todo on DAY (e.g. today), whatever its deadline")
+	fmt.Println("  -points N    Estimated effort of the todo, counted against the weekly capacity")
+	fmt.Println("  -attach      File path or reference to attach to the todo (repeatable)")
+	fmt.Println("  -tz string   Timezone for absolute deadlines (e.g. UTC, +02:00, Europe/Berlin)")
+	fmt.Println("  -from-midnight  Count deadlines like 2d from the start of today instead of from now")
+	fmt.Println("  -tags, -g    Comma separated tags for the todo (e.g. \"work,urgent\")")
+	fmt.Println("  -project NAME  Project the todo belongs to")
 	fmt.Println("  -list, -l    List all todos")
+	fmt.Println("  -focus PROJECT  Open the list showing only the todos of PROJECT")
+	fmt.Println("  -search QUERY  Find todos by their title, description, tags, project or note")
+	fmt.Println("  -archived    With -search, also search the archived todos")
+	fmt.Println("  -plain       Print the list as plain text with no colors or symbols, for screen readers and logs")
+	fmt.Println("  -count       Print how many todos there are, incomplete, overdue, due today and completed")
+	fmt.Println("  -json        With -count, print the counts as a JSON object for dashboards and prompts")
+	fmt.Println("  -day DAY     List the todos due on DAY (YYYY-MM-DD, today or tomorrow); add -list for the list view")
+	fmt.Println("  -wrap        Wrap long titles in the list instead of truncating them")
+	fmt.Println("  -both-dates  Show deadlines as the date and the days left, e.g. \"Jan 2, 3:04 PM (in 5 days)\"")
+	fmt.Println("  -show-zone   Show deadlines in the zone they were set in instead of local time")
+	fmt.Println("  -markdown    Render descriptions as basic markdown in the list")
+	fmt.Println("  -table       Show the list as a table with tags and right-aligned due in columns")
+	fmt.Println("  -auto-expand  Expand the selected todo while it is selected, Space still pins todos open")
+	fmt.Println("  -fold-upcoming  Start the list with the upcoming deadlines folded into a count (U unfolds them)")
+	fmt.Println("  -paged       Load the list one page of upcoming deadlines at a time instead of every todo, for very large databases")
+	fmt.Println("  -empty-to-form  Open the new todo form when the list would be empty")
+	fmt.Println("  -completed-first  List completed todos above the incomplete ones")
+	fmt.Println("  -warn-days N  Color todos with at most N days left orange (default 3)")
+	fmt.Println("  -soon-days N  Color todos with fewer than N days left red (default 1)")
+	fmt.Println("  -morning HOUR  Hour S in the list snoozes todos to tomorrow (default 9)")
+	fmt.Println("  -stale-days N  Mark todos without a deadline untouched for N days with 💤 in the list (default 30, 0 disables)")
+	fmt.Println("  -stale-nudge  Report how many todos are stale when the list opens")
+	fmt.Println("  -confirm-over N  Ask to type the count before deleting more than N marked todos (default 5)")
+	fmt.Println("  -weekly-goal N  Aim to complete N todos a week, shown in the list and -stats (0 clears it)")
+	fmt.Println("  -capacity N  Take on at most N points of todos due a week, checked in -stats (0 clears it)")
+	fmt.Println("  -week-start DAY  Day the week of the weekly goal starts on (default monday)")
+	fmt.Println("  -then-list   Open the list after creating a todo")
+	fmt.Println("  -replace OLD NEW  Replace text in all titles and descriptions")
+	fmt.Println("  -merge FILE  Import todos and streak from another doit database")
+	fmt.Println("  -dry-run     Preview changes without saving them")
+	fmt.Println("  -export-streak FILE  Write the streak as JSON (- for stdout)")
+	fmt.Println("  -import-streak FILE  Replace the streak with exported JSON (- for stdin)")
+	fmt.Println("  -export-taskwarrior FILE  Write all todos for `task import` (- for stdout)")
+	fmt.Println("  -export-jsonl FILE  Write all todos as one JSON object per line (- for stdout), for huge databases")
+	fmt.Println("  -import FILE  Add todos from a JSON array (- for stdin), keeping created_at and completed_at")
+	fmt.Println("  -export-csv FILE  Write all todos as CSV (- for stdout)")
+	fmt.Println("  -import-csv FILE  Add todos from CSV (- for stdin), skipping and reporting malformed rows")
+	fmt.Println("  -csv-overwrite  With -import-csv, update todos that already exist instead of skipping them")
+	fmt.Println("  -review      Review what was completed today")
+	fmt.Println("  -stats       Show completion statistics")
+	fmt.Println("  -random      Suggest a random incomplete todo to work on next")
+	fmt.Println("  -done ID     Complete the todo with this ID, a unique start of it or part of its title")
+	fmt.Println("  -at TIME     With -done, when it was completed (e.g. \"2025-11-15 20:00\")")
+	fmt.Println("  -note TEXT   With -done, a note on what was done or the outcome")
+	fmt.Println("  -doctor      Check the database for problems (add -fix to repair the safe ones)")
+	fmt.Println("  -carryover   Move unfinished todos due before today to the end of today")
+	fmt.Println("  -plan-next N  Plan the N most urgent incomplete todos for today, a short list for the day")
+	fmt.Println("  -complete-overdue  Complete every overdue todo, for clearing a backlog that is done")
+	fmt.Println("  -snooze-overdue DURATION  Push every overdue todo's deadline forward (e.g. 1d), or to the end of today with eod")
+	fmt.Println("  -schedule N  Spread todos without a deadline over the coming business days, N per day")
+	fmt.Println("  -dedupe      Merge incomplete todos with the same title (preview with -dry-run)")
+	fmt.Println("  -db FILE     Database file to use (default $DOIT_DB, then ~/.local/share/doit/doit.db)")
+	fmt.Println("  -verbose     Log how long opening, loading, sorting and rendering take to stderr")
+	fmt.Println("  -where       Print the database path and exit")
+	fmt.Println("  -encrypt     Encrypt the todos with a passphrase, asked for on every start (IDs and deadlines stay readable)")
+	fmt.Println("  -decrypt     Store the todos unencrypted again")
+	fmt.Println("  -compact     Shrink the database file after many deletes (close other doit windows first)")
 	fmt.Println("  -help, -h    Show this help message")
 	fmt.Println()
 	fmt.Println("Interactive Mode:")
@@ -158,21 +931,933 @@ func printHelp() {
 	fmt.Println("  doit -t \"Project\" -d \"Milestone 1\" -n \"1w 2d\"")
 }
 
-func generateID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
+// runCarryOver moves overdue todos from previous days to the end of today
+func runCarryOver(store storage.Storage, now time.Time) error {
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return err
+	}
+
+	carried := storage.CarryOver(todos, now)
+	if err := store.UpdateTodos(carried); err != nil {
+		return err
+	}
+	for _, todo := range carried {
+		fmt.Printf("  ↷ %s\n", todo.Title)
+	}
+
+	fmt.Printf("✔ Carried over %d todos to today\n", len(carried))
+	return nil
 }
 
-func getDBPath() (string, error) {
-	home, err := os.UserHomeDir()
+// runPlanNext plans the n most urgent incomplete todos for today and returns
+// the ones it planned
+func runPlanNext(store storage.Storage, n int, now time.Time) ([]*models.Todo, error) {
+	todos, err := store.GetAllTodos()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
 
-	dataDir := filepath.Join(home, ".local", "share", "doit")
-
-	if err := os.MkdirAll(dataDir, 0o755); err != nil {
-		return "", fmt.Errorf("failed to create data directory: %w", err)
+	planned := storage.PlanNext(todos, n, now)
+	if err := store.UpdateTodos(planned); err != nil {
+		return nil, err
+	}
+	for _, todo := range planned {
+		fmt.Printf("  → %s\n", todo.Title)
 	}
 
-	return filepath.Join(dataDir, "doit.db"), nil
+	fmt.Printf("✔ Planned %d todos for today\n", len(planned))
+	return planned, nil
+}
+
+// runCompleteOverdue completes every overdue todo in one batch, scheduling the
+// next occurrence of repeating ones, and returns how many were completed
+func runCompleteOverdue(store storage.Storage, now time.Time) (int, error) {
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return 0, err
+	}
+
+	overdue := storage.OverdueTodos(todos)
+	if err := store.CompleteTodos(overdue, now); err != nil {
+		return 0, err
+	}
+
+	var next []*models.Todo
+	for _, todo := range overdue {
+		fmt.Printf("  ✔ %s\n", todo.Title)
+		if occurrence := models.NextFutureOccurrence(todo, now); occurrence != nil {
+			occurrence.ID = models.NewID()
+			next = append(next, occurrence)
+		}
+	}
+	if err := store.SaveTodos(next); err != nil {
+		return 0, fmt.Errorf("failed to schedule the next occurrence: %w", err)
+	}
+
+	fmt.Printf("✔ Completed %d overdue todos\n", len(overdue))
+	return len(overdue), nil
+}
+
+// runSnoozeOverdue moves the deadline of every overdue todo to by from now,
+// or to the end of today when by is "eod", in one batch and returns how many
+// were moved
+func runSnoozeOverdue(store storage.Storage, by string, now time.Time) (int, error) {
+	var duration time.Duration
+	if by != "eod" {
+		d, err := utils.ParseLeadTime(by)
+		if err != nil {
+			return 0, err
+		}
+		duration = d
+	}
+	deadline := utils.SnoozeUntil(now, duration)
+
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return 0, err
+	}
+
+	overdue := storage.OverdueTodos(todos)
+	if err := store.RescheduleTodos(overdue, deadline); err != nil {
+		return 0, err
+	}
+	for _, todo := range overdue {
+		fmt.Printf("  ↷ %s\n", todo.Title)
+	}
+
+	fmt.Printf("✔ Snoozed %d overdue todos until %s\n", len(overdue), utils.FormatTime(deadline, utils.FormatOptsFromEnv()))
+	return len(overdue), nil
+}
+
+// runDoctor reports problems in the database, such as unreadable records or
+// todos created while the system clock was wrong. With fix set the safe ones
+// are repaired and the rest are reported.
+func runDoctor(store *storage.BoltStorage, now time.Time, fix bool) error {
+	problems, err := store.Diagnose(now)
+	if err != nil {
+		return err
+	}
+	if len(problems) == 0 {
+		fmt.Println("✔ No problems found")
+		return nil
+	}
+
+	if fix {
+		remaining, err := store.Repair(now)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✔ Fixed %d problems\n", len(problems)-len(remaining))
+		if len(remaining) == 0 {
+			return nil
+		}
+		problems = remaining
+	}
+
+	fixable := 0
+	fmt.Printf("⚠ %d problems found\n", len(problems))
+	for _, problem := range problems {
+		marker := " "
+		if problem.Fixable() {
+			marker = "*"
+			fixable++
+		}
+		if problem.ID != "" {
+			fmt.Printf(" %s %s (ID %s)\n", marker, problem.Message, problem.ID)
+		} else {
+			fmt.Printf(" %s %s\n", marker, problem.Message)
+		}
+	}
+	if fixable > 0 && !fix {
+		fmt.Printf("Run with -fix to repair the %d marked with *\n", fixable)
+	}
+	return nil
+}
+
+// runSchedule gives the incomplete todos without a deadline one, oldest first,
+// spreading them over the business days from tomorrow so that no day has more
+// than perDay todos due. Nothing is saved when dryRun is set.
+func runSchedule(store storage.Storage, perDay int, now time.Time, dryRun bool) error {
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return err
+	}
+
+	noDeadline := storage.GetTodosWithoutDeadline(todos)
+	slices.SortStableFunc(noDeadline, func(a, b *models.Todo) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	deadlines := storage.DistributeDeadlinesAround(noDeadline, todos, perDay, now.AddDate(0, 0, 1))
+
+	if dryRun {
+		fmt.Printf("Would schedule %d todos:\n", len(noDeadline))
+	}
+	opts := utils.FormatOptsFromEnv()
+	for _, todo := range noDeadline {
+		fmt.Printf("  %s → %s\n", todo.Title, utils.FormatTime(*deadlines[todo.ID], opts))
+	}
+
+	if !dryRun {
+		for _, todo := range noDeadline {
+			todo.Deadline = deadlines[todo.ID]
+		}
+		if err := store.UpdateTodos(noDeadline); err != nil {
+			return err
+		}
+		fmt.Printf("✔ Scheduled %d todos\n", len(noDeadline))
+	}
+	return nil
+}
+
+// runDedupe merges incomplete todos with the same title, only listing the
+// merges when dryRun is set
+func runDedupe(store *storage.BoltStorage, dryRun bool) error {
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return err
+	}
+
+	merges, errs := storage.MergeDuplicates(todos)
+	removed := 0
+	for _, merge := range merges {
+		removed += len(merge.Removed)
+	}
+
+	if dryRun {
+		fmt.Printf("Would merge %d duplicates:\n", removed)
+	}
+	for _, merge := range merges {
+		fmt.Printf("  %s (%d todos)\n", merge.Kept.Title, len(merge.Removed)+1)
+	}
+	for _, err := range errs {
+		fmt.Printf("  ✘ Skipped %v\n", err)
+	}
+	if dryRun {
+		return nil
+	}
+
+	if err := store.ApplyMerges(merges); err != nil {
+		return err
+	}
+	fmt.Printf("✔ Merged %d duplicates", removed)
+	if len(errs) > 0 {
+		fmt.Printf(", skipped %d titles", len(errs))
+	}
+	fmt.Println()
+	return nil
+}
+
+// printRandom prints a randomly suggested todo to work on next
+func printRandom(store storage.Storage, rng *rand.Rand) error {
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return err
+	}
+
+	todo := storage.SuggestNext(todos, rng)
+	if todo == nil {
+		fmt.Println("Nothing left to do 🎉")
+		return nil
+	}
+
+	fmt.Printf("Next up: %s\n", todo.Title)
+	if todo.Description != "" {
+		fmt.Printf("  %s\n", todo.Description)
+	}
+	if todo.Deadline != nil {
+		fmt.Printf("  Due: %s\n", utils.FormatTime(todo.Deadline.Local(), utils.FormatOptsFromEnv()))
+	}
+	return nil
+}
+
+// runDone completes the todo that ref resolves to (its ID, a unique prefix of
+// it or part of its title), at the time given by at when set (for logging
+// work done earlier) or now otherwise, storing note as its completion note.
+// A repeating todo schedules its next occurrence.
+func runDone(store storage.Storage, ref, at, note string, now time.Time) error {
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return err
+	}
+	todo, err := storage.ResolveTodoRef(todos, ref)
+	if err != nil {
+		return err
+	}
+	if todo.Completed {
+		return fmt.Errorf("%q is already completed", todo.Title)
+	}
+
+	completedAt := now
+	if at != "" {
+		loc, err := flagLocation()
+		if err != nil {
+			return err
+		}
+		completedAt, err = utils.ParseCompletionTime(at, loc, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	todo.MarkCompleteAt(completedAt)
+	todo.CompletionNote = note
+	if err := store.UpdateTodo(todo); err != nil {
+		return err
+	}
+	fmt.Printf("✔ Completed %s (%s)\n", todo.Title, utils.FormatTime(completedAt.Local(), utils.FormatOptsFromEnv()))
+
+	if next := models.NextFutureOccurrence(todo, now); next != nil {
+		next.ID = models.NewID()
+		if err := store.SaveTodo(next); err != nil {
+			return fmt.Errorf("failed to schedule the next occurrence: %w", err)
+		}
+	}
+	return nil
+}
+
+// weekBarWidth caps the bars of the week's completion trend in -stats
+const weekBarWidth = 20
+
+// printStats prints the streak, today's burndown, the completions of the past
+// week and the completed todos per tag, most completed first
+func printStats(store storage.Storage, now time.Time) error {
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return err
+	}
+
+	streak, err := store.GetStreak()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Completed: %d all time | Streak: %d days (max %d)\n",
+		streak.TotalCompleted, streak.CurrentStreak, streak.MaxStreak)
+	if done, goal := storage.WeeklyProgress(streak, now, weekStart); goal > 0 {
+		fmt.Printf("Weekly goal: %d/%d this week\n", done, goal)
+	}
+	if streak.WeeklyCapacity > 0 {
+		committed := storage.WeeklyCommittedPoints(todos, now)
+		fmt.Printf("Committed: %d/%d points due this week\n", committed, streak.WeeklyCapacity)
+		if storage.Overcommitted(committed, streak.WeeklyCapacity) {
+			fmt.Printf("⚠ Over capacity by %d points\n", committed-streak.WeeklyCapacity)
+		}
+	}
+	fmt.Println()
+
+	done, remaining := storage.TodayBurndown(todos, now)
+	if done+remaining == 0 {
+		fmt.Println("Today: nothing due")
+	} else {
+		fmt.Printf("Today: %s %d done, %d remaining\n",
+			strings.Repeat("■", done)+strings.Repeat("□", remaining), done, remaining)
+	}
+	fmt.Println()
+
+	fmt.Println("This week:")
+	for _, day := range storage.WeekTrend(streak, now) {
+		bar := min(day.Count, weekBarWidth)
+		fmt.Printf("  %s %s%s %d\n", day.Day.Format("Mon"),
+			strings.Repeat("█", bar), strings.Repeat(" ", weekBarWidth-bar), day.Count)
+	}
+	fmt.Println()
+
+	ranked := storage.RankTagCounts(storage.CompletionsByTag(todos))
+	if len(ranked) == 0 {
+		fmt.Println("No completed todos yet.")
+		return nil
+	}
+
+	width := 0
+	for _, entry := range ranked {
+		width = max(width, len(entry.Tag))
+	}
+
+	fmt.Println("Completed by tag:")
+	for i, entry := range ranked {
+		fmt.Printf("  %2d. %-*s %d\n", i+1, width, entry.Tag, entry.Count)
+	}
+	return nil
+}
+
+// runWeeklyGoal stores how many todos to complete a week, clearing the goal
+// for 0
+func runWeeklyGoal(store storage.Storage, goal int) error {
+	streak, err := store.GetStreak()
+	if err != nil {
+		return err
+	}
+	streak.WeeklyGoal = goal
+	if err := store.UpdateStreak(streak); err != nil {
+		return err
+	}
+	if goal == 0 {
+		fmt.Println("✔ Weekly goal cleared")
+	} else {
+		fmt.Printf("✔ Weekly goal set to %d todos\n", goal)
+	}
+	return nil
+}
+
+// runCapacity stores how many points of todos fit in a week, clearing the
+// capacity when it is 0
+func runCapacity(store storage.Storage, capacity int) error {
+	streak, err := store.GetStreak()
+	if err != nil {
+		return err
+	}
+	streak.WeeklyCapacity = capacity
+	if err := store.UpdateStreak(streak); err != nil {
+		return err
+	}
+	if capacity == 0 {
+		fmt.Println("✔ Weekly capacity cleared")
+	} else {
+		fmt.Printf("✔ Weekly capacity set to %d points\n", capacity)
+	}
+	return nil
+}
+
+// printCounts prints the todo counts as one line of text, or as a JSON
+// object when asJSON is set
+func printCounts(w io.Writer, store storage.Storage, now time.Time, asJSON bool) error {
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return err
+	}
+
+	summary := storage.SummarizeTodos(todos, now)
+	if asJSON {
+		return json.NewEncoder(w).Encode(summary)
+	}
+	_, err = fmt.Fprintf(w, "Total: %d | Incomplete: %d | Overdue: %d | Due today: %d | Completed: %d\n",
+		summary.Total, summary.Incomplete, summary.Overdue, summary.DueToday, summary.Completed)
+	return err
+}
+
+// printList prints the list once as plain text, with the todos of project
+// only when it is set
+func printList(w io.Writer, store storage.Storage, project string) error {
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return err
+	}
+	if project != "" {
+		todos = slices.DeleteFunc(todos, func(todo *models.Todo) bool {
+			return !strings.EqualFold(todo.Project, project)
+		})
+	}
+	_, err = io.WriteString(w, ui.RenderList(todos, ui.NewRenderer(true), time.Now()))
+	return err
+}
+
+// printDay prints the agenda of the todos due on day, earliest first
+func printDay(store storage.Storage, day time.Time) error {
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return err
+	}
+
+	opts := utils.FormatOptsFromEnv()
+	due := storage.TodosOnDay(todos, day)
+	if len(due) == 0 {
+		fmt.Printf("Nothing due on %s.\n", utils.FormatDate(day, opts))
+		return nil
+	}
+	slices.SortStableFunc(due, func(a, b *models.Todo) int {
+		return a.Deadline.Compare(*b.Deadline)
+	})
+
+	fmt.Printf("Due %s (%d):\n", utils.FormatDate(day, opts), len(due))
+	for _, todo := range due {
+		checkbox := "[ ]"
+		if todo.Completed {
+			checkbox = "[✔]"
+		}
+		fmt.Printf("  %s %8s  %s\n", checkbox, utils.FormatClock(todo.Deadline.Local(), opts), todo.Title)
+	}
+	return nil
+}
+
+// printReview prints the end-of-day review of today's completions
+func printReview(store storage.Storage, now time.Time) error {
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return err
+	}
+
+	streak, err := store.GetStreak()
+	if err != nil {
+		return err
+	}
+
+	completed := storage.TodaysCompletions(todos, now)
+
+	fmt.Printf("Review for %s\n", utils.FormatDate(now, utils.FormatOptsFromEnv()))
+	fmt.Println()
+
+	if len(completed) == 0 {
+		fmt.Println("Nothing completed today.")
+	} else {
+		fmt.Printf("Completed today (%d):\n", len(completed))
+		for _, todo := range completed {
+			fmt.Printf("  ✔ %s\n", todo.Title)
+			if todo.CompletionNote != "" {
+				fmt.Printf("    %s\n", strings.ReplaceAll(todo.CompletionNote, "\n", "\n    "))
+			}
+		}
+	}
+	fmt.Println()
+
+	if streak.CurrentStreak > 0 {
+		fmt.Printf("Streak: %d days (max %d) | %d completed today | %d all time\n",
+			streak.CurrentStreak, streak.MaxStreak, streak.DailyCompletions[now.Format("2006-01-02")], streak.TotalCompleted)
+	}
+	fmt.Println(reviewMessage(len(completed)))
+	fmt.Println()
+
+	tomorrow := now.AddDate(0, 0, 1)
+	dueTomorrow := 0
+	for _, todo := range todos {
+		if !todo.Completed && todo.Deadline != nil && todo.Deadline.Local().Format("2006-01-02") == tomorrow.Format("2006-01-02") {
+			dueTomorrow++
+		}
+	}
+	fmt.Printf("Plan tomorrow: %d todos due. Add more with:\n", dueTomorrow)
+	fmt.Println("  doit -t \"Title\" -d \"Description\" -n \"tomorrow 9am\"")
+
+	return nil
+}
+
+// reviewMessage returns a motivational message for the number of todos
+// completed today
+func reviewMessage(completed int) string {
+	switch {
+	case completed == 0:
+		return "Tomorrow is a fresh start - pick one small thing to finish."
+	case completed < 3:
+		return "Good progress, every finished task counts."
+	case completed < 6:
+		return "Great work today, keep the momentum going!"
+	default:
+		return "Outstanding day - you crushed it!"
+	}
+}
+
+// printSearch prints the todos in scope matching query, labeling the ones
+// found in the archive
+func printSearch(store *storage.BoltStorage, query string, scope storage.Scope) error {
+	results, err := store.SearchAll(query, scope)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Printf("No todos match %q.\n", query)
+		return nil
+	}
+
+	fmt.Printf("Matching %q (%d):\n", query, len(results))
+	for _, result := range results {
+		checkbox := "[ ]"
+		if result.Todo.Completed {
+			checkbox = "[✔]"
+		}
+		source := ""
+		if result.Source == storage.ScopeArchive {
+			source = " (archived)"
+		}
+		fmt.Printf("  %s %s%s  %s\n", checkbox, result.Todo.Title, source, result.Todo.ID)
+	}
+	return nil
+}
+
+// runReplace replaces text across all todos, only previewing the affected
+// todos when dryRun is set
+func runReplace(store storage.Storage, oldText, newText string, dryRun bool) error {
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return err
+	}
+
+	changed, overflowed := storage.ReplaceInTodos(todos, oldText, newText)
+
+	for _, todo := range overflowed {
+		fmt.Printf("Skipped (exceeds character limit): %s\n", todo.Title)
+	}
+
+	if dryRun {
+		fmt.Printf("Would update %d todos:\n", len(changed))
+		for _, todo := range changed {
+			fmt.Printf("  %s\n", todo.Title)
+		}
+		return nil
+	}
+
+	if err := store.UpdateTodos(changed); err != nil {
+		return err
+	}
+
+	fmt.Printf("✔ Updated %d todos\n", len(changed))
+	return nil
+}
+
+// withFile opens path with open and passes it to fn, or passes std when path
+// is "-"
+func withFile(path string, std *os.File, open func(string) (*os.File, error), fn func(*os.File) error) error {
+	if path == "-" {
+		return fn(std)
+	}
+
+	f, err := open(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// exportStreak writes the streak, including the daily completions, as
+// indented JSON
+func exportStreak(store storage.Storage, w io.Writer) error {
+	streak, err := store.GetStreak()
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(streak)
+}
+
+// importStreak reads an exported streak, validates it and replaces the stored
+// streak with it
+func importStreak(store storage.Storage, r io.Reader) error {
+	var streak storage.Streak
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&streak); err != nil {
+		return fmt.Errorf("invalid streak JSON: %w", err)
+	}
+
+	if err := storage.NormalizeStreak(&streak); err != nil {
+		return err
+	}
+	return store.UpdateStreak(&streak)
+}
+
+// importTodos reads a JSON array of todos and saves them with the timestamps
+// they were exported with. Every todo is validated before any is saved, and
+// todos without an ID get a new one.
+func importTodos(store *storage.BoltStorage, r io.Reader) (int, error) {
+	var todos []*models.Todo
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&todos); err != nil {
+		return 0, fmt.Errorf("invalid todos JSON: %w", err)
+	}
+
+	for i, todo := range todos {
+		if todo == nil || strings.TrimSpace(todo.Title) == "" {
+			return 0, fmt.Errorf("todo %d: title is required", i+1)
+		}
+		if len(todo.Title) > models.MaxTitleLength {
+			return 0, fmt.Errorf("todo %d: title exceeds maximum length of %d characters", i+1, models.MaxTitleLength)
+		}
+		if len(todo.Description) > models.MaxDescriptionLength {
+			return 0, fmt.Errorf("todo %d: description exceeds maximum length of %d characters", i+1, models.MaxDescriptionLength)
+		}
+		if todo.ID == "" {
+			todo.ID = models.NewID()
+		}
+	}
+
+	if err := store.SaveTodosPreservingTimestamps(todos); err != nil {
+		return 0, err
+	}
+	return len(todos), nil
+}
+
+// importCSV saves the well-formed rows of a CSV export with their timestamps
+// and reports the rows it skipped. Rows with the ID of a stored todo are
+// skipped too, since the CSV lacks its tags, subtasks and other fields, unless
+// overwrite is set; then only the CSV's columns of the stored todo change.
+func importCSV(w io.Writer, store *storage.BoltStorage, r io.Reader, overwrite bool) error {
+	rows, errs := export.ImportCSV(r)
+
+	existing, err := store.GetAllTodos()
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*models.Todo, len(existing))
+	for _, todo := range existing {
+		byID[todo.ID] = todo
+	}
+
+	var todos []*models.Todo
+	for _, todo := range rows {
+		stored := byID[todo.ID]
+		switch {
+		case todo.ID == "":
+			todo.ID = models.NewID()
+		case stored != nil && !overwrite:
+			errs = append(errs, fmt.Errorf("todo %s: already exists (use -csv-overwrite to replace it)", todo.ID))
+			continue
+		case stored != nil:
+			todo = overlayCSVRow(stored, todo)
+		}
+		todos = append(todos, todo)
+	}
+	for _, err := range errs {
+		fmt.Fprintf(w, "  ✘ Skipped %v\n", err)
+	}
+
+	if err := store.SaveTodosPreservingTimestamps(todos); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "✔ Imported %d todos", len(todos))
+	if len(errs) > 0 {
+		fmt.Fprintf(w, ", skipped %d rows", len(errs))
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+// overlayCSVRow returns stored with the columns of a CSV row, keeping the
+// fields the CSV doesn't carry and the completion time it already has
+func overlayCSVRow(stored, row *models.Todo) *models.Todo {
+	todo := *stored
+	todo.Title = row.Title
+	todo.Description = row.Description
+	todo.Deadline = row.Deadline
+	if !row.CreatedAt.IsZero() {
+		todo.CreatedAt = row.CreatedAt
+	}
+	switch {
+	case !row.Completed:
+		todo.Completed = false
+		todo.CompletedAt = nil
+	case !todo.Completed || todo.CompletedAt == nil:
+		todo.Completed = true
+		todo.CompletedAt = row.CompletedAt
+	}
+	// Stamped with now when saved
+	todo.UpdatedAt = time.Time{}
+	return &todo
+}
+
+// mergeDatabase opens the database at otherPath read-only and merges it into
+// the store, unlocking it with a passphrase from read when it is encrypted
+func mergeDatabase(store *storage.BoltStorage, dbPath, otherPath string, read func(prompt string) (string, error)) error {
+	absPath, err := filepath.Abs(otherPath)
+	if err != nil {
+		return err
+	}
+	if absPath == dbPath {
+		return fmt.Errorf("cannot merge the database into itself")
+	}
+
+	other, err := storage.OpenBoltStorageReadOnly(otherPath)
+	if err != nil {
+		return err
+	}
+	defer other.Close()
+
+	if other.Encrypted() {
+		passphrase, err := read("Passphrase for " + otherPath + ": ")
+		if err != nil {
+			return err
+		}
+		if err := other.Unlock(passphrase); err != nil {
+			return fmt.Errorf("failed to unlock %s: %w", otherPath, err)
+		}
+	}
+
+	return runMerge(store, other, dryRun)
+}
+
+// runMerge imports the todos of other that are new or more recently updated,
+// and merges both streaks
+func runMerge(store *storage.BoltStorage, other storage.Storage, dryRun bool) error {
+	existing, err := store.GetAllTodos()
+	if err != nil {
+		return err
+	}
+	incoming, err := other.GetAllTodos()
+	if err != nil {
+		return err
+	}
+
+	imported, skipped := storage.MergeTodos(existing, incoming)
+
+	if dryRun {
+		fmt.Printf("Would import %d todos, skipping %d older duplicates\n", len(imported), skipped)
+		return nil
+	}
+
+	ours, err := store.GetStreak()
+	if err != nil {
+		return err
+	}
+	theirs, err := other.GetStreak()
+	if err != nil {
+		return err
+	}
+
+	if err := store.ImportTodos(imported); err != nil {
+		return err
+	}
+	if err := store.UpdateStreak(storage.MergeStreaks(ours, theirs)); err != nil {
+		return err
+	}
+
+	fmt.Printf("✔ Imported %d todos, skipped %d older duplicates\n", len(imported), skipped)
+	return nil
+}
+
+// parseSubtasks builds subtasks from the given titles, skipping blanks
+func parseSubtasks(titles []string) []models.Subtask {
+	var result []models.Subtask
+	for _, title := range titles {
+		if title = strings.TrimSpace(title); title != "" {
+			result = append(result, models.Subtask{Title: title})
+		}
+	}
+	return result
+}
+
+func getDBPath() (string, error) {
+	dbPath, err := resolveDBPath(dbFlag, os.Getenv(DBPathEnv))
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	return dbPath, nil
+}
+
+// AutoArchiveEnv is the environment variable holding the number of days after
+// which completed todos are archived on startup. Archiving is off when unset.
+const AutoArchiveEnv = "DOIT_AUTO_ARCHIVE_DAYS"
+
+// autoArchiveRetention parses the auto archive setting, reporting false when
+// it is unset or not a positive number of days
+func autoArchiveRetention(value string) (time.Duration, bool) {
+	days, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || days <= 0 {
+		return 0, false
+	}
+	return time.Duration(days) * 24 * time.Hour, true
+}
+
+// PassphraseEnv is the environment variable holding the passphrase of an
+// encrypted database, for scripts that can't answer the prompt
+const PassphraseEnv = "DOIT_PASSPHRASE"
+
+// readPassphrase reads a passphrase from $DOIT_PASSPHRASE or, when unset,
+// from the terminal without echoing it
+func readPassphrase(prompt string) (string, error) {
+	if passphrase, ok := os.LookupEnv(PassphraseEnv); ok {
+		return passphrase, nil
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", fmt.Errorf("the database is encrypted: set %s or run doit in a terminal", PassphraseEnv)
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	return string(passphrase), err
+}
+
+// runEncrypt asks for a new passphrase twice and encrypts the database with it
+func runEncrypt(store *storage.BoltStorage, read func(prompt string) (string, error)) error {
+	if store.Encrypted() {
+		return storage.ErrAlreadyEncrypted
+	}
+
+	passphrase, err := read("New passphrase: ")
+	if err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return errors.New("the passphrase must not be empty")
+	}
+	repeated, err := read("Repeat passphrase: ")
+	if err != nil {
+		return err
+	}
+	if repeated != passphrase {
+		return errors.New("the passphrases do not match")
+	}
+
+	if err := store.Encrypt(passphrase); err != nil {
+		return err
+	}
+	fmt.Println("✔ Database encrypted. Todos can't be recovered without the passphrase")
+	return nil
+}
+
+// DBPathEnv is the environment variable overriding the database location
+const DBPathEnv = "DOIT_DB"
+
+// resolveDBPath returns the absolute database path, taken from the -db flag,
+// then $DOIT_DB, then ~/.local/share/doit/doit.db
+func resolveDBPath(flagPath, envPath string) (string, error) {
+	dbPath := flagPath
+	if dbPath == "" {
+		dbPath = envPath
+	}
+	if dbPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dbPath = filepath.Join(home, ".local", "share", "doit", "doit.db")
+	}
+
+	return filepath.Abs(dbPath)
+}
+
+// printWhere prints the resolved database path
+func printWhere(w io.Writer, flagPath, envPath string) error {
+	dbPath, err := resolveDBPath(flagPath, envPath)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, dbPath)
+	return err
+}
+
+// runCompact compacts the database file in place and reports how much it
+// shrank. It runs before the database is opened, since bolt locks the file.
+func runCompact(w io.Writer, dbPath string) error {
+	before, after, err := storage.CompactFile(dbPath)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "✔ Compacted database from %s to %s\n", formatSize(before), formatSize(after))
+	return err
+}
+
+// formatSize formats a file size in bytes with a binary unit
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size) / unit
+	for _, suffix := range []string{"KB", "MB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f GB", value)
 }
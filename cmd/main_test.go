@@ -1,17 +1,29 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/export"
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/storage"
+	"github.com/akr411/doit/internal/ui"
 )
 
 func TestCharacterLimitConstants(t *testing.T) {
-	if MaxTitleLength != 100 {
-		t.Errorf("Expected MaxTitleLength to be 100, got %d", MaxTitleLength)
+	if models.MaxTitleLength != 100 {
+		t.Errorf("Expected models.MaxTitleLength to be 100, got %d", models.MaxTitleLength)
 	}
 
-	if MaxDescriptionLength != 500 {
-		t.Errorf("Expected MaxDescriptionLength to be 100, got %d", MaxDescriptionLength)
+	if models.MaxDescriptionLength != 500 {
+		t.Errorf("Expected models.MaxDescriptionLength to be 100, got %d", models.MaxDescriptionLength)
 	}
 }
 
@@ -30,46 +42,46 @@ func TestValidateCharacterLimits(t *testing.T) {
 		},
 		{
 			name:        "Title at max length",
-			title:       strings.Repeat("a", MaxTitleLength),
+			title:       strings.Repeat("a", models.MaxTitleLength),
 			description: "Normal description",
 			shouldFail:  false,
 		},
 		{
 			name:        "Title exceeds max length",
-			title:       strings.Repeat("a", MaxTitleLength+1),
+			title:       strings.Repeat("a", models.MaxTitleLength+1),
 			description: "Normal description",
 			shouldFail:  true,
 		},
 		{
 			name:        "Description at max length",
 			title:       "Normal title",
-			description: strings.Repeat("b", MaxDescriptionLength),
+			description: strings.Repeat("b", models.MaxDescriptionLength),
 			shouldFail:  false,
 		},
 		{
 			name:        "Description exceeds max length",
 			title:       "Normal title",
-			description: strings.Repeat("b", MaxDescriptionLength+1),
+			description: strings.Repeat("b", models.MaxDescriptionLength+1),
 			shouldFail:  true,
 		},
 		{
 			name:        "Both at max length",
-			title:       strings.Repeat("a", MaxTitleLength),
-			description: strings.Repeat("b", MaxDescriptionLength),
+			title:       strings.Repeat("a", models.MaxTitleLength),
+			description: strings.Repeat("b", models.MaxDescriptionLength),
 			shouldFail:  false,
 		},
 		{
 			name:        "Both exceeds max length",
-			title:       strings.Repeat("a", MaxTitleLength+1),
-			description: strings.Repeat("b", MaxDescriptionLength+1),
+			title:       strings.Repeat("a", models.MaxTitleLength+1),
+			description: strings.Repeat("b", models.MaxDescriptionLength+1),
 			shouldFail:  true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			titleExceeds := len(tt.title) > MaxTitleLength
-			descExceeds := len(tt.description) > MaxDescriptionLength
+			titleExceeds := len(tt.title) > models.MaxTitleLength
+			descExceeds := len(tt.description) > models.MaxDescriptionLength
 			shouldFail := titleExceeds || descExceeds
 
 			if shouldFail != tt.shouldFail {
@@ -78,3 +90,791 @@ func TestValidateCharacterLimits(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSubtasks(t *testing.T) {
+	subtasks := parseSubtasks([]string{"Draft", "  ", " Review "})
+
+	if len(subtasks) != 2 {
+		t.Fatalf("parseSubtasks returned %d subtasks, want 2", len(subtasks))
+	}
+	if subtasks[0].Title != "Draft" || subtasks[1].Title != "Review" {
+		t.Errorf("parseSubtasks titles = %q, %q", subtasks[0].Title, subtasks[1].Title)
+	}
+	if subtasks[0].Done || subtasks[1].Done {
+		t.Error("New subtasks should not be done")
+	}
+}
+
+func TestRun_ThenList(t *testing.T) {
+	tests := []struct {
+		name       string
+		title      string
+		thenList   bool
+		closeStore bool
+		wantLaunch bool
+		wantError  bool
+	}{
+		{
+			name:       "create without then-list",
+			title:      "Task",
+			thenList:   false,
+			wantLaunch: false,
+		},
+		{
+			name:       "create with then-list",
+			title:      "Task",
+			thenList:   true,
+			wantLaunch: true,
+		},
+		{
+			name:       "invalid input does not launch",
+			title:      strings.Repeat("a", models.MaxTitleLength+1),
+			thenList:   true,
+			wantLaunch: false,
+			wantError:  true,
+		},
+		{
+			name:       "failed save does not launch",
+			title:      "Task",
+			thenList:   true,
+			closeStore: true,
+			wantLaunch: false,
+			wantError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore(t)
+			if tt.closeStore {
+				store.Close()
+			}
+
+			setCreateFlags(t, tt.title, "Description", tt.thenList)
+
+			launch, err := run(store)
+			if (err != nil) != tt.wantError {
+				t.Errorf("run() error = %v, wantError %v", err, tt.wantError)
+			}
+			if launch != tt.wantLaunch {
+				t.Errorf("run() launch = %v, want %v", launch, tt.wantLaunch)
+			}
+		})
+	}
+}
+
+func TestRun_WhitespaceTitle(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		wantTitle string
+		wantError string
+	}{
+		{name: "spaces only", title: "   ", wantError: "title is required"},
+		{name: "tabs and newlines", title: "\t\n", wantError: "title is required"},
+		{name: "surrounding spaces", title: "  hi  ", wantTitle: "hi"},
+		{name: "internal spaces", title: " buy milk ", wantTitle: "buy milk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore(t)
+
+			setCreateFlags(t, tt.title, " Description ", false)
+
+			_, err := run(store)
+			if tt.wantError != "" {
+				if err == nil || err.Error() != tt.wantError {
+					t.Errorf("run() error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+
+			todos, err := store.GetAllTodos()
+			if err != nil {
+				t.Fatalf("GetAllTodos() error = %v", err)
+			}
+			if len(todos) != 1 {
+				t.Fatalf("GetAllTodos() returned %d todos, want 1", len(todos))
+			}
+			if todos[0].Title != tt.wantTitle || todos[0].Description != "Description" {
+				t.Errorf("stored %q / %q, want %q / %q", todos[0].Title, todos[0].Description, tt.wantTitle, "Description")
+			}
+		})
+	}
+}
+
+// newTestStore opens a storage in a fresh temporary directory, closing it
+// when the test ends
+func newTestStore(t *testing.T) *storage.BoltStorage {
+	t.Helper()
+
+	store, err := storage.NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// setCreateFlags sets the create flags for a test, restoring them afterwards
+func setCreateFlags(t *testing.T, newTitle, newDescription string, newThenList bool) {
+	t.Helper()
+
+	oldTitle, oldDescription, oldThenList := title, description, thenList
+	title, description, thenList = newTitle, newDescription, newThenList
+
+	t.Cleanup(func() {
+		title, description, thenList = oldTitle, oldDescription, oldThenList
+	})
+}
+
+func TestRun_RequireDeadline(t *testing.T) {
+	tests := []struct {
+		name      string
+		deadline  string
+		strict    bool
+		wantError error
+	}{
+		{name: "permissive without deadline", strict: false},
+		{name: "strict without deadline", strict: true, wantError: ui.ErrDeadlineRequired},
+		{name: "strict with deadline", deadline: "1d", strict: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore(t)
+
+			setCreateFlags(t, "Task", "Description", false)
+			oldDeadline, oldStrict := deadline, strictMode
+			deadline, strictMode = tt.deadline, tt.strict
+			t.Cleanup(func() { deadline, strictMode = oldDeadline, oldStrict })
+
+			if _, err := run(store); !errors.Is(err, tt.wantError) {
+				t.Errorf("run() error = %v, want %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestRunReplace_DryRun(t *testing.T) {
+	store := newTestStore(t)
+
+	if err := store.SaveTodo(&models.Todo{ID: "1", Title: "Email Acme", Description: "Invoice"}); err != nil {
+		t.Fatalf("SaveTodo failed: %v", err)
+	}
+
+	if err := runReplace(store, "Acme", "Globex", true); err != nil {
+		t.Fatalf("runReplace dry run failed: %v", err)
+	}
+
+	todo, _ := store.GetTodo("1")
+	if todo.Title != "Email Acme" {
+		t.Errorf("Dry run should not persist changes, got title %q", todo.Title)
+	}
+
+	if err := runReplace(store, "Acme", "Globex", false); err != nil {
+		t.Fatalf("runReplace failed: %v", err)
+	}
+
+	todo, _ = store.GetTodo("1")
+	if todo.Title != "Email Globex" {
+		t.Errorf("Expected replacement to be saved, got title %q", todo.Title)
+	}
+}
+
+// closeCounter counts Close calls, leaving the rest of Storage unimplemented
+type closeCounter struct {
+	storage.Storage
+	closes int
+}
+
+func (c *closeCounter) Close() error {
+	c.closes++
+	return nil
+}
+
+func TestRunDedupe_DryRun(t *testing.T) {
+	store := newTestStore(t)
+
+	for _, todo := range []*models.Todo{
+		{ID: "1", Title: "Renew passport"},
+		{ID: "2", Title: "renew passport "},
+	} {
+		if err := store.SaveTodo(todo); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+
+	if err := runDedupe(store, true); err != nil {
+		t.Fatalf("runDedupe dry run failed: %v", err)
+	}
+	if todos, _ := store.GetAllTodos(); len(todos) != 2 {
+		t.Errorf("Dry run should not merge, got %d todos", len(todos))
+	}
+
+	if err := runDedupe(store, false); err != nil {
+		t.Fatalf("runDedupe failed: %v", err)
+	}
+	if todos, _ := store.GetAllTodos(); len(todos) != 1 {
+		t.Errorf("Expected the duplicates to be merged, got %d todos", len(todos))
+	}
+}
+
+func TestShutdown_ClosesOnce(t *testing.T) {
+	store := &closeCounter{}
+	closeStore := shutdown(store)
+
+	stop := handleSignals(closeStore)
+	for range 3 {
+		if err := closeStore(); err != nil {
+			t.Errorf("closeStore() error = %v", err)
+		}
+	}
+	stop()
+
+	if store.closes != 1 {
+		t.Errorf("Close called %d times, want 1", store.closes)
+	}
+}
+
+func TestPrintWhere(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		flagPath string
+		envPath  string
+		want     string
+	}{
+		{"default", "", "", filepath.Join(home, ".local", "share", "doit", "doit.db")},
+		{"env", "", "/data/env.db", "/data/env.db"},
+		{"flag", "/data/flag.db", "", "/data/flag.db"},
+		{"flag wins over env", "/data/flag.db", "/data/env.db", "/data/flag.db"},
+		{"relative flag", "todos.db", "", filepath.Join(cwd, "todos.db")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := resolveDBPath(tt.flagPath, tt.envPath)
+			if err != nil {
+				t.Fatalf("resolveDBPath() error = %v", err)
+			}
+			if resolved != tt.want {
+				t.Errorf("resolveDBPath() = %v, want %v", resolved, tt.want)
+			}
+
+			var out bytes.Buffer
+			if err := printWhere(&out, tt.flagPath, tt.envPath); err != nil {
+				t.Fatalf("printWhere() error = %v", err)
+			}
+			if out.String() != resolved+"\n" {
+				t.Errorf("printWhere() = %q, want %q", out.String(), resolved+"\n")
+			}
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KB"},
+		{32 << 20, "32.0 MB"},
+		{3 << 30, "3.0 GB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatSize(tt.size); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestExportImportStreak_RoundTrip(t *testing.T) {
+	source := newTestStore(t)
+
+	streak := &storage.Streak{
+		CurrentStreak:   2,
+		MaxStreak:       3,
+		LastCompletedAt: time.Date(2025, 11, 16, 18, 30, 0, 0, time.UTC),
+		TotalCompleted:  9,
+		DailyCompletions: map[string]int{
+			"2025-11-01": 2,
+			"2025-11-02": 1,
+			"2025-11-03": 3,
+			"2025-11-15": 1,
+			"2025-11-16": 2,
+		},
+	}
+	if err := source.UpdateStreak(streak); err != nil {
+		t.Fatalf("UpdateStreak failed: %v", err)
+	}
+
+	var exported bytes.Buffer
+	if err := exportStreak(source, &exported); err != nil {
+		t.Fatalf("exportStreak() error = %v", err)
+	}
+
+	target := newTestStore(t)
+
+	if err := importStreak(target, &exported); err != nil {
+		t.Fatalf("importStreak() error = %v", err)
+	}
+
+	imported, err := target.GetStreak()
+	if err != nil {
+		t.Fatalf("GetStreak failed: %v", err)
+	}
+	if !reflect.DeepEqual(imported, streak) {
+		t.Errorf("Imported streak = %+v, want %+v", imported, streak)
+	}
+}
+
+func TestImportStreak_Invalid(t *testing.T) {
+	store := newTestStore(t)
+
+	tests := []string{
+		`not json`,
+		`{"daily_completions": {"yesterday": 1}}`,
+		`{"daily_completions": {"2025-11-16": -1}}`,
+		`{"unknown_field": 1}`,
+	}
+
+	for _, input := range tests {
+		if err := importStreak(store, strings.NewReader(input)); err == nil {
+			t.Errorf("importStreak(%q) should fail", input)
+		}
+	}
+}
+
+func TestImportTodos_KeepsTimestamps(t *testing.T) {
+	store := newTestStore(t)
+
+	input := `[
+		{"id": "1", "title": "Old task", "created_at": "2023-03-01T09:00:00Z",
+		 "completed": true, "completed_at": "2023-03-02T17:30:00Z"},
+		{"title": "No ID", "created_at": "2023-04-01T09:00:00Z"}
+	]`
+	count, err := importTodos(store, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("importTodos() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("importTodos() = %d, want 2", count)
+	}
+
+	todo, err := store.GetTodo("1")
+	if err != nil || todo == nil {
+		t.Fatalf("GetTodo() = %v, %v", todo, err)
+	}
+	if want := time.Date(2023, 3, 1, 9, 0, 0, 0, time.UTC); !todo.CreatedAt.Equal(want) {
+		t.Errorf("Imported CreatedAt = %v, want %v", todo.CreatedAt, want)
+	}
+	if want := time.Date(2023, 3, 2, 17, 30, 0, 0, time.UTC); todo.CompletedAt == nil || !todo.CompletedAt.Equal(want) {
+		t.Errorf("Imported CompletedAt = %v, want %v", todo.CompletedAt, want)
+	}
+
+	// A todo without a title rejects the whole file
+	if _, err := importTodos(store, strings.NewReader(`[{"id": "3", "title": "Fine"}, {"id": "4"}]`)); err == nil {
+		t.Error("importTodos() should fail for a todo without a title")
+	}
+	if todo, _ := store.GetTodo("3"); todo != nil {
+		t.Error("importTodos() saved todos from a rejected file")
+	}
+}
+
+func TestImportCSV_SkipsMalformedRows(t *testing.T) {
+	store := newTestStore(t)
+
+	input := "id,title,description,deadline,completed,created_at\n" +
+		"1,Old task,,,true,2023-03-01T09:00:00Z\n" +
+		"2,,,,false,\n" +
+		",No ID,,,false,\n"
+	var out bytes.Buffer
+	if err := importCSV(&out, store, strings.NewReader(input), false); err != nil {
+		t.Fatalf("importCSV() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "line 3: title is required") || !strings.Contains(out.String(), "Imported 2 todos, skipped 1 rows") {
+		t.Errorf("importCSV() output = %q", out.String())
+	}
+
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		t.Fatalf("GetAllTodos() error = %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("Imported %d todos, want 2", len(todos))
+	}
+	todo, err := store.GetTodo("1")
+	if err != nil || todo == nil {
+		t.Fatalf("GetTodo() = %v, %v", todo, err)
+	}
+	if want := time.Date(2023, 3, 1, 9, 0, 0, 0, time.UTC); !todo.CreatedAt.Equal(want) {
+		t.Errorf("Imported CreatedAt = %v, want %v", todo.CreatedAt, want)
+	}
+}
+
+func TestImportCSV_ExistingTodos(t *testing.T) {
+	store := newTestStore(t)
+
+	deadline := time.Now().Add(24 * time.Hour)
+	if err := store.SaveTodo(&models.Todo{
+		ID: "1", Title: "Water plants", Tags: []string{"home"}, Deadline: &deadline,
+		Subtasks: []models.Subtask{{Title: "Balcony"}}, Recurrence: models.RecurWeekly,
+	}); err != nil {
+		t.Fatalf("SaveTodo failed: %v", err)
+	}
+
+	var csvFile bytes.Buffer
+	todos, _ := store.GetAllTodos()
+	if err := export.ExportCSV(&csvFile, todos); err != nil {
+		t.Fatalf("ExportCSV() error = %v", err)
+	}
+	exported := csvFile.String()
+
+	var out bytes.Buffer
+	if err := importCSV(&out, store, strings.NewReader(exported), false); err != nil {
+		t.Fatalf("importCSV() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "todo 1: already exists") || !strings.Contains(out.String(), "Imported 0 todos, skipped 1 rows") {
+		t.Errorf("importCSV() output = %q", out.String())
+	}
+
+	renamed := strings.Replace(exported, "Water plants", "Water all plants", 1)
+	if err := importCSV(&out, store, strings.NewReader(renamed), true); err != nil {
+		t.Fatalf("importCSV() with overwrite error = %v", err)
+	}
+	todo, err := store.GetTodo("1")
+	if err != nil {
+		t.Fatalf("GetTodo() error = %v", err)
+	}
+	if todo.Title != "Water all plants" {
+		t.Errorf("Overwritten title = %q, want %q", todo.Title, "Water all plants")
+	}
+	if len(todo.Tags) != 1 || len(todo.Subtasks) != 1 || todo.Recurrence != models.RecurWeekly {
+		t.Errorf("Overwriting lost fields the CSV lacks: tags %v, subtasks %v, recurrence %v", todo.Tags, todo.Subtasks, todo.Recurrence)
+	}
+}
+
+func TestAutoArchiveRetention(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"30", 30 * 24 * time.Hour, true},
+		{" 7 ", 7 * 24 * time.Hour, true},
+		{"", 0, false},
+		{"0", 0, false},
+		{"-5", 0, false},
+		{"30d", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := autoArchiveRetention(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("autoArchiveRetention(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRunDone_Backdated(t *testing.T) {
+	store := newTestStore(t)
+
+	if err := store.SaveTodo(&models.Todo{ID: "1", Title: "Gym", Description: "Legs"}); err != nil {
+		t.Fatalf("SaveTodo failed: %v", err)
+	}
+
+	now := time.Now()
+	if err := runDone(store, "1", "2999-01-01 10:00", "", now); err == nil {
+		t.Error("runDone() with a future -at should fail")
+	}
+
+	at := now.AddDate(0, 0, -2).Truncate(time.Minute)
+	if err := runDone(store, "1", at.Format("2006-01-02 15:04"), "Squats and lunges", now); err != nil {
+		t.Fatalf("runDone() failed: %v", err)
+	}
+
+	todo, _ := store.GetTodo("1")
+	if !todo.Completed || todo.CompletedAt == nil || !todo.CompletedAt.Equal(at) {
+		t.Errorf("CompletedAt = %v, want %v", todo.CompletedAt, at)
+	}
+	if todo.CompletionNote != "Squats and lunges" {
+		t.Errorf("CompletionNote = %q, want %q", todo.CompletionNote, "Squats and lunges")
+	}
+	streak, _ := store.GetStreak()
+	if got := streak.DailyCompletions[at.Format("2006-01-02")]; got != 1 {
+		t.Errorf("DailyCompletions for the backdated day = %d, want 1", got)
+	}
+
+	if err := runDone(store, "1", "", "", now); err == nil {
+		t.Error("runDone() on a completed todo should fail")
+	}
+	if err := runDone(store, "missing", "", "", now); err == nil {
+		t.Error("runDone() with an unknown ID should fail")
+	}
+}
+
+func TestRunDone_ByTitle(t *testing.T) {
+	store := newTestStore(t)
+
+	for _, todo := range []*models.Todo{
+		{ID: "1", Title: "Write quarterly report"},
+		{ID: "2", Title: "Read report feedback"},
+	} {
+		if err := store.SaveTodo(todo); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+
+	if err := runDone(store, "report", "", "", time.Now()); err == nil {
+		t.Error("runDone() with an ambiguous title should fail")
+	}
+	if err := runDone(store, "Quarterly", "", "", time.Now()); err != nil {
+		t.Fatalf("runDone() failed: %v", err)
+	}
+	if todo, _ := store.GetTodo("1"); !todo.Completed {
+		t.Error("runDone() should complete the todo whose title matches")
+	}
+}
+
+func TestRunCompleteOverdue(t *testing.T) {
+	store := newTestStore(t)
+
+	now := time.Now()
+	past, future := now.AddDate(0, 0, -2), now.AddDate(0, 0, 2)
+	for _, todo := range []*models.Todo{
+		{ID: "1", Title: "Overdue", Deadline: &past},
+		{ID: "2", Title: "Also overdue", Deadline: &past},
+		{ID: "3", Title: "Upcoming", Deadline: &future},
+		{ID: "4", Title: "No deadline"},
+		{ID: "5", Title: "Done late", Deadline: &past, Completed: true},
+	} {
+		if err := store.SaveTodo(todo); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+
+	count, err := runCompleteOverdue(store, now)
+	if err != nil {
+		t.Fatalf("runCompleteOverdue() failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("runCompleteOverdue() = %d, want 2", count)
+	}
+
+	for id, want := range map[string]bool{"1": true, "2": true, "3": false, "4": false} {
+		if todo, _ := store.GetTodo(id); todo.Completed != want {
+			t.Errorf("todo %s Completed = %v, want %v", id, todo.Completed, want)
+		}
+	}
+	streak, _ := store.GetStreak()
+	if got := streak.DailyCompletions[now.Format("2006-01-02")]; got != 2 {
+		t.Errorf("DailyCompletions for today = %d, want 2", got)
+	}
+}
+
+func TestRunPlanNext(t *testing.T) {
+	store := newTestStore(t)
+
+	now := time.Now()
+	soon, later := now.Add(time.Hour), now.AddDate(0, 0, 2)
+	for _, todo := range []*models.Todo{
+		{ID: "1", Title: "Later", Deadline: &later},
+		{ID: "2", Title: "Soon", Deadline: &soon},
+		{ID: "3", Title: "Done", Deadline: &soon, Completed: true},
+	} {
+		if err := store.SaveTodo(todo); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+
+	planned, err := runPlanNext(store, 1, now)
+	if err != nil {
+		t.Fatalf("runPlanNext() failed: %v", err)
+	}
+	if len(planned) != 1 || planned[0].ID != "2" {
+		t.Fatalf("runPlanNext() planned %d todos, want only the most urgent one", len(planned))
+	}
+
+	todos, _ := store.GetAllTodos()
+	if got := storage.PlannedToday(todos, now); len(got) != 1 || got[0].ID != "2" {
+		t.Errorf("PlannedToday() = %d todos, want todo 2 saved as planned", len(got))
+	}
+}
+
+func TestRunSnoozeOverdue(t *testing.T) {
+	store := newTestStore(t)
+
+	now := time.Now().Truncate(time.Second)
+	past, future := now.AddDate(0, 0, -2), now.AddDate(0, 0, 2)
+	for _, todo := range []*models.Todo{
+		{ID: "1", Title: "Overdue", Deadline: &past},
+		{ID: "2", Title: "Upcoming", Deadline: &future},
+		{ID: "3", Title: "Done late", Deadline: &past, Completed: true},
+	} {
+		if err := store.SaveTodo(todo); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+
+	count, err := runSnoozeOverdue(store, "1d", now)
+	if err != nil {
+		t.Fatalf("runSnoozeOverdue() failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("runSnoozeOverdue() = %d, want 1", count)
+	}
+
+	for id, want := range map[string]time.Time{"1": now.Add(24 * time.Hour), "2": future, "3": past} {
+		if todo, _ := store.GetTodo(id); !todo.Deadline.Equal(want) {
+			t.Errorf("todo %s deadline = %v, want %v", id, todo.Deadline, want)
+		}
+	}
+
+	if _, err := runSnoozeOverdue(store, "soon", now); err == nil {
+		t.Error("Expected an invalid duration to fail")
+	}
+}
+
+func TestPrintCounts(t *testing.T) {
+	store := newTestStore(t)
+
+	now := time.Date(2025, 11, 16, 12, 0, 0, 0, time.Local)
+	earlier, later := now.Add(-2*time.Hour), now.Add(3*time.Hour)
+	lastWeek, nextWeek := now.AddDate(0, 0, -7), now.AddDate(0, 0, 7)
+	for _, todo := range []*models.Todo{
+		{ID: "1", Title: "Due this morning", Deadline: &earlier},
+		{ID: "2", Title: "Due tonight", Deadline: &later},
+		{ID: "3", Title: "Long overdue", Deadline: &lastWeek},
+		{ID: "4", Title: "Next week", Deadline: &nextWeek},
+		{ID: "5", Title: "Someday"},
+		{ID: "6", Title: "Done", Deadline: &earlier, Completed: true, CompletedAt: &now},
+	} {
+		if err := store.SaveTodo(todo); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := printCounts(&out, store, now, true); err != nil {
+		t.Fatalf("printCounts() failed: %v", err)
+	}
+	var got map[string]int
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("printCounts() printed invalid JSON %q: %v", out.String(), err)
+	}
+	want := map[string]int{"total": 6, "incomplete": 5, "overdue": 2, "due_today": 2, "completed": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("printCounts() = %v, want %v", got, want)
+	}
+
+	out.Reset()
+	if err := printCounts(&out, store, now, false); err != nil {
+		t.Fatalf("printCounts() failed: %v", err)
+	}
+	if want := "Total: 6 | Incomplete: 5 | Overdue: 2 | Due today: 2 | Completed: 1\n"; out.String() != want {
+		t.Errorf("printCounts() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestRunEncrypt(t *testing.T) {
+	store := newTestStore(t)
+
+	answers := func(replies ...string) func(string) (string, error) {
+		return func(string) (string, error) {
+			reply := replies[0]
+			replies = replies[1:]
+			return reply, nil
+		}
+	}
+
+	if err := runEncrypt(store, answers("secret", "typo")); err == nil {
+		t.Error("runEncrypt() with mismatched passphrases should fail")
+	}
+	if err := runEncrypt(store, answers("")); err == nil {
+		t.Error("runEncrypt() with an empty passphrase should fail")
+	}
+	if store.Encrypted() {
+		t.Fatal("A failed runEncrypt() should leave the database unencrypted")
+	}
+
+	if err := runEncrypt(store, answers("secret", "secret")); err != nil {
+		t.Fatalf("runEncrypt() failed: %v", err)
+	}
+	if !store.Encrypted() {
+		t.Error("Expected the database to be encrypted")
+	}
+}
+
+func TestMergeDatabase_EncryptedSource(t *testing.T) {
+	otherPath := filepath.Join(t.TempDir(), "other.db")
+	other, err := storage.NewBoltStorage(otherPath)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	if err := other.Encrypt("secret"); err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	if err := other.SaveTodo(&models.Todo{ID: "1", Title: "Renew passport"}); err != nil {
+		t.Fatalf("SaveTodo failed: %v", err)
+	}
+	other.Close()
+
+	store := newTestStore(t)
+	passphrase := func(reply string) func(string) (string, error) {
+		return func(string) (string, error) { return reply, nil }
+	}
+
+	if err := mergeDatabase(store, "", otherPath, passphrase("typo")); !errors.Is(err, storage.ErrWrongPassphrase) {
+		t.Errorf("mergeDatabase() with a wrong passphrase error = %v, want ErrWrongPassphrase", err)
+	}
+	if err := mergeDatabase(store, "", otherPath, passphrase("secret")); err != nil {
+		t.Fatalf("mergeDatabase() failed: %v", err)
+	}
+	if todo, err := store.GetTodo("1"); err != nil || todo.Title != "Renew passport" {
+		t.Errorf("GetTodo() after merging = %v, %v, want the encrypted source's todo", todo, err)
+	}
+}
+
+func TestRunSchedule(t *testing.T) {
+	store := newTestStore(t)
+
+	created := time.Now().Add(-time.Hour)
+	for i, id := range []string{"1", "2", "3"} {
+		todo := &models.Todo{ID: id, Title: "Todo " + id, Description: "d", CreatedAt: created.Add(time.Duration(i) * time.Minute)}
+		if err := store.SaveTodo(todo); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+
+	now := time.Date(2025, 11, 17, 9, 0, 0, 0, time.Local) // Monday
+	if err := runSchedule(store, 2, now, true); err != nil {
+		t.Fatalf("runSchedule dry run failed: %v", err)
+	}
+	if todo, _ := store.GetTodo("1"); todo.Deadline != nil {
+		t.Error("Dry run should not persist deadlines")
+	}
+
+	if err := runSchedule(store, 2, now, false); err != nil {
+		t.Fatalf("runSchedule failed: %v", err)
+	}
+	for id, day := range map[string]int{"1": 18, "2": 18, "3": 19} {
+		todo, _ := store.GetTodo(id)
+		if todo.Deadline == nil || todo.Deadline.Day() != day {
+			t.Errorf("Deadline of %s = %v, want Nov %d", id, todo.Deadline, day)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// CSVHeader is the header row of the CSV export, naming its columns in order
+var CSVHeader = []string{"id", "title", "description", "deadline", "completed", "created_at"}
+
+// ExportCSV writes todos as CSV with a header row. Times are RFC 3339, and a
+// todo without a deadline has an empty deadline column.
+func ExportCSV(w io.Writer, todos []*models.Todo) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(CSVHeader); err != nil {
+		return err
+	}
+	for _, todo := range todos {
+		deadline := ""
+		if todo.Deadline != nil {
+			deadline = todo.Deadline.Format(time.RFC3339)
+		}
+		if err := writer.Write([]string{
+			todo.ID,
+			todo.Title,
+			todo.Description,
+			deadline,
+			strconv.FormatBool(todo.Completed),
+			todo.CreatedAt.Format(time.RFC3339),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ImportCSV reads todos in the columns ExportCSV writes. The header row is
+// skipped, and malformed rows are left out and reported with their line
+// number rather than failing the whole import. Completed todos are stamped
+// as completed when they were created, since the CSV has no completion time.
+func ImportCSV(r io.Reader) ([]*models.Todo, []error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var todos []*models.Todo
+	var errs []error
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			errs = append(errs, err)
+			continue
+		} else if err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), CSVHeader[0]) {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		todo, err := parseCSVRecord(record)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+			continue
+		}
+		todos = append(todos, todo)
+	}
+	return todos, errs
+}
+
+// parseCSVRecord turns one CSV row into a todo
+func parseCSVRecord(record []string) (*models.Todo, error) {
+	if len(record) != len(CSVHeader) {
+		return nil, fmt.Errorf("expected %d columns, got %d", len(CSVHeader), len(record))
+	}
+
+	todo := &models.Todo{
+		ID:          strings.TrimSpace(record[0]),
+		Title:       strings.TrimSpace(record[1]),
+		Description: strings.TrimSpace(record[2]),
+	}
+	if todo.Title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	if utf8.RuneCountInString(todo.Title) > models.MaxTitleLength {
+		return nil, fmt.Errorf("title exceeds maximum length of %d characters", models.MaxTitleLength)
+	}
+	if utf8.RuneCountInString(todo.Description) > models.MaxDescriptionLength {
+		return nil, fmt.Errorf("description exceeds maximum length of %d characters", models.MaxDescriptionLength)
+	}
+
+	if value := strings.TrimSpace(record[3]); value != "" {
+		deadline, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deadline %q", value)
+		}
+		todo.Deadline = &deadline
+	}
+	if value := strings.TrimSpace(record[4]); value != "" {
+		completed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid completed value %q", value)
+		}
+		todo.Completed = completed
+	}
+	if value := strings.TrimSpace(record[5]); value != "" {
+		createdAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid created_at %q", value)
+		}
+		todo.CreatedAt = createdAt
+	}
+	if todo.Completed && !todo.CreatedAt.IsZero() {
+		completedAt := todo.CreatedAt
+		todo.CompletedAt = &completedAt
+	}
+	return todo, nil
+}
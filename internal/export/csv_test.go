@@ -0,0 +1,88 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestCSV_RoundTrip(t *testing.T) {
+	zone := time.FixedZone("UTC+9", 9*60*60)
+	created := time.Date(2025, 11, 10, 9, 0, 0, 0, zone)
+	deadline := time.Date(2025, 11, 14, 17, 30, 0, 0, zone)
+
+	todos := []*models.Todo{
+		{ID: "1", Title: "Send invoice", Description: "Acme, \"net 30\"", Deadline: &deadline, CreatedAt: created},
+		{ID: "2", Title: "Water plants", Description: "Both\nbalconies", Completed: true, CreatedAt: created},
+	}
+
+	var buf bytes.Buffer
+	if err := ExportCSV(&buf, todos); err != nil {
+		t.Fatalf("ExportCSV() error = %v", err)
+	}
+	if header, _, _ := strings.Cut(buf.String(), "\n"); header != "id,title,description,deadline,completed,created_at" {
+		t.Errorf("ExportCSV() header = %q", header)
+	}
+
+	got, errs := ImportCSV(&buf)
+	if len(errs) != 0 {
+		t.Fatalf("ImportCSV() errors = %v", errs)
+	}
+	if len(got) != len(todos) {
+		t.Fatalf("ImportCSV() returned %d todos, want %d", len(got), len(todos))
+	}
+	for i, want := range todos {
+		if got[i].ID != want.ID || got[i].Title != want.Title || got[i].Description != want.Description ||
+			got[i].Completed != want.Completed || !got[i].CreatedAt.Equal(want.CreatedAt) {
+			t.Errorf("ImportCSV()[%d] = %+v, want %+v", i, got[i], want)
+		}
+	}
+	if got[0].Deadline == nil || !got[0].Deadline.Equal(deadline) {
+		t.Errorf("ImportCSV()[0].Deadline = %v, want %v", got[0].Deadline, deadline)
+	}
+	if got[1].Deadline != nil {
+		t.Errorf("ImportCSV()[1].Deadline = %v, want nil", got[1].Deadline)
+	}
+	if got[1].CompletedAt == nil || !got[1].CompletedAt.Equal(created) {
+		t.Errorf("ImportCSV()[1].CompletedAt = %v, want %v", got[1].CompletedAt, created)
+	}
+}
+
+func TestImportCSV_MalformedRows(t *testing.T) {
+	input := strings.Join([]string{
+		"id,title,description,deadline,completed,created_at",
+		"1,Send invoice,,2025-11-14T17:30:00+09:00,false,2025-11-10T09:00:00+09:00",
+		"2,Too few columns",
+		"3,Bad deadline,,next tuesday,false,",
+		"4,   ,,,false,",
+		"5,Bad \"quote,,,false,",
+		"6," + strings.Repeat("a", models.MaxTitleLength+1) + ",,,false,",
+		"7,Water plants,,,yes,",
+		"8,Call mum,,,,",
+	}, "\n")
+
+	todos, errs := ImportCSV(strings.NewReader(input))
+
+	if len(todos) != 2 || todos[0].ID != "1" || todos[1].ID != "8" {
+		t.Fatalf("ImportCSV() kept %d todos, want 1 and 8", len(todos))
+	}
+	wants := []string{
+		"line 3: expected 6 columns",
+		"line 4: invalid deadline",
+		"line 5: title is required",
+		"line 6",
+		"line 7: title exceeds maximum length",
+		"line 8: invalid completed value",
+	}
+	if len(errs) != len(wants) {
+		t.Fatalf("ImportCSV() errors = %v, want %d", errs, len(wants))
+	}
+	for i, want := range wants {
+		if !strings.Contains(errs[i].Error(), want) {
+			t.Errorf("ImportCSV() error %d = %q, want it to contain %q", i, errs[i], want)
+		}
+	}
+}
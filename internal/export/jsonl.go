@@ -0,0 +1,23 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// TodoIterator visits every todo one at a time, so callers can process
+// databases too large to load at once
+type TodoIterator interface {
+	EachTodo(fn func(todo *models.Todo) error) error
+}
+
+// ExportJSONL writes each todo as a JSON object on its own line, streaming
+// them from it without holding them all in memory
+func ExportJSONL(w io.Writer, it TodoIterator) error {
+	encoder := json.NewEncoder(w)
+	return it.EachTodo(func(todo *models.Todo) error {
+		return encoder.Encode(todo)
+	})
+}
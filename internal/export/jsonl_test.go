@@ -0,0 +1,74 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// sliceIterator is a TodoIterator over fixed todos. It has no GetAllTodos,
+// so ExportJSONL can only stream through EachTodo.
+type sliceIterator struct {
+	todos   []*models.Todo
+	visited int
+}
+
+func (it *sliceIterator) EachTodo(fn func(todo *models.Todo) error) error {
+	for _, todo := range it.todos {
+		it.visited++
+		if err := fn(todo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestExportJSONL(t *testing.T) {
+	it := &sliceIterator{todos: []*models.Todo{
+		{ID: "1", Title: "Send invoice", Tags: []string{"work"}},
+		{ID: "2", Title: "Line\nbreak", Description: "Has \"quotes\""},
+		{ID: "3", Title: "Done", Completed: true},
+	}}
+
+	var buf bytes.Buffer
+	if err := ExportJSONL(&buf, it); err != nil {
+		t.Fatalf("ExportJSONL() error = %v", err)
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	var lines int
+	for scanner.Scan() {
+		var todo models.Todo
+		if err := json.Unmarshal(scanner.Bytes(), &todo); err != nil {
+			t.Fatalf("line %d is not a JSON object: %v", lines+1, err)
+		}
+		if want := it.todos[lines]; todo.ID != want.ID || todo.Title != want.Title {
+			t.Errorf("line %d = %s %q, want %s %q", lines+1, todo.ID, todo.Title, want.ID, want.Title)
+		}
+		lines++
+	}
+	if lines != 3 {
+		t.Errorf("ExportJSONL() wrote %d lines, want 3", lines)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestExportJSONL_StopsOnWriteError(t *testing.T) {
+	it := &sliceIterator{todos: []*models.Todo{{ID: "1"}, {ID: "2"}, {ID: "3"}}}
+
+	if err := ExportJSONL(failingWriter{}, it); err == nil {
+		t.Fatal("ExportJSONL() should fail when writing fails")
+	}
+	if it.visited != 1 {
+		t.Errorf("ExportJSONL() visited %d todos after the first write failed, want 1", it.visited)
+	}
+}
@@ -0,0 +1,66 @@
+// Package export writes todos in the formats of other todo tools
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// TaskwarriorTimeLayout is the UTC date format of Taskwarrior's JSON
+const TaskwarriorTimeLayout = "20060102T150405Z"
+
+// TaskwarriorTask is a task in Taskwarrior's JSON import format. Taskwarrior
+// assigns the uuid on import.
+type TaskwarriorTask struct {
+	Description string   `json:"description"`
+	Status      string   `json:"status"`
+	Entry       string   `json:"entry"`
+	Due         string   `json:"due,omitempty"`
+	End         string   `json:"end,omitempty"`
+	Project     string   `json:"project,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// ToTaskwarrior maps a todo to a Taskwarrior task. Todos have no priority, so
+// none is exported.
+func ToTaskwarrior(todo *models.Todo) TaskwarriorTask {
+	task := TaskwarriorTask{
+		Description: todo.Title,
+		Status:      "pending",
+		Entry:       taskwarriorTime(todo.CreatedAt),
+		Project:     todo.Project,
+		Tags:        todo.Tags,
+	}
+	if todo.Deadline != nil {
+		task.Due = taskwarriorTime(*todo.Deadline)
+	}
+	if todo.Completed {
+		task.Status = "completed"
+		// Taskwarrior requires an end date on completed tasks
+		end := todo.UpdatedAt
+		if todo.CompletedAt != nil {
+			end = *todo.CompletedAt
+		}
+		task.End = taskwarriorTime(end)
+	}
+	return task
+}
+
+// ExportTaskwarrior writes todos as a JSON array that `task import` reads
+func ExportTaskwarrior(w io.Writer, todos []*models.Todo) error {
+	tasks := make([]TaskwarriorTask, 0, len(todos))
+	for _, todo := range todos {
+		tasks = append(tasks, ToTaskwarrior(todo))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(tasks)
+}
+
+func taskwarriorTime(t time.Time) string {
+	return t.UTC().Format(TaskwarriorTimeLayout)
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestToTaskwarrior(t *testing.T) {
+	zone := time.FixedZone("UTC+9", 9*60*60)
+	created := time.Date(2025, 11, 10, 9, 0, 0, 0, zone)
+	deadline := time.Date(2025, 11, 14, 17, 30, 0, 0, zone)
+	completed := time.Date(2025, 11, 13, 8, 15, 45, 0, zone)
+
+	todo := &models.Todo{
+		ID:          "1",
+		Title:       "Send invoice",
+		Deadline:    &deadline,
+		Tags:        []string{"work", "billing"},
+		Project:     "Acme",
+		Completed:   true,
+		CompletedAt: &completed,
+		CreatedAt:   created,
+	}
+
+	got := ToTaskwarrior(todo)
+	want := TaskwarriorTask{
+		Description: "Send invoice",
+		Status:      "completed",
+		Entry:       "20251110T000000Z",
+		Due:         "20251114T083000Z",
+		End:         "20251112T231545Z",
+		Project:     "Acme",
+		Tags:        []string{"work", "billing"},
+	}
+	if got.Description != want.Description || got.Status != want.Status || got.Entry != want.Entry ||
+		got.Due != want.Due || got.End != want.End || got.Project != want.Project || !slices.Equal(got.Tags, want.Tags) {
+		t.Errorf("ToTaskwarrior() = %+v, want %+v", got, want)
+	}
+}
+
+func TestExportTaskwarrior(t *testing.T) {
+	created := time.Date(2025, 11, 10, 9, 0, 0, 0, time.UTC)
+	todos := []*models.Todo{{ID: "1", Title: "Water plants", CreatedAt: created}}
+
+	var buf bytes.Buffer
+	if err := ExportTaskwarrior(&buf, todos); err != nil {
+		t.Fatalf("ExportTaskwarrior() failed: %v", err)
+	}
+
+	var tasks []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &tasks); err != nil {
+		t.Fatalf("ExportTaskwarrior() wrote invalid JSON: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("ExportTaskwarrior() wrote %d tasks, want 1", len(tasks))
+	}
+	task := tasks[0]
+	if task["description"] != "Water plants" || task["status"] != "pending" || task["entry"] != "20251110T090000Z" {
+		t.Errorf("ExportTaskwarrior() task = %v", task)
+	}
+	for _, field := range []string{"due", "end", "tags", "priority"} {
+		if _, ok := task[field]; ok {
+			t.Errorf("ExportTaskwarrior() task has %q, want it omitted", field)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// NewID returns a new todo ID: the creation time in nanoseconds, so IDs sort
+// by when they were made and keep their familiar prefix, followed by a random
+// suffix so todos created in the same nanosecond, such as in an import loop,
+// never share one
+func NewID() string {
+	return newIDAt(time.Now())
+}
+
+func newIDAt(now time.Time) string {
+	var suffix [4]byte
+	rand.Read(suffix[:])
+	return fmt.Sprintf("%d-%s", now.UnixNano(), hex.EncodeToString(suffix[:]))
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewID_Unique(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100000; i++ {
+		id := NewID()
+		if seen[id] {
+			t.Fatalf("NewID() returned %q twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestNewID_SameNanosecond(t *testing.T) {
+	now := time.Date(2025, 11, 16, 12, 0, 0, 0, time.UTC)
+	a, b := newIDAt(now), newIDAt(now)
+	if a == b {
+		t.Errorf("newIDAt() = %q for both, want distinct IDs", a)
+	}
+	prefix := "1763294400000000000-"
+	if !strings.HasPrefix(a, prefix) || len(a) != len(prefix)+8 {
+		t.Errorf("newIDAt() = %q, want the time followed by an 8 character suffix", a)
+	}
+}
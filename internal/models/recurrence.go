@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akr411/doit/internal/utils"
+)
+
+// Recurrence describes how often a todo repeats
+type Recurrence string
+
+const (
+	RecurNone     Recurrence = ""
+	RecurDaily    Recurrence = "daily"
+	RecurWeekdays Recurrence = "weekdays"
+	RecurWeekly   Recurrence = "weekly"
+	RecurMonthly  Recurrence = "monthly"
+)
+
+// ParseRecurrence parses a recurrence name such as "daily" or "weekdays"
+func ParseRecurrence(input string) (Recurrence, error) {
+	switch r := Recurrence(strings.ToLower(strings.TrimSpace(input))); r {
+	case RecurNone, RecurDaily, RecurWeekdays, RecurWeekly, RecurMonthly:
+		return r, nil
+	default:
+		return RecurNone, fmt.Errorf("invalid recurrence: %s (use: daily, weekdays, weekly, monthly)", input)
+	}
+}
+
+// advance returns the next occurrence after t for the recurrence
+func (r Recurrence) advance(t time.Time) time.Time {
+	switch r {
+	case RecurDaily:
+		return t.AddDate(0, 0, 1)
+	case RecurWeekdays:
+		return utils.NextBusinessDay(t)
+	case RecurWeekly:
+		return t.AddDate(0, 0, 7)
+	case RecurMonthly:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t
+	}
+}
+
+// NextOccurrence returns a new incomplete copy of a recurring todo with its
+// deadline moved to the next occurrence. The copy has no ID. It returns nil
+// for todos that do not recur or have no deadline.
+func (t *Todo) NextOccurrence() *Todo {
+	if t.Recurrence == RecurNone || t.Deadline == nil {
+		return nil
+	}
+
+	next := *t
+	next.ID = ""
+	next.Completed = false
+	next.CompletedAt = nil
+	next.CompletionNote = ""
+	next.PlannedFor = nil
+	deadline := t.Recurrence.advance(*t.Deadline)
+	next.Deadline = &deadline
+	next.Tags = append([]string(nil), t.Tags...)
+	next.Attachments = append([]string(nil), t.Attachments...)
+	next.Subtasks = nil
+	for _, subtask := range t.Subtasks {
+		next.Subtasks = append(next.Subtasks, Subtask{Title: subtask.Title})
+	}
+
+	return &next
+}
+
+// NextFutureOccurrence returns the next occurrence of a recurring todo like
+// NextOccurrence, but rolled forward past the slots missed while it was
+// overdue so that its deadline is after now
+func NextFutureOccurrence(todo *Todo, now time.Time) *Todo {
+	next := todo.NextOccurrence()
+	if next == nil {
+		return nil
+	}
+
+	for !next.Deadline.After(now) {
+		deadline := next.Recurrence.advance(*next.Deadline)
+		if !deadline.After(*next.Deadline) {
+			// Unknown recurrences never advance
+			break
+		}
+		next.Deadline = &deadline
+	}
+	return next
+}
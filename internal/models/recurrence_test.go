@@ -0,0 +1,121 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTodo_NextOccurrence(t *testing.T) {
+	// Friday, November 21 2025
+	friday := time.Date(2025, 11, 21, 9, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name       string
+		recurrence Recurrence
+		expected   time.Time
+	}{
+		{"daily", RecurDaily, time.Date(2025, 11, 22, 9, 0, 0, 0, time.Local)},
+		{"weekdays skips the weekend", RecurWeekdays, time.Date(2025, 11, 24, 9, 0, 0, 0, time.Local)},
+		{"weekly", RecurWeekly, time.Date(2025, 11, 28, 9, 0, 0, 0, time.Local)},
+		{"monthly", RecurMonthly, time.Date(2025, 12, 21, 9, 0, 0, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := Todo{
+				ID:         "1",
+				Title:      "Standup notes",
+				Deadline:   timePtr(friday),
+				Recurrence: tt.recurrence,
+				Subtasks:   []Subtask{{Title: "Write", Done: true}},
+			}
+			todo.MarkComplete()
+
+			next := todo.NextOccurrence()
+			if next == nil {
+				t.Fatal("NextOccurrence() returned nil for a recurring todo")
+			}
+			if !next.Deadline.Equal(tt.expected) {
+				t.Errorf("NextOccurrence() deadline = %v, want %v", next.Deadline, tt.expected)
+			}
+			if next.Completed || next.CompletedAt != nil {
+				t.Error("NextOccurrence() should be incomplete")
+			}
+			if next.ID != "" {
+				t.Errorf("NextOccurrence() ID = %q, want empty", next.ID)
+			}
+			if next.Subtasks[0].Done || !todo.Subtasks[0].Done {
+				t.Error("NextOccurrence() should reset subtasks without touching the original")
+			}
+		})
+	}
+}
+
+func TestTodo_NextOccurrence_NotRecurring(t *testing.T) {
+	noRecurrence := Todo{Deadline: timePtr(time.Now())}
+	if noRecurrence.NextOccurrence() != nil {
+		t.Error("NextOccurrence() should be nil without a recurrence")
+	}
+
+	noDeadline := Todo{Recurrence: RecurDaily}
+	if noDeadline.NextOccurrence() != nil {
+		t.Error("NextOccurrence() should be nil without a deadline")
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	if r, err := ParseRecurrence(" Weekdays "); err != nil || r != RecurWeekdays {
+		t.Errorf("ParseRecurrence(Weekdays) = %q, %v", r, err)
+	}
+	if _, err := ParseRecurrence("hourly"); err == nil {
+		t.Error("ParseRecurrence(hourly) expected error but got nil")
+	}
+}
+
+func TestNextFutureOccurrence(t *testing.T) {
+	// Sunday, November 16 2025 at noon
+	now := time.Date(2025, 11, 16, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name       string
+		recurrence Recurrence
+		deadline   time.Time
+		expected   time.Time
+	}{
+		{"daily overdue by 5 days", RecurDaily, now.AddDate(0, 0, -5).Add(-3 * time.Hour), time.Date(2025, 11, 17, 9, 0, 0, 0, time.Local)},
+		{"daily due later today", RecurDaily, now.Add(2 * time.Hour), time.Date(2025, 11, 17, 14, 0, 0, 0, time.Local)},
+		{"daily overdue earlier today", RecurDaily, now.Add(-time.Hour), time.Date(2025, 11, 17, 11, 0, 0, 0, time.Local)},
+		{"weekly overdue by 3 weeks", RecurWeekly, now.AddDate(0, 0, -21).Add(-time.Hour), time.Date(2025, 11, 23, 11, 0, 0, 0, time.Local)},
+		{"weekdays overdue over a weekend", RecurWeekdays, time.Date(2025, 11, 13, 9, 0, 0, 0, time.Local), time.Date(2025, 11, 17, 9, 0, 0, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := &Todo{ID: "1", Title: "Water plants", Deadline: timePtr(tt.deadline), Recurrence: tt.recurrence}
+
+			next := NextFutureOccurrence(todo, now)
+			if next == nil {
+				t.Fatal("NextFutureOccurrence() returned nil for a recurring todo")
+			}
+			if !next.Deadline.Equal(tt.expected) {
+				t.Errorf("NextFutureOccurrence() deadline = %v, want %v", next.Deadline, tt.expected)
+			}
+			if !next.Deadline.After(now) {
+				t.Errorf("NextFutureOccurrence() deadline %v should be after %v", next.Deadline, now)
+			}
+		})
+	}
+}
+
+func TestNextFutureOccurrence_UnknownRecurrence(t *testing.T) {
+	now := time.Date(2025, 11, 16, 12, 0, 0, 0, time.Local)
+	todo := &Todo{ID: "1", Deadline: timePtr(now.AddDate(0, 0, -1)), Recurrence: Recurrence("hourly")}
+
+	// Must not loop forever on a recurrence that cannot advance
+	if next := NextFutureOccurrence(todo, now); next == nil {
+		t.Error("NextFutureOccurrence() should still return a copy")
+	}
+	if NextFutureOccurrence(&Todo{ID: "2"}, now) != nil {
+		t.Error("NextFutureOccurrence() should be nil without a recurrence")
+	}
+}
@@ -1,6 +1,23 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Character limits on titles and descriptions, enforced wherever todos are
+// created, edited or imported
+const (
+	MaxTitleLength       = 100
+	MaxDescriptionLength = 500
+)
+
+// Subtask represents a checklist item within a todo
+type Subtask struct {
+	Title string `json:"title"`
+	Done  bool   `json:"done"`
+}
 
 // Todo represents a todo item
 type Todo struct {
@@ -8,10 +25,30 @@ type Todo struct {
 	Title       string     `json:"title"`
 	Description string     `json:"description"`
 	Deadline    *time.Time `json:"deadline,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Project     string     `json:"project,omitempty"`
+	Subtasks    []Subtask  `json:"subtasks,omitempty"`
+	Attachments []string   `json:"attachments,omitempty"`
+	Recurrence  Recurrence `json:"recurrence,omitempty"`
+	Waiting     bool       `json:"waiting,omitempty"`
+	WaitingOn   string     `json:"waiting_on,omitempty"`
 	Completed   bool       `json:"completed"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// ReminderBefore is how long before the deadline the todo is surfaced,
+	// 0 for the default of a day
+	ReminderBefore time.Duration `json:"reminder_before,omitempty"`
+	// CompletionNote records what was done or the outcome, written when the
+	// todo is completed
+	CompletionNote string `json:"completion_note,omitempty"`
+	// PlannedFor is the local midnight of the day the todo is planned to be
+	// worked on, independent of when it is due
+	PlannedFor *time.Time `json:"planned_for,omitempty"`
+	// Points is the estimated effort, like story points, 0 when not
+	// estimated
+	Points int `json:"points,omitempty"`
 }
 
 // IsOverdue checks if the todo is overdue
@@ -31,17 +68,92 @@ func (t *Todo) DaysUntilDeadline() int {
 	return int(duration.Hours() / 24)
 }
 
+// HasTag checks if the todo is tagged with the given tag
+func (t *Todo) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, tag)
+}
+
+// AddTag tags the todo with tag unless it already is, reporting whether the
+// tags changed
+func (t *Todo) AddTag(tag string) bool {
+	if tag == "" || t.HasTag(tag) {
+		return false
+	}
+	t.Tags = append(t.Tags, tag)
+	return true
+}
+
+// RemoveTag removes tag from the todo, reporting whether it had the tag
+func (t *Todo) RemoveTag(tag string) bool {
+	i := slices.Index(t.Tags, tag)
+	if i < 0 {
+		return false
+	}
+	t.Tags = slices.Delete(t.Tags, i, i+1)
+	if len(t.Tags) == 0 {
+		t.Tags = nil
+	}
+	return true
+}
+
+// SubtaskProgress returns the number of done subtasks and the total
+func (t *Todo) SubtaskProgress() (done, total int) {
+	for _, subtask := range t.Subtasks {
+		if subtask.Done {
+			done++
+		}
+	}
+	return done, len(t.Subtasks)
+}
+
+// PromoteSubtask removes the subtask at index from the todo and returns it
+// as a standalone todo inheriting the parent's deadline, tags and project.
+// The returned todo has no ID; the caller assigns one before saving it
+func (t *Todo) PromoteSubtask(index int) (*Todo, error) {
+	if index < 0 || index >= len(t.Subtasks) {
+		return nil, fmt.Errorf("subtask index %d out of range", index)
+	}
+	subtask := t.Subtasks[index]
+	now := time.Now()
+	promoted := &Todo{
+		Title:     subtask.Title,
+		Tags:      slices.Clone(t.Tags),
+		Project:   t.Project,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if t.Deadline != nil {
+		deadline := *t.Deadline
+		promoted.Deadline = &deadline
+	}
+	if subtask.Done {
+		promoted.MarkCompleteAt(now)
+	}
+	t.Subtasks = slices.Delete(t.Subtasks, index, index+1)
+	if len(t.Subtasks) == 0 {
+		t.Subtasks = nil
+	}
+	t.UpdatedAt = now
+	return promoted, nil
+}
+
 // MarkComplete marks the todo as completed
 func (t *Todo) MarkComplete() {
+	t.MarkCompleteAt(time.Now())
+}
+
+// MarkCompleteAt marks the todo as completed at the given time, which may be
+// in the past to log work done earlier
+func (t *Todo) MarkCompleteAt(at time.Time) {
 	t.Completed = true
-	now := time.Now()
-	t.CompletedAt = &now
-	t.UpdatedAt = now
+	t.CompletedAt = &at
+	t.UpdatedAt = time.Now()
 }
 
 // MarkIncomplete marks the todo as incomplete
 func (t *Todo) MarkIncomplete() {
 	t.Completed = false
 	t.CompletedAt = nil
+	t.CompletionNote = ""
 	t.UpdatedAt = time.Now()
 }
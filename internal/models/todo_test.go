@@ -1,6 +1,7 @@
 package models
 
 import (
+	"slices"
 	"testing"
 	"time"
 )
@@ -157,3 +158,128 @@ func abs(n int) int {
 	}
 	return n
 }
+
+func TestTodo_AddRemoveTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		tags        []string
+		add         string
+		remove      string
+		wantTags    []string
+		wantChanged bool
+	}{
+		{"add to untagged", nil, "work", "", []string{"work"}, true},
+		{"add new", []string{"work"}, "home", "", []string{"work", "home"}, true},
+		{"add duplicate", []string{"work", "home"}, "work", "", []string{"work", "home"}, false},
+		{"add empty", []string{"work"}, "", "", []string{"work"}, false},
+		{"remove present", []string{"work", "home"}, "", "work", []string{"home"}, true},
+		{"remove last", []string{"work"}, "", "work", nil, true},
+		{"remove absent", []string{"work"}, "", "home", []string{"work"}, false},
+		{"remove from untagged", nil, "", "work", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := Todo{Tags: slices.Clone(tt.tags)}
+			var changed bool
+			if tt.remove != "" {
+				changed = todo.RemoveTag(tt.remove)
+			} else {
+				changed = todo.AddTag(tt.add)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if !slices.Equal(todo.Tags, tt.wantTags) {
+				t.Errorf("Tags = %v, want %v", todo.Tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestTodo_SubtaskProgress(t *testing.T) {
+	todo := Todo{
+		Subtasks: []Subtask{
+			{Title: "Draft", Done: true},
+			{Title: "Review", Done: true},
+			{Title: "Send", Done: false},
+		},
+	}
+
+	done, total := todo.SubtaskProgress()
+	if done != 2 || total != 3 {
+		t.Errorf("SubtaskProgress() = %d/%d, want 2/3", done, total)
+	}
+
+	empty := Todo{}
+	if done, total := empty.SubtaskProgress(); done != 0 || total != 0 {
+		t.Errorf("SubtaskProgress() without subtasks = %d/%d, want 0/0", done, total)
+	}
+}
+
+func TestTodo_PromoteSubtask(t *testing.T) {
+	deadline := time.Date(2025, 3, 1, 17, 0, 0, 0, time.Local)
+	parent := Todo{
+		ID:       "1",
+		Title:    "Release",
+		Deadline: &deadline,
+		Tags:     []string{"work"},
+		Project:  "doit",
+		Subtasks: []Subtask{
+			{Title: "Draft", Done: true},
+			{Title: "Review"},
+		},
+	}
+
+	promoted, err := parent.PromoteSubtask(1)
+	if err != nil {
+		t.Fatalf("PromoteSubtask() error = %v", err)
+	}
+	if promoted.Title != "Review" || promoted.Completed {
+		t.Errorf("PromoteSubtask() = %q (completed %v), want %q open", promoted.Title, promoted.Completed, "Review")
+	}
+	if promoted.Deadline == nil || !promoted.Deadline.Equal(deadline) || promoted.Deadline == parent.Deadline {
+		t.Errorf("PromoteSubtask() deadline = %v, want a copy of %v", promoted.Deadline, deadline)
+	}
+	if !promoted.HasTag("work") || promoted.Project != "doit" {
+		t.Errorf("PromoteSubtask() tags = %v, project = %q, want [work], doit", promoted.Tags, promoted.Project)
+	}
+	if len(parent.Subtasks) != 1 || parent.Subtasks[0].Title != "Draft" {
+		t.Errorf("parent subtasks = %v, want [Draft]", parent.Subtasks)
+	}
+
+	done, err := parent.PromoteSubtask(0)
+	if err != nil {
+		t.Fatalf("PromoteSubtask() error = %v", err)
+	}
+	if !done.Completed || done.CompletedAt == nil {
+		t.Errorf("PromoteSubtask() of a done subtask completed = %v, want true", done.Completed)
+	}
+	if parent.Subtasks != nil {
+		t.Errorf("parent subtasks = %v, want none", parent.Subtasks)
+	}
+}
+
+func TestTodo_PromoteSubtaskOutOfRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		index int
+	}{
+		{"negative", -1},
+		{"past the end", 2},
+		{"far past the end", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := Todo{Subtasks: []Subtask{{Title: "a"}, {Title: "b"}}}
+			promoted, err := todo.PromoteSubtask(tt.index)
+			if err == nil {
+				t.Errorf("PromoteSubtask(%d) = %v, want error", tt.index, promoted)
+			}
+			if len(todo.Subtasks) != 2 {
+				t.Errorf("PromoteSubtask(%d) left %d subtasks, want 2", tt.index, len(todo.Subtasks))
+			}
+		})
+	}
+}
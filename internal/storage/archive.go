@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ArchiveTodo moves a todo out of the active list into the archive bucket.
+// Unlike DeleteTodo it keeps the todo and leaves the streak untouched.
+func (s *BoltStorage) ArchiveTodo(id string) error {
+	return s.update(func(tx *bolt.Tx) error {
+		stored, err := s.storedTodo(tx, id)
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			return fmt.Errorf("todo not found")
+		}
+
+		data := tx.Bucket(todoBucket).Get([]byte(id))
+		if err := tx.Bucket(archiveBucket).Put([]byte(id), data); err != nil {
+			return err
+		}
+		return s.deleteTodo(tx, id)
+	})
+}
+
+// GetArchivedTodos retrieves all archived todos
+func (s *BoltStorage) GetArchivedTodos() ([]*models.Todo, error) {
+	var todos []*models.Todo
+
+	err := s.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(archiveBucket).ForEach(func(k, v []byte) error {
+			var todo models.Todo
+			if err := s.decodeTodo(v, &todo); err != nil {
+				return err
+			}
+			todos = append(todos, &todo)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// AutoArchive archives completed todos that were completed more than
+// olderThan before now, returning how many were archived. Todos without a
+// CompletedAt are skipped.
+func AutoArchive(store Storage, olderThan time.Duration, now time.Time) (int, error) {
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := now.Add(-olderThan)
+	archived := 0
+	for _, todo := range todos {
+		if !todo.Completed || todo.CompletedAt == nil || !todo.CompletedAt.Before(cutoff) {
+			continue
+		}
+		if err := store.ArchiveTodo(todo.ID); err != nil {
+			return archived, err
+		}
+		archived++
+	}
+	return archived, nil
+}
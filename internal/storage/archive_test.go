@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestAutoArchive(t *testing.T) {
+	s, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	todos := []*models.Todo{
+		{ID: "old", Title: "Done long ago", Completed: true, CompletedAt: timePtr(now.AddDate(0, 0, -40))},
+		{ID: "recent", Title: "Done last week", Completed: true, CompletedAt: timePtr(now.AddDate(0, 0, -7))},
+		{ID: "no-timestamp", Title: "Done, unknown when", Completed: true},
+		{ID: "open", Title: "Still open"},
+	}
+	for _, todo := range todos {
+		if err := s.SaveTodo(todo); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+
+	streakBefore, _ := s.GetStreak()
+
+	archived, err := AutoArchive(s, 30*24*time.Hour, now)
+	if err != nil {
+		t.Fatalf("AutoArchive() error = %v", err)
+	}
+	if archived != 1 {
+		t.Errorf("AutoArchive() = %v, want %v", archived, 1)
+	}
+
+	active, _ := s.GetAllTodos()
+	for _, todo := range active {
+		if todo.ID == "old" {
+			t.Error("The 40 day old todo should no longer be active")
+		}
+	}
+	if len(active) != 3 {
+		t.Errorf("GetAllTodos() returned %d todos, want 3", len(active))
+	}
+
+	archivedTodos, err := s.GetArchivedTodos()
+	if err != nil {
+		t.Fatalf("GetArchivedTodos() error = %v", err)
+	}
+	if len(archivedTodos) != 1 || archivedTodos[0].ID != "old" {
+		t.Errorf("GetArchivedTodos() = %v, want only the old todo", archivedTodos)
+	}
+
+	streakAfter, _ := s.GetStreak()
+	if streakAfter.TotalCompleted != streakBefore.TotalCompleted {
+		t.Errorf("Archiving changed TotalCompleted from %d to %d", streakBefore.TotalCompleted, streakAfter.TotalCompleted)
+	}
+
+	// Running again finds nothing new
+	if archived, _ := AutoArchive(s, 30*24*time.Hour, now); archived != 0 {
+		t.Errorf("Second AutoArchive() = %v, want 0", archived)
+	}
+}
+
+func TestBoltStorage_ArchiveTodoNotFound(t *testing.T) {
+	s, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.ArchiveTodo("missing"); err == nil {
+		t.Error("ArchiveTodo() should fail for a missing todo")
+	}
+}
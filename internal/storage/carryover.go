@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// CarryOver moves incomplete todos whose deadline was before today to the
+// end of today, returning the todos it changed so they can be saved
+func CarryOver(todos []*models.Todo, now time.Time) []*models.Todo {
+	now = now.Local()
+	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endOfToday := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 0, 0, now.Location())
+
+	var carried []*models.Todo
+	for _, todo := range todos {
+		if todo.Completed || todo.Deadline == nil || !todo.Deadline.Before(startOfToday) {
+			continue
+		}
+		deadline := endOfToday
+		todo.Deadline = &deadline
+		carried = append(carried, todo)
+	}
+	return carried
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestCarryOver(t *testing.T) {
+	now := time.Date(2025, 11, 16, 8, 30, 0, 0, time.Local)
+	endOfToday := time.Date(2025, 11, 16, 23, 59, 0, 0, time.Local)
+
+	todos := []*models.Todo{
+		{ID: "yesterday", Deadline: timePtr(time.Date(2025, 11, 15, 17, 0, 0, 0, time.Local))},
+		{ID: "last-week", Deadline: timePtr(time.Date(2025, 11, 9, 9, 0, 0, 0, time.Local))},
+		{ID: "late-last-night", Deadline: timePtr(time.Date(2025, 11, 15, 23, 59, 59, 0, time.Local))},
+		{ID: "earlier-today", Deadline: timePtr(time.Date(2025, 11, 16, 7, 0, 0, 0, time.Local))},
+		{ID: "tomorrow", Deadline: timePtr(time.Date(2025, 11, 17, 9, 0, 0, 0, time.Local))},
+		{ID: "completed", Deadline: timePtr(time.Date(2025, 11, 14, 9, 0, 0, 0, time.Local)), Completed: true},
+		{ID: "no-deadline"},
+	}
+
+	carried := CarryOver(todos, now)
+
+	want := []string{"yesterday", "last-week", "late-last-night"}
+	if len(carried) != len(want) {
+		t.Fatalf("CarryOver() returned %d todos, want %d", len(carried), len(want))
+	}
+	for i, todo := range carried {
+		if todo.ID != want[i] {
+			t.Errorf("CarryOver()[%d] = %v, want %v", i, todo.ID, want[i])
+		}
+		if !todo.Deadline.Equal(endOfToday) {
+			t.Errorf("CarryOver() %s deadline = %v, want %v", todo.ID, todo.Deadline, endOfToday)
+		}
+	}
+
+	if !todos[3].Deadline.Equal(time.Date(2025, 11, 16, 7, 0, 0, 0, time.Local)) {
+		t.Error("CarryOver() should leave todos due earlier today alone")
+	}
+	if !todos[5].Deadline.Equal(time.Date(2025, 11, 14, 9, 0, 0, 0, time.Local)) {
+		t.Error("CarryOver() should leave completed todos alone")
+	}
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// compactTxSize is how many bytes Compact copies per write transaction
+const compactTxSize = 1 << 20
+
+// Compact copies the database at srcPath into a new file at dstPath, leaving
+// out the free pages that bolt never gives back to the file system. dstPath
+// must not exist yet; it is removed again if compaction fails.
+func Compact(srcPath, dstPath string) error {
+	if _, err := os.Stat(dstPath); err == nil {
+		return fmt.Errorf("%s already exists", dstPath)
+	}
+
+	src, err := bolt.Open(srcPath, 0o600, &bolt.Options{ReadOnly: true, Timeout: time.Second})
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := bolt.Open(dstPath, 0o600, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create compacted database: %w", err)
+	}
+	if err := bolt.Compact(dst, src, compactTxSize); err != nil {
+		dst.Close()
+		os.Remove(dstPath)
+		return fmt.Errorf("failed to compact database: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(dstPath)
+		return fmt.Errorf("failed to close compacted database: %w", err)
+	}
+	return nil
+}
+
+// CompactFile compacts the database at path in place, writing the compacted
+// copy next to it and renaming it over the original so a crash leaves one or
+// the other intact. It returns the file size before and after.
+func CompactFile(path string) (before, after int64, err error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tmpPath := path + ".compact"
+	if err := os.Remove(tmpPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return 0, 0, err
+	}
+	if err := Compact(path, tmpPath); err != nil {
+		return 0, 0, err
+	}
+
+	compacted, err := os.Stat(tmpPath)
+	if err != nil {
+		os.Remove(tmpPath)
+		return 0, 0, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return 0, 0, fmt.Errorf("failed to replace database: %w", err)
+	}
+	return info.Size(), compacted.Size(), nil
+}
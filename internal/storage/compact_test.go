@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestCompactFile(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "doit.db")
+	store, err := NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("NewBoltStorage() error = %v", err)
+	}
+
+	description := strings.Repeat("x", 4096)
+	for i := range 500 {
+		todo := &models.Todo{ID: fmt.Sprintf("%03d", i), Title: "Todo", Description: description, CreatedAt: time.Now()}
+		if err := store.SaveTodo(todo); err != nil {
+			t.Fatalf("SaveTodo() error = %v", err)
+		}
+	}
+	for i := range 490 {
+		if err := store.DeleteTodo(fmt.Sprintf("%03d", i)); err != nil {
+			t.Fatalf("DeleteTodo() error = %v", err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	before, after, err := CompactFile(dbPath)
+	if err != nil {
+		t.Fatalf("CompactFile() error = %v", err)
+	}
+	if after >= before {
+		t.Errorf("CompactFile() size %d -> %d, want it to shrink", before, after)
+	}
+
+	store, err = NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("NewBoltStorage() after compaction error = %v", err)
+	}
+	defer store.Close()
+	todos, err := store.GetAllTodos()
+	if err != nil {
+		t.Fatalf("GetAllTodos() error = %v", err)
+	}
+	if len(todos) != 10 {
+		t.Fatalf("GetAllTodos() returned %d todos, want 10", len(todos))
+	}
+	if todos[0].Description != description {
+		t.Error("Compaction should keep the remaining todos intact")
+	}
+}
+
+func TestCompact_ExistingDestination(t *testing.T) {
+	dir := t.TempDir()
+	srcPath := filepath.Join(dir, "doit.db")
+	store, err := NewBoltStorage(srcPath)
+	if err != nil {
+		t.Fatalf("NewBoltStorage() error = %v", err)
+	}
+	store.Close()
+
+	dstPath := filepath.Join(dir, "other.db")
+	other, err := NewBoltStorage(dstPath)
+	if err != nil {
+		t.Fatalf("NewBoltStorage() error = %v", err)
+	}
+	other.Close()
+
+	if err := Compact(srcPath, dstPath); err == nil {
+		t.Error("Compact() should refuse to overwrite an existing file")
+	}
+}
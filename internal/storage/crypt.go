@@ -0,0 +1,253 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	metaBucket = []byte("meta")
+	saltKey    = []byte("salt")
+	// checkKey holds checkPlaintext encrypted, to tell a wrong passphrase
+	// from a corrupt todo
+	checkKey = []byte("check")
+)
+
+const (
+	checkPlaintext = "doit"
+	saltSize       = 16
+	// kdfIterations is the PBKDF2-SHA256 work factor for deriving the key.
+	// PBKDF2 is in the standard library, unlike scrypt, and this is the
+	// OWASP recommended count for it.
+	kdfIterations = 600_000
+)
+
+var (
+	// ErrLocked is returned when reading todos from an encrypted database
+	// before Unlock
+	ErrLocked = errors.New("database is encrypted, unlock it with the passphrase first")
+	// ErrWrongPassphrase is returned by Unlock for a passphrase the database
+	// was not encrypted with
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+	// ErrNotEncrypted is returned by Unlock and Decrypt on a plain database
+	ErrNotEncrypted = errors.New("database is not encrypted")
+	// ErrAlreadyEncrypted is returned by Encrypt on an encrypted database
+	ErrAlreadyEncrypted = errors.New("database is already encrypted")
+)
+
+// Encrypted reports whether the todos are stored encrypted
+func (s *BoltStorage) Encrypted() bool {
+	return s.salt != nil
+}
+
+// Unlock derives the key of an encrypted database from passphrase, so todos
+// can be read and written
+func (s *BoltStorage) Unlock(passphrase string) error {
+	if !s.Encrypted() {
+		return ErrNotEncrypted
+	}
+
+	aead, err := deriveAEAD(passphrase, s.salt)
+	if err != nil {
+		return err
+	}
+	var check []byte
+	err = s.view(func(tx *bolt.Tx) error {
+		check = slices.Clone(tx.Bucket(metaBucket).Get(checkKey))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if plain, err := openSealed(aead, check); err != nil || string(plain) != checkPlaintext {
+		return ErrWrongPassphrase
+	}
+
+	s.aead = aead
+	return nil
+}
+
+// Encrypt encrypts every stored and archived todo with a key derived from
+// passphrase, in one transaction. The streak is left readable.
+func (s *BoltStorage) Encrypt(passphrase string) error {
+	if s.Encrypted() {
+		return ErrAlreadyEncrypted
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := deriveAEAD(passphrase, salt)
+	if err != nil {
+		return err
+	}
+
+	err = s.update(func(tx *bolt.Tx) error {
+		if err := recodeTodos(tx, nil, aead); err != nil {
+			return err
+		}
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		if err := meta.Put(saltKey, salt); err != nil {
+			return err
+		}
+		check, err := seal(aead, []byte(checkPlaintext))
+		if err != nil {
+			return err
+		}
+		return meta.Put(checkKey, check)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.salt, s.aead = salt, aead
+	return nil
+}
+
+// Decrypt stores every todo in plain JSON again. The database must be
+// unlocked.
+func (s *BoltStorage) Decrypt() error {
+	if !s.Encrypted() {
+		return ErrNotEncrypted
+	}
+	if s.aead == nil {
+		return ErrLocked
+	}
+
+	err := s.update(func(tx *bolt.Tx) error {
+		if err := recodeTodos(tx, s.aead, nil); err != nil {
+			return err
+		}
+		meta := tx.Bucket(metaBucket)
+		if err := meta.Delete(saltKey); err != nil {
+			return err
+		}
+		return meta.Delete(checkKey)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.salt, s.aead = nil, nil
+	return nil
+}
+
+// loadSalt reads the salt of an encrypted database, leaving it nil for plain
+// ones
+func (s *BoltStorage) loadSalt(tx *bolt.Tx) {
+	if meta := tx.Bucket(metaBucket); meta != nil {
+		s.salt = slices.Clone(meta.Get(saltKey))
+	}
+}
+
+// encodeTodo marshals a todo for storage, encrypting it when the database is
+// encrypted
+func (s *BoltStorage) encodeTodo(todo *models.Todo) ([]byte, error) {
+	data, err := json.Marshal(todo)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Encrypted() {
+		return data, nil
+	}
+	if s.aead == nil {
+		return nil, ErrLocked
+	}
+	return seal(s.aead, data)
+}
+
+// decodeTodo reverses encodeTodo
+func (s *BoltStorage) decodeTodo(data []byte, todo *models.Todo) error {
+	if s.Encrypted() {
+		if s.aead == nil {
+			return ErrLocked
+		}
+		plain, err := openSealed(s.aead, data)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt todo: %w", err)
+		}
+		data = plain
+	}
+	return json.Unmarshal(data, todo)
+}
+
+// recodeTodos rewrites every stored and archived todo, decrypting it with
+// from and encrypting it with to, either of which may be nil for plain JSON
+func recodeTodos(tx *bolt.Tx, from, to cipher.AEAD) error {
+	for _, name := range [][]byte{todoBucket, archiveBucket} {
+		b := tx.Bucket(name)
+
+		// Collected first, as a bucket can't be written while iterating it
+		var keys, values [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			keys = append(keys, slices.Clone(k))
+			values = append(values, slices.Clone(v))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for i, data := range values {
+			if from != nil {
+				if data, err = openSealed(from, data); err != nil {
+					return fmt.Errorf("failed to decrypt todo %s: %w", keys[i], err)
+				}
+			}
+			if to != nil {
+				if data, err = seal(to, data); err != nil {
+					return err
+				}
+			}
+			if err := b.Put(keys[i], data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deriveAEAD derives an AES-256-GCM cipher from a passphrase and salt
+func deriveAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, kdfIterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts data under a random nonce, which is prepended to the result
+func seal(aead cipher.AEAD, data []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// openSealed decrypts data sealed by seal
+func openSealed(aead cipher.AEAD, data []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBoltStorage_EncryptRoundTrip(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	if err := s.SaveTodo(&models.Todo{ID: "1", Title: "Renew passport"}); err != nil {
+		t.Fatalf("SaveTodo failed: %v", err)
+	}
+	if err := s.Encrypt("correct horse"); err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	if err := s.SaveTodo(&models.Todo{ID: "2", Title: "Pay taxes"}); err != nil {
+		t.Fatalf("SaveTodo after Encrypt() failed: %v", err)
+	}
+
+	s.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(todoBucket).ForEach(func(k, v []byte) error {
+			if bytes.Contains(v, []byte("passport")) || bytes.Contains(v, []byte("taxes")) {
+				t.Errorf("todo %s is stored in plain text", k)
+			}
+			return nil
+		})
+	})
+	s.Close()
+
+	s, err = NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen storage: %v", err)
+	}
+	defer s.Close()
+
+	if !s.Encrypted() {
+		t.Fatal("Encrypted() = false after reopening, want true")
+	}
+	if _, err := s.GetAllTodos(); !errors.Is(err, ErrLocked) {
+		t.Errorf("GetAllTodos() before Unlock() error = %v, want ErrLocked", err)
+	}
+
+	if err := s.Unlock("correct horse"); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	todos, err := s.GetAllTodos()
+	if err != nil || len(todos) != 2 {
+		t.Fatalf("GetAllTodos() = %d todos, %v, want 2", len(todos), err)
+	}
+	if todo, _ := s.GetTodo("1"); todo.Title != "Renew passport" {
+		t.Errorf("GetTodo() title = %q, want %q", todo.Title, "Renew passport")
+	}
+
+	if err := s.Decrypt(); err != nil {
+		t.Fatalf("Decrypt() failed: %v", err)
+	}
+	if s.Encrypted() {
+		t.Error("Encrypted() = true after Decrypt()")
+	}
+	if todo, err := s.GetTodo("2"); err != nil || todo.Title != "Pay taxes" {
+		t.Errorf("GetTodo() after Decrypt() = %v, %v", todo, err)
+	}
+}
+
+func TestBoltStorage_UnlockWrongPassphrase(t *testing.T) {
+	s, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.Unlock("anything"); !errors.Is(err, ErrNotEncrypted) {
+		t.Errorf("Unlock() on a plain database error = %v, want ErrNotEncrypted", err)
+	}
+
+	s.SaveTodo(&models.Todo{ID: "1", Title: "Secret"})
+	if err := s.Encrypt("right"); err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	if err := s.Encrypt("again"); !errors.Is(err, ErrAlreadyEncrypted) {
+		t.Errorf("Encrypt() twice error = %v, want ErrAlreadyEncrypted", err)
+	}
+
+	// Forget the key, as if the database was just opened
+	s.aead = nil
+	if err := s.Unlock("wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Unlock() error = %v, want ErrWrongPassphrase", err)
+	}
+	if _, err := s.GetTodo("1"); !errors.Is(err, ErrLocked) {
+		t.Errorf("GetTodo() after a failed Unlock() error = %v, want ErrLocked", err)
+	}
+}
+
+func TestBoltStorage_UnlockReportsOpenErrors(t *testing.T) {
+	s, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	if err := s.Encrypt("right"); err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	s.Close()
+
+	// A database that can't be opened is not a wrong passphrase
+	if err := s.Unlock("right"); !errors.Is(err, bolt.ErrDatabaseNotOpen) {
+		t.Errorf("Unlock() on a closed storage error = %v, want ErrDatabaseNotOpen", err)
+	}
+}
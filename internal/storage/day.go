@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// TodosOnDay returns the todos due on the same local calendar day as day, in
+// the order given
+func TodosOnDay(todos []*models.Todo, day time.Time) []*models.Todo {
+	var due []*models.Todo
+	for _, todo := range todos {
+		if todo.Deadline != nil && sameDay(*todo.Deadline, day) {
+			due = append(due, todo)
+		}
+	}
+	return due
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestTodosOnDay(t *testing.T) {
+	day := time.Date(2025, 11, 16, 0, 0, 0, 0, time.Local)
+	at := func(d, h, m int) *time.Time {
+		deadline := time.Date(2025, 11, d, h, m, 0, 0, time.Local)
+		return &deadline
+	}
+
+	tests := []struct {
+		name     string
+		deadline *time.Time
+		want     bool
+	}{
+		{"Midnight", at(16, 0, 0), true},
+		{"Afternoon", at(16, 14, 30), true},
+		{"Last minute", at(16, 23, 59), true},
+		{"Next midnight", at(17, 0, 0), false},
+		{"Day before", at(15, 23, 59), false},
+		{"No deadline", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := &models.Todo{ID: "1", Deadline: tt.deadline}
+			if got := len(TodosOnDay([]*models.Todo{todo}, day)) == 1; got != tt.want {
+				t.Errorf("TodosOnDay() includes the todo = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
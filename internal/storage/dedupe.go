@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// DuplicateMerge is a group of incomplete todos with the same title merged
+// into one: Kept is the merged todo, saved under the ID of the earliest
+// created one, and Removed are the others
+type DuplicateMerge struct {
+	Kept    *models.Todo
+	Removed []*models.Todo
+}
+
+// MergeDuplicates groups the incomplete todos whose titles match once trimmed
+// and compared case-insensitively, and merges each group of two or more. The
+// merged todo keeps the earliest CreatedAt and the soonest deadline, with the
+// distinct descriptions and notes joined by blank lines and the tags,
+// subtasks and attachments of all of them. Groups whose joined description
+// would be too long are left unmerged and reported. The todos passed in are
+// not modified.
+func MergeDuplicates(todos []*models.Todo) ([]DuplicateMerge, []error) {
+	groups := make(map[string][]*models.Todo)
+	var titles []string
+	for _, todo := range todos {
+		if todo.Completed {
+			continue
+		}
+		title := strings.ToLower(strings.TrimSpace(todo.Title))
+		if _, seen := groups[title]; !seen {
+			titles = append(titles, title)
+		}
+		groups[title] = append(groups[title], todo)
+	}
+
+	var merges []DuplicateMerge
+	var errs []error
+	for _, title := range titles {
+		group := groups[title]
+		if len(group) < 2 {
+			continue
+		}
+		group = slices.Clone(group)
+		slices.SortStableFunc(group, func(a, b *models.Todo) int {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		})
+		kept, err := mergeGroup(group)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		merges = append(merges, DuplicateMerge{Kept: kept, Removed: group[1:]})
+	}
+	return merges, errs
+}
+
+// mergeGroup merges todos, sorted by creation, into a copy of the first
+func mergeGroup(group []*models.Todo) (*models.Todo, error) {
+	kept := *group[0]
+	kept.Tags = slices.Clone(kept.Tags)
+	kept.Subtasks = nil
+	kept.Attachments = nil
+
+	var descriptions, notes []string
+	for _, todo := range group {
+		if todo.Deadline != nil && (kept.Deadline == nil || todo.Deadline.Before(*kept.Deadline)) {
+			deadline := *todo.Deadline
+			kept.Deadline = &deadline
+		}
+		if description := strings.TrimSpace(todo.Description); description != "" && !slices.Contains(descriptions, description) {
+			descriptions = append(descriptions, description)
+		}
+		if note := strings.TrimSpace(todo.CompletionNote); note != "" && !slices.Contains(notes, note) {
+			notes = append(notes, note)
+		}
+		for _, tag := range todo.Tags {
+			kept.AddTag(tag)
+		}
+		for _, subtask := range todo.Subtasks {
+			kept.Subtasks = mergeSubtask(kept.Subtasks, subtask)
+		}
+		for _, attachment := range todo.Attachments {
+			if !slices.Contains(kept.Attachments, attachment) {
+				kept.Attachments = append(kept.Attachments, attachment)
+			}
+		}
+	}
+	kept.Description = strings.Join(descriptions, "\n\n")
+	kept.CompletionNote = strings.Join(notes, "\n\n")
+	if utf8.RuneCountInString(kept.Description) > models.MaxDescriptionLength {
+		return nil, fmt.Errorf("%q: merged description exceeds maximum length of %d characters", kept.Title, models.MaxDescriptionLength)
+	}
+	return &kept, nil
+}
+
+// mergeSubtask adds subtask to subtasks unless one with the same title is
+// already there, which is then done if either of them is
+func mergeSubtask(subtasks []models.Subtask, subtask models.Subtask) []models.Subtask {
+	for i := range subtasks {
+		if subtasks[i].Title == subtask.Title {
+			subtasks[i].Done = subtasks[i].Done || subtask.Done
+			return subtasks
+		}
+	}
+	return append(subtasks, subtask)
+}
+
+// ApplyMerges saves each merged todo and deletes the duplicates it replaces,
+// all in one transaction. Nothing is changed when any of them fails.
+func (s *BoltStorage) ApplyMerges(merges []DuplicateMerge) error {
+	return s.update(func(tx *bolt.Tx) error {
+		now := time.Now()
+
+		for _, merge := range merges {
+			kept := *merge.Kept
+			kept.UpdatedAt = now
+			SanitizeTodo(&kept)
+
+			if err := s.putTodo(tx, &kept); err != nil {
+				return err
+			}
+
+			for _, todo := range merge.Removed {
+				if err := s.deleteTodo(tx, todo.ID); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
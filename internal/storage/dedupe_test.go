@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestMergeDuplicates(t *testing.T) {
+	base := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+	soon, later := base.AddDate(0, 0, 2), base.AddDate(0, 0, 9)
+
+	first := &models.Todo{ID: "1", Title: "Call Mum", Description: "About the trip", Tags: []string{"family"},
+		Deadline: &later, CreatedAt: base,
+		Subtasks: []models.Subtask{{Title: "Find number"}}, Attachments: []string{"trip.pdf"}}
+	second := &models.Todo{ID: "2", Title: "  call mum ", Description: "Birthday plans", Tags: []string{"family", "phone"},
+		Deadline: &soon, CreatedAt: base.Add(time.Hour),
+		Subtasks: []models.Subtask{{Title: "Find number", Done: true}, {Title: "Pick a date"}}, Attachments: []string{"trip.pdf", "cake.jpg"}}
+	unique := &models.Todo{ID: "3", Title: "Call the bank", CreatedAt: base}
+	done := &models.Todo{ID: "4", Title: "Call mum", Completed: true, CreatedAt: base.Add(-time.Hour)}
+
+	// The later created todo comes first to check the merge keeps the earliest
+	merges, errs := MergeDuplicates([]*models.Todo{second, unique, first, done})
+
+	if len(merges) != 1 || len(errs) != 0 {
+		t.Fatalf("MergeDuplicates() returned %d merges, want 1", len(merges))
+	}
+	kept := merges[0].Kept
+	if kept.ID != "1" || !kept.CreatedAt.Equal(base) {
+		t.Errorf("Kept = %s created %v, want 1 created %v", kept.ID, kept.CreatedAt, base)
+	}
+	if kept.Deadline == nil || !kept.Deadline.Equal(soon) {
+		t.Errorf("Kept deadline = %v, want the soonest %v", kept.Deadline, soon)
+	}
+	if want := "About the trip\n\nBirthday plans"; kept.Description != want {
+		t.Errorf("Kept description = %q, want %q", kept.Description, want)
+	}
+	if want := []string{"family", "phone"}; !slices.Equal(kept.Tags, want) {
+		t.Errorf("Kept tags = %v, want %v", kept.Tags, want)
+	}
+	if want := []models.Subtask{{Title: "Find number", Done: true}, {Title: "Pick a date"}}; !slices.Equal(kept.Subtasks, want) {
+		t.Errorf("Kept subtasks = %v, want %v", kept.Subtasks, want)
+	}
+	if want := []string{"trip.pdf", "cake.jpg"}; !slices.Equal(kept.Attachments, want) {
+		t.Errorf("Kept attachments = %v, want %v", kept.Attachments, want)
+	}
+	if removed := merges[0].Removed; len(removed) != 1 || removed[0].ID != "2" {
+		t.Errorf("Removed = %v, want todo 2", removed)
+	}
+
+	if first.Deadline != &later || first.Description != "About the trip" || len(first.Tags) != 1 ||
+		first.Subtasks[0].Done || len(first.Attachments) != 1 {
+		t.Error("MergeDuplicates() modified the todos passed in")
+	}
+
+	if got, _ := MergeDuplicates([]*models.Todo{unique, done, first}); len(got) != 0 {
+		t.Errorf("MergeDuplicates() with unique titles = %v, want none", got)
+	}
+}
+
+func TestMergeDuplicates_DescriptionTooLong(t *testing.T) {
+	long := strings.Repeat("a", models.MaxDescriptionLength-100)
+	todos := []*models.Todo{
+		{ID: "1", Title: "Write report", Description: long + "1"},
+		{ID: "2", Title: "Write report", Description: long + "2"},
+		{ID: "3", Title: "Pay rent"},
+		{ID: "4", Title: "Pay rent"},
+	}
+
+	merges, errs := MergeDuplicates(todos)
+	if len(merges) != 1 || merges[0].Kept.Title != "Pay rent" {
+		t.Errorf("MergeDuplicates() = %v, want only the rent todos merged", merges)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "Write report") {
+		t.Errorf("MergeDuplicates() errors = %v, want the report todos reported", errs)
+	}
+}
+
+func TestBoltStorage_ApplyMerges(t *testing.T) {
+	s, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	todos := []*models.Todo{
+		{ID: "1", Title: "Water plants", Description: "Balcony"},
+		{ID: "2", Title: "water plants", Description: "Kitchen"},
+		{ID: "3", Title: "Pay rent"},
+	}
+	for _, todo := range todos {
+		if err := s.SaveTodo(todo); err != nil {
+			t.Fatalf("SaveTodo() error = %v", err)
+		}
+	}
+
+	stored, err := s.GetAllTodos()
+	if err != nil {
+		t.Fatalf("GetAllTodos() error = %v", err)
+	}
+	merges, _ := MergeDuplicates(stored)
+	if err := s.ApplyMerges(merges); err != nil {
+		t.Fatalf("ApplyMerges() error = %v", err)
+	}
+
+	remaining, err := s.GetAllTodos()
+	if err != nil {
+		t.Fatalf("GetAllTodos() error = %v", err)
+	}
+	if len(remaining) != 2 {
+		t.Fatalf("GetAllTodos() returned %d todos after merging, want 2", len(remaining))
+	}
+	kept, err := s.GetTodo("1")
+	if err != nil || kept == nil {
+		t.Fatalf("GetTodo(1) = %v, %v", kept, err)
+	}
+	if kept.Description != "Balcony\n\nKitchen" {
+		t.Errorf("Merged description = %q", kept.Description)
+	}
+	if gone, _ := s.GetTodo("2"); gone != nil {
+		t.Error("Expected the duplicate to be deleted")
+	}
+}
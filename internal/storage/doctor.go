@@ -0,0 +1,317 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ProblemKind identifies the check that found a problem
+type ProblemKind int
+
+const (
+	// ProblemUnparseable is a stored record that isn't a valid todo
+	ProblemUnparseable ProblemKind = iota
+	// ProblemClock is a todo created in the future or after its deadline
+	ProblemClock
+	// ProblemMissingCompletedAt is a completed todo without a completion time
+	ProblemMissingCompletedAt
+	// ProblemStreak is a streak whose counts contradict each other or the todos
+	ProblemStreak
+	// ProblemIndex is an upcoming index entry that is stale or missing
+	ProblemIndex
+)
+
+// Problem is an issue found in the database by Diagnose
+type Problem struct {
+	Kind ProblemKind
+	// ID is the todo or record the problem is about, "" for the streak
+	ID      string
+	Message string
+}
+
+// Fixable reports whether Repair can safely fix the problem. Unparseable
+// records and clock skew need a human to decide what the data should be.
+func (p Problem) Fixable() bool {
+	switch p.Kind {
+	case ProblemMissingCompletedAt, ProblemStreak, ProblemIndex:
+		return true
+	default:
+		return false
+	}
+}
+
+// ClockAnomaly describes what is wrong with a todo's timestamps, usually
+// because the system clock was off when it was created, or returns "" when
+// they are consistent
+func ClockAnomaly(todo *models.Todo, now time.Time) string {
+	switch {
+	case todo.CreatedAt.After(now):
+		return "created in the future"
+	case todo.Deadline != nil && todo.CreatedAt.After(*todo.Deadline):
+		return "created after its deadline"
+	default:
+		return ""
+	}
+}
+
+// DetectClockAnomalies returns the todos created in the future or after their
+// deadline, which make "days until" and elapsed time meaningless
+func DetectClockAnomalies(todos []*models.Todo, now time.Time) []*models.Todo {
+	var anomalies []*models.Todo
+	for _, todo := range todos {
+		if todo != nil && ClockAnomaly(todo, now) != "" {
+			anomalies = append(anomalies, todo)
+		}
+	}
+	return anomalies
+}
+
+// CheckClockAnomalies reports the todos DetectClockAnomalies flags
+func CheckClockAnomalies(todos []*models.Todo, now time.Time) []Problem {
+	var problems []Problem
+	for _, todo := range DetectClockAnomalies(todos, now) {
+		problems = append(problems, Problem{
+			Kind:    ProblemClock,
+			ID:      todo.ID,
+			Message: fmt.Sprintf("%q was %s", todo.Title, ClockAnomaly(todo, now)),
+		})
+	}
+	return problems
+}
+
+// CheckMissingCompletedAt reports completed todos without a CompletedAt,
+// which are left out of the review and auto-archiving
+func CheckMissingCompletedAt(todos []*models.Todo) []Problem {
+	var problems []Problem
+	for _, todo := range todos {
+		if todo.Completed && todo.CompletedAt == nil {
+			problems = append(problems, Problem{
+				Kind:    ProblemMissingCompletedAt,
+				ID:      todo.ID,
+				Message: fmt.Sprintf("%q is completed but has no completion time", todo.Title),
+			})
+		}
+	}
+	return problems
+}
+
+// CheckStreak reports a max streak below the current streak or the longest
+// run of days, and a total below the number of completed todos. The total
+// may be higher, since reopened and deleted todos still count towards it.
+func CheckStreak(streak *Streak, completed int) []Problem {
+	var problems []Problem
+	if streak.MaxStreak < streak.CurrentStreak {
+		problems = append(problems, Problem{
+			Kind:    ProblemStreak,
+			Message: fmt.Sprintf("max streak %d is below the current streak %d", streak.MaxStreak, streak.CurrentStreak),
+		})
+	}
+	if streak.TotalCompleted < completed {
+		problems = append(problems, Problem{
+			Kind:    ProblemStreak,
+			Message: fmt.Sprintf("total completed %d is below the %d completed todos", streak.TotalCompleted, completed),
+		})
+	}
+	return problems
+}
+
+// FixStreak raises the max streak and total so CheckStreak passes, without
+// touching the current streak or the completions per day
+func FixStreak(streak *Streak, completed int) {
+	longest := *streak
+	RecomputeStreak(&longest, streak.LastCompletedAt)
+	streak.MaxStreak = max(streak.MaxStreak, streak.CurrentStreak, longest.MaxStreak)
+	streak.TotalCompleted = max(streak.TotalCompleted, completed)
+}
+
+// scanBucket decodes every todo in a bucket, collecting the keys of records
+// that can't be decoded instead of failing
+func (s *BoltStorage) scanBucket(b *bolt.Bucket) (todos []*models.Todo, unparseable []string, err error) {
+	err = b.ForEach(func(k, v []byte) error {
+		var todo models.Todo
+		if err := s.decodeTodo(v, &todo); err != nil {
+			unparseable = append(unparseable, string(k))
+			return nil
+		}
+		todos = append(todos, &todo)
+		return nil
+	})
+	return todos, unparseable, err
+}
+
+// checkUpcomingIndex reports index entries that point at missing or changed
+// todos, and incomplete todos with a deadline that aren't indexed
+func checkUpcomingIndex(tx *bolt.Tx, todos []*models.Todo) []Problem {
+	var problems []Problem
+	index := tx.Bucket(upcomingBucket)
+
+	expected := make(map[string]bool)
+	for _, todo := range todos {
+		if key := upcomingKey(todo); key != nil {
+			expected[string(key)] = true
+			if index.Get(key) == nil {
+				problems = append(problems, Problem{
+					Kind:    ProblemIndex,
+					ID:      todo.ID,
+					Message: fmt.Sprintf("%q is missing from the upcoming index", todo.Title),
+				})
+			}
+		}
+	}
+
+	index.ForEach(func(k, v []byte) error {
+		if !expected[string(k)] {
+			problems = append(problems, Problem{
+				Kind:    ProblemIndex,
+				ID:      string(v),
+				Message: "stale upcoming index entry " + string(k),
+			})
+		}
+		return nil
+	})
+	return problems
+}
+
+// Diagnose runs every check over the stored and archived todos and the streak
+func (s *BoltStorage) Diagnose(now time.Time) ([]Problem, error) {
+	var problems []Problem
+
+	err := s.view(func(tx *bolt.Tx) error {
+		todos, unparseable, err := s.scanBucket(tx.Bucket(todoBucket))
+		if err != nil {
+			return err
+		}
+		archived, unparseableArchived, err := s.scanBucket(tx.Bucket(archiveBucket))
+		if err != nil {
+			return err
+		}
+
+		for _, key := range unparseable {
+			problems = append(problems, Problem{Kind: ProblemUnparseable, ID: key, Message: "record is not a valid todo"})
+		}
+		for _, key := range unparseableArchived {
+			problems = append(problems, Problem{Kind: ProblemUnparseable, ID: key, Message: "archived record is not a valid todo"})
+		}
+
+		problems = append(problems, CheckClockAnomalies(todos, now)...)
+		problems = append(problems, CheckMissingCompletedAt(todos)...)
+		problems = append(problems, CheckMissingCompletedAt(archived)...)
+		problems = append(problems, checkUpcomingIndex(tx, todos)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	streak, err := s.GetStreak()
+	if err != nil {
+		problems = append(problems, Problem{Kind: ProblemUnparseable, Message: "streak is not valid: " + err.Error()})
+		return problems, nil
+	}
+	completed, err := s.countCompleted()
+	if err != nil {
+		return nil, err
+	}
+	problems = append(problems, CheckStreak(streak, completed)...)
+
+	return problems, nil
+}
+
+// countCompleted counts the completed todos, archived ones included
+func (s *BoltStorage) countCompleted() (int, error) {
+	count := 0
+	err := s.view(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{todoBucket, archiveBucket} {
+			todos, _, err := s.scanBucket(tx.Bucket(name))
+			if err != nil {
+				return err
+			}
+			for _, todo := range todos {
+				if todo.Completed {
+					count++
+				}
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Repair fixes the problems Diagnose finds that are safe to fix: completion
+// times are backfilled from UpdatedAt, the upcoming index is rebuilt and the
+// streak counts are raised to match. It returns the problems left, which
+// need fixing by hand.
+func (s *BoltStorage) Repair(now time.Time) ([]Problem, error) {
+	err := s.update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{todoBucket, archiveBucket} {
+			if err := s.backfillCompletedAt(tx, name); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.DeleteBucket(upcomingBucket); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(upcomingBucket); err != nil {
+			return err
+		}
+		return s.rebuildUpcomingIndex(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// An unreadable streak is left for Diagnose to report
+	streak, err := s.GetStreak()
+	if err == nil {
+		completed, err := s.countCompleted()
+		if err != nil {
+			return nil, err
+		}
+		FixStreak(streak, completed)
+		if err := s.UpdateStreak(streak); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.Diagnose(now)
+}
+
+// backfillCompletedAt sets the CompletedAt of completed todos missing one to
+// when they were last updated, or created if that is unknown
+func (s *BoltStorage) backfillCompletedAt(tx *bolt.Tx, name []byte) error {
+	b := tx.Bucket(name)
+	todos, _, err := s.scanBucket(b)
+	if err != nil {
+		return err
+	}
+	for _, todo := range todos {
+		if !todo.Completed || todo.CompletedAt != nil {
+			continue
+		}
+		completedAt := todo.UpdatedAt
+		if completedAt.IsZero() {
+			completedAt = todo.CreatedAt
+		}
+		todo.CompletedAt = &completedAt
+
+		if string(name) == string(todoBucket) {
+			if err := s.putTodo(tx, todo); err != nil {
+				return err
+			}
+			continue
+		}
+		// Archived todos are not in the upcoming index
+		data, err := s.encodeTodo(todo)
+		if err != nil {
+			return err
+		}
+		if err := b.Put([]byte(todo.ID), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestDetectClockAnomalies(t *testing.T) {
+	now := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return now.Add(d) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name   string
+		todo   *models.Todo
+		reason string
+	}{
+		{"consistent", &models.Todo{CreatedAt: at(-time.Hour), Deadline: ptr(at(time.Hour))}, ""},
+		{"no deadline", &models.Todo{CreatedAt: at(-time.Hour)}, ""},
+		{"created now", &models.Todo{CreatedAt: now}, ""},
+		{"created in the future", &models.Todo{CreatedAt: at(24 * time.Hour)}, "created in the future"},
+		{"created after its deadline", &models.Todo{CreatedAt: at(-time.Hour), Deadline: ptr(at(-2 * time.Hour))}, "created after its deadline"},
+		{"future and after deadline", &models.Todo{CreatedAt: at(48 * time.Hour), Deadline: ptr(at(24 * time.Hour))}, "created in the future"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClockAnomaly(tt.todo, now); got != tt.reason {
+				t.Errorf("ClockAnomaly() = %q, want %q", got, tt.reason)
+			}
+
+			anomalies := DetectClockAnomalies([]*models.Todo{tt.todo, nil}, now)
+			if flagged := len(anomalies) == 1; flagged != (tt.reason != "") {
+				t.Errorf("DetectClockAnomalies() = %d todos, want flagged %v", len(anomalies), tt.reason != "")
+			}
+		})
+	}
+}
+
+func TestCheckStreak(t *testing.T) {
+	tests := []struct {
+		name      string
+		streak    Streak
+		completed int
+		want      int
+	}{
+		{"consistent", Streak{CurrentStreak: 2, MaxStreak: 5, TotalCompleted: 10}, 10, 0},
+		{"total above completed todos", Streak{CurrentStreak: 2, MaxStreak: 5, TotalCompleted: 12}, 10, 0},
+		{"max below current", Streak{CurrentStreak: 6, MaxStreak: 5, TotalCompleted: 10}, 10, 1},
+		{"total below completed todos", Streak{CurrentStreak: 2, MaxStreak: 5, TotalCompleted: 3}, 10, 1},
+		{"both", Streak{CurrentStreak: 6, MaxStreak: 5, TotalCompleted: 3}, 10, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := CheckStreak(&tt.streak, tt.completed)
+			if len(problems) != tt.want {
+				t.Fatalf("CheckStreak() = %v, want %d problems", problems, tt.want)
+			}
+			for _, problem := range problems {
+				if problem.Kind != ProblemStreak || !problem.Fixable() {
+					t.Errorf("CheckStreak() problem = %+v, want a fixable streak problem", problem)
+				}
+			}
+
+			FixStreak(&tt.streak, tt.completed)
+			if problems := CheckStreak(&tt.streak, tt.completed); len(problems) != 0 {
+				t.Errorf("CheckStreak() after FixStreak() = %v, want none", problems)
+			}
+		})
+	}
+}
+
+func TestCheckMissingCompletedAt(t *testing.T) {
+	completedAt := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+	todos := []*models.Todo{
+		{ID: "1", Title: "Open"},
+		{ID: "2", Title: "Done", Completed: true, CompletedAt: &completedAt},
+		{ID: "3", Title: "Done long ago", Completed: true},
+		{ID: "4", Title: "Reopened", CompletedAt: &completedAt},
+	}
+
+	problems := CheckMissingCompletedAt(todos)
+	if len(problems) != 1 || problems[0].ID != "3" {
+		t.Fatalf("CheckMissingCompletedAt() = %v, want todo 3", problems)
+	}
+	if !problems[0].Fixable() {
+		t.Error("A missing CompletedAt should be fixable")
+	}
+}
+
+func TestBoltStorage_DiagnoseAndRepair(t *testing.T) {
+	s, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	updated := now.Add(-time.Hour)
+	deadline := now.Add(24 * time.Hour)
+	todos := []*models.Todo{
+		{ID: "1", Title: "Done", Completed: true, CreatedAt: updated, UpdatedAt: updated},
+		{ID: "2", Title: "Upcoming", Deadline: &deadline, CreatedAt: updated, UpdatedAt: updated},
+	}
+	if err := s.SaveTodosPreservingTimestamps(todos); err != nil {
+		t.Fatalf("SaveTodos failed: %v", err)
+	}
+	if err := s.UpdateStreak(&Streak{CurrentStreak: 3, MaxStreak: 1, DailyCompletions: map[string]int{}}); err != nil {
+		t.Fatalf("UpdateStreak failed: %v", err)
+	}
+	err = s.update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(todoBucket).Put([]byte("broken"), []byte("{not json")); err != nil {
+			return err
+		}
+		return tx.Bucket(upcomingBucket).Delete(upcomingKey(todos[1]))
+	})
+	if err != nil {
+		t.Fatalf("Failed to corrupt database: %v", err)
+	}
+
+	problems, err := s.Diagnose(now)
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	kinds := make(map[ProblemKind]int)
+	for _, problem := range problems {
+		kinds[problem.Kind]++
+	}
+	want := map[ProblemKind]int{
+		ProblemUnparseable:        1,
+		ProblemMissingCompletedAt: 1,
+		ProblemStreak:             2,
+		ProblemIndex:              1,
+	}
+	for kind, count := range want {
+		if kinds[kind] != count {
+			t.Errorf("Diagnose() found %d problems of kind %d, want %d: %v", kinds[kind], kind, count, problems)
+		}
+	}
+
+	remaining, err := s.Repair(now)
+	if err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].Kind != ProblemUnparseable {
+		t.Errorf("Repair() left %v, want only the unparseable record", remaining)
+	}
+
+	todo, err := s.GetTodo("1")
+	if err != nil {
+		t.Fatalf("GetTodo failed: %v", err)
+	}
+	if todo.CompletedAt == nil || !todo.CompletedAt.Equal(updated) {
+		t.Errorf("Repaired CompletedAt = %v, want %v", todo.CompletedAt, updated)
+	}
+	upcoming, _, err := s.GetTodosPage(0, 10, SortDeadline)
+	if err != nil || len(upcoming) != 1 {
+		t.Errorf("GetTodosPage() after Repair() = %v, %v, want the reindexed todo", upcoming, err)
+	}
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// indexTimeLayout is a fixed width UTC layout so that index keys sort in
+// deadline order byte by byte
+const indexTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// upcomingKey returns the upcoming index key for a todo, or nil when the todo
+// is completed or has no deadline and so does not belong in the index
+func upcomingKey(todo *models.Todo) []byte {
+	if todo == nil || todo.Completed || todo.Deadline == nil {
+		return nil
+	}
+	return []byte(todo.Deadline.UTC().Format(indexTimeLayout) + "/" + todo.ID)
+}
+
+// reindexTodo replaces the index entry of the stored todo with the entry of
+// its new version. Either may be nil.
+func reindexTodo(tx *bolt.Tx, stored, todo *models.Todo) error {
+	b := tx.Bucket(upcomingBucket)
+	if key := upcomingKey(stored); key != nil && b.Get(key) != nil {
+		if err := b.Delete(key); err != nil {
+			return err
+		}
+		if err := countKeys(b, -1); err != nil {
+			return err
+		}
+	}
+	if key := upcomingKey(todo); key != nil {
+		if b.Get(key) == nil {
+			if err := countKeys(b, 1); err != nil {
+				return err
+			}
+		}
+		return b.Put(key, []byte(todo.ID))
+	}
+	return nil
+}
+
+// storedTodo reads a todo inside a transaction, returning nil if it does not
+// exist
+func (s *BoltStorage) storedTodo(tx *bolt.Tx, id string) (*models.Todo, error) {
+	data := tx.Bucket(todoBucket).Get([]byte(id))
+	if data == nil {
+		return nil, nil
+	}
+	var todo models.Todo
+	if err := s.decodeTodo(data, &todo); err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// rebuildUpcomingIndex fills the upcoming index from every stored todo, for
+// databases created before the index existed and for -doctor. Records that
+// can't be decoded are skipped, so that the database still opens and -doctor
+// can report them.
+func (s *BoltStorage) rebuildUpcomingIndex(tx *bolt.Tx) error {
+	todos, _, err := s.scanBucket(tx.Bucket(todoBucket))
+	if err != nil {
+		return err
+	}
+	for _, todo := range todos {
+		if err := reindexTodo(tx, nil, todo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
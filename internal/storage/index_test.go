@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+func upcomingIDs(t *testing.T, s *BoltStorage) []string {
+	t.Helper()
+	todos, total, err := s.GetTodosPage(0, 10, SortDeadline)
+	if err != nil {
+		t.Fatalf("GetTodosPage failed: %v", err)
+	}
+	var ids []string
+	for _, todo := range todos {
+		ids = append(ids, todo.ID)
+	}
+
+	var indexed int
+	s.view(func(tx *bolt.Tx) error {
+		indexed = tx.Bucket(upcomingBucket).Stats().KeyN
+		return nil
+	})
+	if indexed != len(ids) || total != len(ids) {
+		t.Errorf("Upcoming index has %d keys counted as %d, want %d", indexed, total, len(ids))
+	}
+	return ids
+}
+
+func TestBoltStorage_UpcomingIndex(t *testing.T) {
+	s, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	later := &models.Todo{ID: "later", Title: "Later", Deadline: timePtr(now.Add(48 * time.Hour))}
+	soon := &models.Todo{ID: "soon", Title: "Soon", Deadline: timePtr(now.Add(time.Hour))}
+	someday := &models.Todo{ID: "someday", Title: "Someday"}
+
+	steps := []struct {
+		name   string
+		action func() error
+		want   []string
+	}{
+		{"create", func() error {
+			for _, todo := range []*models.Todo{later, soon, someday} {
+				if err := s.SaveTodo(todo); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, []string{"soon", "later"}},
+		{"complete", func() error {
+			soon.MarkComplete()
+			return s.UpdateTodo(soon)
+		}, []string{"later"}},
+		{"reopen", func() error {
+			soon.MarkIncomplete()
+			return s.UpdateTodo(soon)
+		}, []string{"soon", "later"}},
+		{"move deadline", func() error {
+			later.Deadline = timePtr(now.Add(time.Minute))
+			return s.UpdateTodo(later)
+		}, []string{"later", "soon"}},
+		{"add deadline", func() error {
+			someday.Deadline = timePtr(now.Add(24 * time.Hour))
+			return s.UpdateTodo(someday)
+		}, []string{"later", "soon", "someday"}},
+		{"delete", func() error {
+			return s.DeleteTodo("later")
+		}, []string{"soon", "someday"}},
+	}
+
+	for _, step := range steps {
+		if err := step.action(); err != nil {
+			t.Fatalf("%s failed: %v", step.name, err)
+		}
+		if got := upcomingIDs(t, s); !slices.Equal(got, step.want) {
+			t.Errorf("after %s GetTodosPage() = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestNewBoltStorage_RebuildsUpcomingIndex(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	now := time.Now()
+	s.SaveTodo(&models.Todo{ID: "b", Title: "B", Deadline: timePtr(now.Add(2 * time.Hour))})
+	s.SaveTodo(&models.Todo{ID: "a", Title: "A", Deadline: timePtr(now.Add(time.Hour))})
+	s.SaveTodo(&models.Todo{ID: "c", Title: "C"})
+
+	// Simulate a database from before the index existed
+	s.update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket(upcomingBucket)
+	})
+	s.Close()
+
+	s, err = NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen storage: %v", err)
+	}
+	defer s.Close()
+
+	if got, want := upcomingIDs(t, s), []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("GetTodosPage() = %v, want %v", got, want)
+	}
+}
+
+func TestNewBoltStorage_RebuildsUpcomingIndexPastUnreadable(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	s.SaveTodo(&models.Todo{ID: "a", Title: "A", Deadline: timePtr(time.Now().Add(time.Hour))})
+
+	// Simulate a database from before the index existed, with a record
+	// that can't be decoded
+	err = s.update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(todoBucket).Put([]byte("b"), []byte("{not json")); err != nil {
+			return err
+		}
+		return tx.DeleteBucket(upcomingBucket)
+	})
+	if err != nil {
+		t.Fatalf("Failed to corrupt database: %v", err)
+	}
+	s.Close()
+
+	s, err = NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("NewBoltStorage() error = %v, want the database to open for -doctor", err)
+	}
+	defer s.Close()
+
+	if got, want := upcomingIDs(t, s), []string{"a"}; !slices.Equal(got, want) {
+		t.Errorf("GetTodosPage() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// OpenBoltStorageReadOnly opens an existing database without modifying it
+func OpenBoltStorageReadOnly(dbPath string) (*BoltStorage, error) {
+	s := &BoltStorage{path: dbPath, readOnly: true}
+	err := s.view(func(tx *bolt.Tx) error {
+		if tx.Bucket(todoBucket) == nil || tx.Bucket(streakBucket) == nil {
+			return errors.New("not a doit database")
+		}
+		s.loadSalt(tx)
+		s.version.Store(readVersion(tx))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MergeTodos returns the incoming todos to import: those with an ID that
+// does not exist yet, and those that were updated more recently than the
+// existing todo with the same ID. The rest are counted as skipped.
+func MergeTodos(existing, incoming []*models.Todo) (imported []*models.Todo, skipped int) {
+	byID := make(map[string]*models.Todo, len(existing))
+	for _, todo := range existing {
+		byID[todo.ID] = todo
+	}
+
+	for _, todo := range incoming {
+		if current, ok := byID[todo.ID]; ok && !todo.UpdatedAt.After(current.UpdatedAt) {
+			skipped++
+			continue
+		}
+		imported = append(imported, todo)
+	}
+	return imported, skipped
+}
+
+// MergeStreaks combines two streaks by summing their completions per day and
+// recomputing the current and max streak as of the latest completion. The
+// weekly goal and capacity of a win when both have one.
+func MergeStreaks(a, b *Streak) *Streak {
+	merged := &Streak{
+		TotalCompleted:   a.TotalCompleted + b.TotalCompleted,
+		LastCompletedAt:  a.LastCompletedAt,
+		DailyCompletions: make(map[string]int),
+		WeeklyGoal:       a.WeeklyGoal,
+		WeeklyCapacity:   a.WeeklyCapacity,
+	}
+	if merged.WeeklyGoal == 0 {
+		merged.WeeklyGoal = b.WeeklyGoal
+	}
+	if merged.WeeklyCapacity == 0 {
+		merged.WeeklyCapacity = b.WeeklyCapacity
+	}
+	if b.LastCompletedAt.After(merged.LastCompletedAt) {
+		merged.LastCompletedAt = b.LastCompletedAt
+	}
+
+	for day, count := range a.DailyCompletions {
+		merged.DailyCompletions[day] += count
+	}
+	for day, count := range b.DailyCompletions {
+		merged.DailyCompletions[day] += count
+	}
+
+	RecomputeStreak(merged, merged.LastCompletedAt)
+	return merged
+}
+
+// ImportTodos writes todos as they are, keeping their timestamps and leaving
+// the streak untouched
+func (s *BoltStorage) ImportTodos(todos []*models.Todo) error {
+	return s.update(func(tx *bolt.Tx) error {
+		for _, todo := range todos {
+			SanitizeTodo(todo)
+
+			if err := s.putTodo(tx, todo); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
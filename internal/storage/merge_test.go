@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestMergeStreaks(t *testing.T) {
+	last := time.Date(2025, 11, 16, 18, 0, 0, 0, time.Local)
+	a := &Streak{
+		TotalCompleted:  4,
+		LastCompletedAt: last.AddDate(0, 0, -2),
+		DailyCompletions: map[string]int{
+			"2025-11-10": 1,
+			"2025-11-11": 1,
+			"2025-11-14": 2,
+		},
+	}
+	b := &Streak{
+		TotalCompleted:  3,
+		LastCompletedAt: last,
+		DailyCompletions: map[string]int{
+			"2025-11-14": 1,
+			"2025-11-15": 1,
+			"2025-11-16": 1,
+		},
+	}
+
+	merged := MergeStreaks(a, b)
+
+	if merged.TotalCompleted != 7 {
+		t.Errorf("TotalCompleted = %v, want %v", merged.TotalCompleted, 7)
+	}
+	if !merged.LastCompletedAt.Equal(last) {
+		t.Errorf("LastCompletedAt = %v, want %v", merged.LastCompletedAt, last)
+	}
+	if merged.DailyCompletions["2025-11-14"] != 3 {
+		t.Errorf("DailyCompletions[2025-11-14] = %v, want %v", merged.DailyCompletions["2025-11-14"], 3)
+	}
+	// 14th to 16th only forms a run once both databases are combined
+	if merged.CurrentStreak != 3 {
+		t.Errorf("CurrentStreak = %v, want %v", merged.CurrentStreak, 3)
+	}
+	if merged.MaxStreak != 3 {
+		t.Errorf("MaxStreak = %v, want %v", merged.MaxStreak, 3)
+	}
+	if a.DailyCompletions["2025-11-14"] != 2 {
+		t.Error("MergeStreaks should not modify its inputs")
+	}
+}
+
+func TestMergeTodos(t *testing.T) {
+	base := time.Date(2025, 11, 16, 12, 0, 0, 0, time.UTC)
+	existing := []*models.Todo{
+		{ID: "same", Title: "Same", UpdatedAt: base},
+		{ID: "ours-newer", Title: "Ours", UpdatedAt: base.Add(time.Hour)},
+		{ID: "theirs-newer", Title: "Ours", UpdatedAt: base},
+	}
+	incoming := []*models.Todo{
+		{ID: "same", Title: "Same", UpdatedAt: base},
+		{ID: "ours-newer", Title: "Theirs", UpdatedAt: base},
+		{ID: "theirs-newer", Title: "Theirs", UpdatedAt: base.Add(time.Hour)},
+		{ID: "new", Title: "New", UpdatedAt: base},
+	}
+
+	imported, skipped := MergeTodos(existing, incoming)
+
+	var ids []string
+	for _, todo := range imported {
+		ids = append(ids, todo.ID)
+	}
+	if want := []string{"theirs-newer", "new"}; !slices.Equal(ids, want) {
+		t.Errorf("MergeTodos() imported = %v, want %v", ids, want)
+	}
+	if skipped != 2 {
+		t.Errorf("MergeTodos() skipped = %v, want %v", skipped, 2)
+	}
+}
+
+func TestBoltStorage_ImportFromReadOnly(t *testing.T) {
+	tempDir := t.TempDir()
+	otherPath := filepath.Join(tempDir, "other.db")
+
+	other, err := NewBoltStorage(otherPath)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	deadline := time.Now().Add(time.Hour)
+	other.SaveTodo(&models.Todo{ID: "1", Title: "From the laptop", Deadline: &deadline})
+	other.Close()
+
+	readOnly, err := OpenBoltStorageReadOnly(otherPath)
+	if err != nil {
+		t.Fatalf("OpenBoltStorageReadOnly failed: %v", err)
+	}
+	defer readOnly.Close()
+
+	incoming, err := readOnly.GetAllTodos()
+	if err != nil {
+		t.Fatalf("GetAllTodos failed: %v", err)
+	}
+	if err := readOnly.ImportTodos(incoming); err == nil {
+		t.Error("Expected writing to a read-only database to fail")
+	}
+
+	s, err := NewBoltStorage(filepath.Join(tempDir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.ImportTodos(incoming); err != nil {
+		t.Fatalf("ImportTodos failed: %v", err)
+	}
+
+	todo, err := s.GetTodo("1")
+	if err != nil {
+		t.Fatalf("GetTodo failed: %v", err)
+	}
+	if !todo.CreatedAt.Equal(incoming[0].CreatedAt) {
+		t.Errorf("CreatedAt = %v, want %v", todo.CreatedAt, incoming[0].CreatedAt)
+	}
+	if got := upcomingIDs(t, s); !slices.Equal(got, []string{"1"}) {
+		t.Errorf("GetTodosPage() = %v, want [1]", got)
+	}
+}
+
+func TestOpenBoltStorageReadOnly_NotADatabase(t *testing.T) {
+	if _, err := OpenBoltStorageReadOnly(filepath.Join(t.TempDir(), "missing.db")); err == nil {
+		t.Error("Expected an error for a missing database")
+	}
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"slices"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Overdue escalation tiers returned by OverdueTier
+const (
+	OverdueNone = iota
+	OverdueRecent
+	OverdueVery
+)
+
+// VeryOverdueAfter is how long a todo can be overdue before it escalates to
+// OverdueVery
+const VeryOverdueAfter = 7 * 24 * time.Hour
+
+// OverdueTier reports how neglected a todo is: OverdueNone when it isn't
+// overdue, OverdueRecent when its deadline passed less than VeryOverdueAfter
+// ago and OverdueVery after that. Completed and waiting todos never escalate.
+func OverdueTier(todo *models.Todo, now time.Time) int {
+	if todo == nil || todo.Completed || todo.Waiting || todo.Deadline == nil || !todo.Deadline.Before(now) {
+		return OverdueNone
+	}
+	if now.Sub(*todo.Deadline) >= VeryOverdueAfter {
+		return OverdueVery
+	}
+	return OverdueRecent
+}
+
+// OverdueTodos returns the incomplete todos whose deadline has passed
+func OverdueTodos(todos []*models.Todo) []*models.Todo {
+	var overdue []*models.Todo
+	for _, todo := range todos {
+		if todo.IsOverdue() {
+			overdue = append(overdue, todo)
+		}
+	}
+	return overdue
+}
+
+// CompleteTodos marks todos complete at the given time in one transaction and
+// counts them towards the streak on that day, in the same transaction. Todos
+// already completed, here or in the database, are skipped. Nothing is saved
+// when any of them fails, and the todos are left as they were.
+func (s *BoltStorage) CompleteTodos(todos []*models.Todo, at time.Time) error {
+	todos = slices.DeleteFunc(slices.Clone(todos), func(todo *models.Todo) bool {
+		return todo.Completed
+	})
+
+	var completedNow []*models.Todo
+	err := s.update(func(tx *bolt.Tx) error {
+		completedNow = nil
+		now := time.Now()
+
+		for _, todo := range todos {
+			stored, err := s.storedTodo(tx, todo.ID)
+			if err != nil {
+				return err
+			}
+			if stored != nil && stored.Completed {
+				// Completed by another process since todos were read
+				continue
+			}
+
+			completed := *todo
+			completed.MarkCompleteAt(at)
+			completed.UpdatedAt = now
+			SanitizeTodo(&completed)
+
+			if err := s.putTodo(tx, &completed); err != nil {
+				return err
+			}
+			completedNow = append(completedNow, todo)
+		}
+		if len(completedNow) > 0 {
+			// Ignore if failed, like UpdateTodo
+			_ = s.updateStreakOnCompletion(tx, at, len(completedNow))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, todo := range completedNow {
+		todo.MarkCompleteAt(at)
+	}
+	return nil
+}
+
+// RescheduleTodos moves the deadline of todos to deadline in one
+// transaction, skipping completed ones. Nothing is saved when any of them
+// fails, and the todos are left as they were.
+func (s *BoltStorage) RescheduleTodos(todos []*models.Todo, deadline time.Time) error {
+	todos = slices.DeleteFunc(slices.Clone(todos), func(todo *models.Todo) bool {
+		return todo.Completed
+	})
+
+	err := s.update(func(tx *bolt.Tx) error {
+		now := time.Now()
+
+		for _, todo := range todos {
+			rescheduled := *todo
+			rescheduled.Deadline = &deadline
+			rescheduled.UpdatedAt = now
+			SanitizeTodo(&rescheduled)
+
+			if err := s.putTodo(tx, &rescheduled); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, todo := range todos {
+		todo.Deadline = &deadline
+	}
+	return nil
+}
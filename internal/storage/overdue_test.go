@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestOverdueTier(t *testing.T) {
+	now := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		deadline := now.Add(d)
+		return &deadline
+	}
+
+	tests := []struct {
+		name string
+		todo *models.Todo
+		want int
+	}{
+		{"no deadline", &models.Todo{}, OverdueNone},
+		{"due later", &models.Todo{Deadline: at(time.Hour)}, OverdueNone},
+		{"due now", &models.Todo{Deadline: at(0)}, OverdueNone},
+		{"just overdue", &models.Todo{Deadline: at(-time.Second)}, OverdueRecent},
+		{"just under the limit", &models.Todo{Deadline: at(-VeryOverdueAfter + time.Second)}, OverdueRecent},
+		{"at the limit", &models.Todo{Deadline: at(-VeryOverdueAfter)}, OverdueVery},
+		{"long overdue", &models.Todo{Deadline: at(-30 * 24 * time.Hour)}, OverdueVery},
+		{"completed", &models.Todo{Deadline: at(-30 * 24 * time.Hour), Completed: true}, OverdueNone},
+		{"waiting", &models.Todo{Deadline: at(-30 * 24 * time.Hour), Waiting: true}, OverdueNone},
+		{"nil", nil, OverdueNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OverdueTier(tt.todo, now); got != tt.want {
+				t.Errorf("OverdueTier() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoltStorage_CompleteTodos(t *testing.T) {
+	s, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	past := time.Now().AddDate(0, 0, -1)
+	todos := []*models.Todo{
+		{ID: "1", Title: "A", Deadline: &past},
+		{ID: "2", Title: "B", Deadline: &past},
+		{ID: "3", Title: "C", Completed: true, CompletedAt: &past},
+	}
+	for _, todo := range todos {
+		s.SaveTodo(todo)
+	}
+
+	// Backdated, so the completions count towards that day
+	at := past.Truncate(time.Minute)
+	if err := s.CompleteTodos(todos, at); err != nil {
+		t.Fatalf("CompleteTodos() failed: %v", err)
+	}
+
+	for _, id := range []string{"1", "2"} {
+		todo, _ := s.GetTodo(id)
+		if !todo.Completed || !todo.CompletedAt.Equal(at) {
+			t.Errorf("todo %s = completed %v at %v, want completed at %v", id, todo.Completed, todo.CompletedAt, at)
+		}
+	}
+	if got := upcomingIDs(t, s); len(got) != 0 {
+		t.Errorf("Upcoming index = %v, want it empty", got)
+	}
+
+	streak, _ := s.GetStreak()
+	if got := streak.DailyCompletions[at.Format(dayLayout)]; got != 2 {
+		t.Errorf("DailyCompletions = %d, want 2 without the already completed todo", got)
+	}
+	if streak.TotalCompleted != 2 {
+		t.Errorf("TotalCompleted = %d, want 2", streak.TotalCompleted)
+	}
+}
+
+func TestBoltStorage_RescheduleTodos(t *testing.T) {
+	s, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	past := time.Now().AddDate(0, 0, -1).Truncate(time.Second)
+	todos := []*models.Todo{
+		{ID: "1", Title: "A", Deadline: &past},
+		{ID: "2", Title: "B", Completed: true, CompletedAt: &past, Deadline: &past},
+	}
+	for _, todo := range todos {
+		s.SaveTodo(todo)
+	}
+
+	deadline := time.Now().AddDate(0, 0, 1).Truncate(time.Second)
+	if err := s.RescheduleTodos(todos, deadline); err != nil {
+		t.Fatalf("RescheduleTodos() failed: %v", err)
+	}
+
+	if todo, _ := s.GetTodo("1"); !todo.Deadline.Equal(deadline) {
+		t.Errorf("todo 1 deadline = %v, want %v", todo.Deadline, deadline)
+	}
+	if todo, _ := s.GetTodo("2"); !todo.Deadline.Equal(past) {
+		t.Errorf("completed todo deadline = %v, want it unchanged", todo.Deadline)
+	}
+	if got := upcomingIDs(t, s); len(got) != 1 || got[0] != "1" {
+		t.Errorf("Upcoming index = %v, want [1]", got)
+	}
+}
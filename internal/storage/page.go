@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// SortOrder is the order GetTodosPage pages through todos in
+type SortOrder int
+
+const (
+	// SortList is the list order of SortTodos. It has no index, so every todo
+	// is loaded and sorted to cut out a page.
+	SortList SortOrder = iota
+	// SortID is ID order, which is creation order for generated IDs. Pages
+	// are read with a cursor over the todos bucket.
+	SortID
+	// SortDeadline is closest deadline first over the incomplete todos that
+	// have one. Pages are read with a cursor over the upcoming index.
+	SortDeadline
+)
+
+// Pager is implemented by storages that can read one page of todos without
+// loading the rest
+type Pager interface {
+	GetTodosPage(offset, limit int, order SortOrder) ([]*models.Todo, int, error)
+}
+
+// GetTodosPage returns up to limit todos starting at offset in the given
+// order, along with the total number of todos in that order. Only the todos
+// on the page are unmarshalled for the cursor backed orders.
+func (s *BoltStorage) GetTodosPage(offset, limit int, order SortOrder) ([]*models.Todo, int, error) {
+	if offset < 0 || limit < 0 {
+		return nil, 0, fmt.Errorf("invalid page: offset %d, limit %d", offset, limit)
+	}
+
+	if order == SortList {
+		todos, err := s.GetAllTodos()
+		if err != nil {
+			return nil, 0, err
+		}
+		start := min(offset, len(todos))
+		end := min(start+limit, len(todos))
+		return todos[start:end], len(todos), nil
+	}
+
+	var todos []*models.Todo
+	var total int
+	err := s.view(func(tx *bolt.Tx) error {
+		var b *bolt.Bucket
+		switch order {
+		case SortID:
+			b = tx.Bucket(todoBucket)
+		case SortDeadline:
+			b = tx.Bucket(upcomingBucket)
+		default:
+			return fmt.Errorf("unknown sort order %d", order)
+		}
+		total = int(b.Sequence())
+
+		c := b.Cursor()
+		k, v := c.First()
+		for i := 0; k != nil && i < offset; i++ {
+			k, v = c.Next()
+		}
+		for ; k != nil && len(todos) < limit; k, v = c.Next() {
+			todo, err := s.pageTodo(tx, order, v)
+			if err != nil {
+				return err
+			}
+			if todo != nil {
+				todos = append(todos, todo)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return todos, total, nil
+}
+
+// countKeys adjusts the key count of b by delta. The count is kept in the
+// bucket's sequence so that page totals don't have to walk the bucket.
+func countKeys(b *bolt.Bucket, delta int) error {
+	return b.SetSequence(uint64(max(int(b.Sequence())+delta, 0)))
+}
+
+// recountKeys counts the keys of b once, for buckets filled before their
+// count was kept
+func recountKeys(b *bolt.Bucket) error {
+	if b.Sequence() != 0 {
+		return nil
+	}
+	if k, _ := b.Cursor().First(); k == nil {
+		return nil
+	}
+	return b.SetSequence(uint64(b.Stats().KeyN))
+}
+
+// pageTodo decodes the todo for a cursor value, which is the todo itself in
+// the todos bucket and its ID in the upcoming index
+func (s *BoltStorage) pageTodo(tx *bolt.Tx, order SortOrder, v []byte) (*models.Todo, error) {
+	if order == SortDeadline {
+		return s.storedTodo(tx, string(v))
+	}
+	var todo models.Todo
+	if err := s.decodeTodo(v, &todo); err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
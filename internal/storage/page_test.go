@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBoltStorage_GetTodosPage(t *testing.T) {
+	s, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	// Five todos, of which 1 to 3 have deadlines in reverse ID order
+	now := time.Now()
+	for i := 1; i <= 5; i++ {
+		todo := &models.Todo{ID: fmt.Sprintf("%d", i), Title: fmt.Sprintf("Todo %d", i)}
+		if i <= 3 {
+			todo.Deadline = timePtr(now.Add(time.Duration(4-i) * time.Hour))
+		}
+		if err := s.SaveTodo(todo); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		offset    int
+		limit     int
+		order     SortOrder
+		want      []string
+		wantTotal int
+	}{
+		{"First ID page", 0, 2, SortID, []string{"1", "2"}, 5},
+		{"Middle ID page", 2, 2, SortID, []string{"3", "4"}, 5},
+		{"Last ID page is short", 4, 2, SortID, []string{"5"}, 5},
+		{"Past the end", 5, 2, SortID, nil, 5},
+		{"Zero limit", 0, 0, SortID, nil, 5},
+		{"Deadline page", 0, 2, SortDeadline, []string{"3", "2"}, 3},
+		{"Last deadline page", 2, 2, SortDeadline, []string{"1"}, 3},
+		{"List page", 2, 2, SortList, []string{"1", "5"}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todos, total, err := s.GetTodosPage(tt.offset, tt.limit, tt.order)
+			if err != nil {
+				t.Fatalf("GetTodosPage() failed: %v", err)
+			}
+			var ids []string
+			for _, todo := range todos {
+				ids = append(ids, todo.ID)
+			}
+			if !slices.Equal(ids, tt.want) || total != tt.wantTotal {
+				t.Errorf("GetTodosPage(%d, %d) = %v, %d, want %v, %d", tt.offset, tt.limit, ids, total, tt.want, tt.wantTotal)
+			}
+		})
+	}
+
+	if _, _, err := s.GetTodosPage(-1, 2, SortID); err == nil {
+		t.Error("GetTodosPage() with a negative offset should fail")
+	}
+}
+
+func TestBoltStorage_GetTodosPageTotal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	total := func(order SortOrder) int {
+		t.Helper()
+		_, total, err := s.GetTodosPage(0, 1, order)
+		if err != nil {
+			t.Fatalf("GetTodosPage() failed: %v", err)
+		}
+		return total
+	}
+
+	deadline := time.Now().Add(time.Hour)
+	for i := 1; i <= 3; i++ {
+		todo := &models.Todo{ID: fmt.Sprintf("%d", i), Title: fmt.Sprintf("Todo %d", i), Deadline: timePtr(deadline)}
+		if err := s.SaveTodo(todo); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+
+	todo, _ := s.GetTodo("1")
+	todo.Deadline = timePtr(deadline.Add(time.Hour))
+	if err := s.UpdateTodo(todo); err != nil {
+		t.Fatalf("UpdateTodo failed: %v", err)
+	}
+	todo, _ = s.GetTodo("2")
+	todo.Completed = true
+	if err := s.UpdateTodo(todo); err != nil {
+		t.Fatalf("UpdateTodo failed: %v", err)
+	}
+	if err := s.DeleteTodo("3"); err != nil {
+		t.Fatalf("DeleteTodo failed: %v", err)
+	}
+
+	if got := total(SortID); got != 2 {
+		t.Errorf("GetTodosPage() total by ID = %d, want 2", got)
+	}
+	if got := total(SortDeadline); got != 1 {
+		t.Errorf("GetTodosPage() total by deadline = %d, want 1", got)
+	}
+
+	// Databases written before the counts were kept are counted on open
+	s.update(func(tx *bolt.Tx) error {
+		tx.Bucket(todoBucket).SetSequence(0)
+		return tx.Bucket(upcomingBucket).SetSequence(0)
+	})
+	s.Close()
+	s, err = NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen storage: %v", err)
+	}
+	defer s.Close()
+
+	if got := total(SortID); got != 2 {
+		t.Errorf("GetTodosPage() total by ID after reopening = %d, want 2", got)
+	}
+	if got := total(SortDeadline); got != 1 {
+		t.Errorf("GetTodosPage() total by deadline after reopening = %d, want 1", got)
+	}
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"math/rand"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// PickRandom returns a random incomplete todo, or nil if there is none
+func PickRandom(todos []*models.Todo, rng *rand.Rand) *models.Todo {
+	var candidates []*models.Todo
+	for _, todo := range todos {
+		if !todo.Completed {
+			candidates = append(candidates, todo)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[rng.Intn(len(candidates))]
+}
+
+// SuggestNext picks a random todo to work on next, preferring todos without
+// a deadline since those are the ones that never become urgent
+func SuggestNext(todos []*models.Todo, rng *rand.Rand) *models.Todo {
+	if todo := PickRandom(GetTodosWithoutDeadline(todos), rng); todo != nil {
+		return todo
+	}
+	return PickRandom(todos, rng)
+}
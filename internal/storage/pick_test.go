@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestPickRandom(t *testing.T) {
+	todos := []*models.Todo{
+		{ID: "1", Completed: true},
+		{ID: "2"},
+		{ID: "3", Completed: true},
+		{ID: "4"},
+		{ID: "5"},
+	}
+
+	picked := make(map[string]int)
+	rng := rand.New(rand.NewSource(42))
+	for range 100 {
+		todo := PickRandom(todos, rng)
+		if todo == nil {
+			t.Fatal("PickRandom() = nil, want a todo")
+		}
+		if todo.Completed {
+			t.Fatalf("PickRandom() picked completed todo %s", todo.ID)
+		}
+		picked[todo.ID]++
+	}
+	if len(picked) != 3 {
+		t.Errorf("PickRandom() picked %v, want all 3 incomplete todos over 100 picks", picked)
+	}
+
+	// The same seed gives the same pick
+	first := PickRandom(todos, rand.New(rand.NewSource(7)))
+	second := PickRandom(todos, rand.New(rand.NewSource(7)))
+	if first != second {
+		t.Errorf("PickRandom() with the same seed = %s and %s", first.ID, second.ID)
+	}
+}
+
+func TestPickRandom_AllCompleted(t *testing.T) {
+	todos := []*models.Todo{
+		{ID: "1", Completed: true},
+		{ID: "2", Completed: true},
+	}
+
+	if got := PickRandom(todos, rand.New(rand.NewSource(1))); got != nil {
+		t.Errorf("PickRandom() = %s, want nil", got.ID)
+	}
+	if got := PickRandom(nil, rand.New(rand.NewSource(1))); got != nil {
+		t.Errorf("PickRandom(nil) = %s, want nil", got.ID)
+	}
+}
+
+func TestSuggestNext(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+	rng := rand.New(rand.NewSource(3))
+
+	withDeadline := &models.Todo{ID: "1", Deadline: &deadline}
+	noDeadline := &models.Todo{ID: "2"}
+
+	for range 10 {
+		if got := SuggestNext([]*models.Todo{withDeadline, noDeadline}, rng); got != noDeadline {
+			t.Fatalf("SuggestNext() = %s, want the todo without a deadline", got.ID)
+		}
+	}
+	if got := SuggestNext([]*models.Todo{withDeadline}, rng); got != withDeadline {
+		t.Errorf("SuggestNext() = %v, want the todo with a deadline", got)
+	}
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"slices"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// PlannedToday returns the incomplete todos planned to be worked on today,
+// whatever their deadline, in the order given
+func PlannedToday(todos []*models.Todo, now time.Time) []*models.Todo {
+	var planned []*models.Todo
+	for _, todo := range todos {
+		if !todo.Completed && todo.PlannedFor != nil && sameDay(*todo.PlannedFor, now) {
+			planned = append(planned, todo)
+		}
+	}
+	return planned
+}
+
+// PlanNext plans the n most urgent incomplete todos, in list order, for
+// today, returning them in that order so they can be saved. Fewer are
+// returned when there are not n incomplete todos.
+func PlanNext(todos []*models.Todo, n int, now time.Time) []*models.Todo {
+	now = now.Local()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	sorted := slices.Clone(todos)
+	SortTodos(sorted)
+
+	var planned []*models.Todo
+	for _, todo := range sorted {
+		if len(planned) == n {
+			break
+		}
+		if todo.Completed {
+			continue
+		}
+		day := today
+		todo.PlannedFor = &day
+		planned = append(planned, todo)
+	}
+	return planned
+}
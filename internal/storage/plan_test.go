@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestPlannedToday(t *testing.T) {
+	now := time.Date(2025, 11, 16, 15, 0, 0, 0, time.Local)
+	day := func(d int) *time.Time {
+		t := time.Date(2025, 11, d, 0, 0, 0, 0, time.Local)
+		return &t
+	}
+	nextWeek := now.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name string
+		todo *models.Todo
+		want bool
+	}{
+		{"Planned today", &models.Todo{PlannedFor: day(16)}, true},
+		{"Planned today, due next week", &models.Todo{PlannedFor: day(16), Deadline: &nextWeek}, true},
+		{"Due today, not planned", &models.Todo{Deadline: &now}, false},
+		{"Planned yesterday", &models.Todo{PlannedFor: day(15)}, false},
+		{"Planned tomorrow", &models.Todo{PlannedFor: day(17)}, false},
+		{"Planned today, completed", &models.Todo{PlannedFor: day(16), Completed: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(PlannedToday([]*models.Todo{tt.todo}, now)) == 1; got != tt.want {
+				t.Errorf("PlannedToday() includes the todo = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanNext(t *testing.T) {
+	now := time.Date(2025, 11, 16, 12, 0, 0, 0, time.Local)
+	today := time.Date(2025, 11, 16, 0, 0, 0, 0, time.Local)
+	soon, later, overdue := now.Add(2*time.Hour), now.AddDate(0, 0, 3), now.AddDate(0, 0, -1)
+	newTodos := func() []*models.Todo {
+		return []*models.Todo{
+			{ID: "1", Title: "Later", Deadline: &later},
+			{ID: "2", Title: "Done", Deadline: &overdue, Completed: true},
+			{ID: "3", Title: "Soon", Deadline: &soon},
+			{ID: "4", Title: "Someday", CreatedAt: now},
+			{ID: "5", Title: "Overdue", Deadline: &overdue},
+		}
+	}
+
+	tests := []struct {
+		name string
+		n    int
+		want []string
+	}{
+		{"Top two", 2, []string{"5", "3"}},
+		{"Top three", 3, []string{"5", "3", "1"}},
+		{"More than there are", 10, []string{"5", "3", "1", "4"}},
+		{"None", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todos := newTodos()
+			planned := PlanNext(todos, tt.n, now)
+
+			var ids []string
+			for _, todo := range planned {
+				ids = append(ids, todo.ID)
+				if todo.PlannedFor == nil || !todo.PlannedFor.Equal(today) {
+					t.Errorf("todo %s PlannedFor = %v, want %v", todo.ID, todo.PlannedFor, today)
+				}
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("PlanNext(%d) = %v, want %v", tt.n, ids, tt.want)
+			}
+			if todos[1].PlannedFor != nil {
+				t.Error("PlanNext() planned a completed todo")
+			}
+			if todos[0].ID != "1" {
+				t.Error("PlanNext() reordered the todos it was given")
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// DefaultReminderBefore is how long before its deadline a todo without its
+// own ReminderBefore is surfaced
+const DefaultReminderBefore = 24 * time.Hour
+
+// NeedsReminder reports whether now is within the todo's reminder lead time
+// of its deadline, before the deadline passes. Completed todos and todos
+// waiting on someone else are never reminded.
+func NeedsReminder(todo *models.Todo, now time.Time) bool {
+	if todo.Completed || todo.Waiting || todo.Deadline == nil || !todo.Deadline.After(now) {
+		return false
+	}
+	lead := todo.ReminderBefore
+	if lead <= 0 {
+		lead = DefaultReminderBefore
+	}
+	return todo.Deadline.Sub(now) <= lead
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestNeedsReminder(t *testing.T) {
+	now := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		deadline := now.Add(d)
+		return &deadline
+	}
+
+	tests := []struct {
+		name string
+		todo *models.Todo
+		want bool
+	}{
+		{"lead entered", &models.Todo{Deadline: at(30 * time.Minute), ReminderBefore: time.Hour}, true},
+		{"lead just entered", &models.Todo{Deadline: at(time.Hour), ReminderBefore: time.Hour}, true},
+		{"lead not reached", &models.Todo{Deadline: at(2 * time.Hour), ReminderBefore: time.Hour}, false},
+		{"long lead entered", &models.Todo{Deadline: at(72 * time.Hour), ReminderBefore: 7 * 24 * time.Hour}, true},
+		{"default lead entered", &models.Todo{Deadline: at(23 * time.Hour)}, true},
+		{"default lead not reached", &models.Todo{Deadline: at(25 * time.Hour)}, false},
+		{"deadline passed", &models.Todo{Deadline: at(-time.Minute), ReminderBefore: time.Hour}, false},
+		{"no deadline", &models.Todo{ReminderBefore: time.Hour}, false},
+		{"completed", &models.Todo{Deadline: at(time.Minute), Completed: true}, false},
+		{"waiting", &models.Todo{Deadline: at(time.Minute), Waiting: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsReminder(tt.todo, now); got != tt.want {
+				t.Errorf("NeedsReminder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// ReplaceInTodos replaces oldText with newText in every todo's title and
+// description. It returns the changed todos, and separately the todos left
+// untouched because the replacement would exceed a character limit.
+func ReplaceInTodos(todos []*models.Todo, oldText, newText string) (changed, overflowed []*models.Todo) {
+	if oldText == "" {
+		return nil, nil
+	}
+
+	for _, todo := range todos {
+		newTitle := strings.ReplaceAll(todo.Title, oldText, newText)
+		newDescription := strings.ReplaceAll(todo.Description, oldText, newText)
+
+		if newTitle == todo.Title && newDescription == todo.Description {
+			continue
+		}
+
+		if utf8.RuneCountInString(newTitle) > models.MaxTitleLength ||
+			utf8.RuneCountInString(newDescription) > models.MaxDescriptionLength {
+			overflowed = append(overflowed, todo)
+			continue
+		}
+
+		todo.Title = newTitle
+		todo.Description = newDescription
+		changed = append(changed, todo)
+	}
+
+	return changed, overflowed
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestReplaceInTodos(t *testing.T) {
+	todos := []*models.Todo{
+		{ID: "1", Title: "Email Acme", Description: "Ask Acme about the invoice"},
+		{ID: "2", Title: "Groceries", Description: "Milk"},
+		{ID: "3", Title: "Acme" + strings.Repeat("x", models.MaxTitleLength-4), Description: "Long"},
+	}
+
+	changed, overflowed := ReplaceInTodos(todos, "Acme", "Globex Corporation")
+
+	if len(changed) != 1 || changed[0].ID != "1" {
+		t.Fatalf("Expected only todo 1 to change, got %d changed", len(changed))
+	}
+	if changed[0].Title != "Email Globex Corporation" || changed[0].Description != "Ask Globex Corporation about the invoice" {
+		t.Errorf("Unexpected replacement: %q / %q", changed[0].Title, changed[0].Description)
+	}
+
+	if len(overflowed) != 1 || overflowed[0].ID != "3" {
+		t.Fatalf("Expected todo 3 to overflow, got %d overflowed", len(overflowed))
+	}
+	if !strings.HasPrefix(todos[2].Title, "Acme") {
+		t.Error("Overflowing todo should be left untouched")
+	}
+}
+
+func TestReplaceInTodos_CountsCharacters(t *testing.T) {
+	// Fits in characters, though the replacement takes more bytes than the
+	// limit
+	title := "x" + strings.Repeat("y", models.MaxTitleLength-1)
+	todos := []*models.Todo{{ID: "1", Title: title, Description: "Long"}}
+
+	changed, overflowed := ReplaceInTodos(todos, "y", "é")
+
+	if len(changed) != 1 || len(overflowed) != 0 {
+		t.Fatalf("ReplaceInTodos() changed %d and overflowed %d, want 1 and 0", len(changed), len(overflowed))
+	}
+	if want := "x" + strings.Repeat("é", models.MaxTitleLength-1); changed[0].Title != want {
+		t.Errorf("Title = %q, want %q", changed[0].Title, want)
+	}
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// ResolveTodoRef finds the one todo that ref refers to: the todo with that
+// exact ID, otherwise the todo whose ID starts with ref, otherwise the todo
+// whose title contains ref, ignoring case. When several todos match but only
+// one of them is incomplete, that one is picked. It fails with the
+// candidates listed when ref is ambiguous, and when nothing matches.
+func ResolveTodoRef(todos []*models.Todo, ref string) (*models.Todo, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("no todo given")
+	}
+
+	var byPrefix, byTitle []*models.Todo
+	lower := strings.ToLower(ref)
+	for _, todo := range todos {
+		if todo.ID == ref {
+			return todo, nil
+		}
+		if strings.HasPrefix(todo.ID, ref) {
+			byPrefix = append(byPrefix, todo)
+		}
+		if strings.Contains(strings.ToLower(todo.Title), lower) {
+			byTitle = append(byTitle, todo)
+		}
+	}
+
+	matches := byPrefix
+	if len(matches) == 0 {
+		matches = byTitle
+	}
+	if len(matches) > 1 {
+		var open []*models.Todo
+		for _, todo := range matches {
+			if !todo.Completed {
+				open = append(open, todo)
+			}
+		}
+		if len(open) > 0 {
+			matches = open
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no todo matches %q", ref)
+	case 1:
+		return matches[0], nil
+	}
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "%q matches %d todos:", ref, len(matches))
+	for _, todo := range matches {
+		fmt.Fprintf(&s, "\n  %s  %s", todo.ID, todo.Title)
+	}
+	return nil, fmt.Errorf("%s", s.String())
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestResolveTodoRef(t *testing.T) {
+	todos := []*models.Todo{
+		{ID: "1763294400000000000", Title: "Buy groceries"},
+		{ID: "1763294411111111111", Title: "Call the bank"},
+		{ID: "1763380800000000000", Title: "Pay rent", Completed: true},
+		{ID: "1763380822222222222", Title: "Pay rent"},
+		{ID: "17", Title: "Groceries for the party"},
+	}
+
+	tests := []struct {
+		name   string
+		ref    string
+		wantID string
+	}{
+		{"exact ID", "17", "17"},
+		{"unique ID prefix", "176329440", "1763294400000000000"},
+		{"title substring", "bank", "1763294411111111111"},
+		{"title ignores case", "PARTY", "17"},
+		{"only incomplete match", "rent", "1763380822222222222"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveTodoRef(todos, tt.ref)
+			if err != nil {
+				t.Fatalf("ResolveTodoRef(%q) error = %v", tt.ref, err)
+			}
+			if got.ID != tt.wantID {
+				t.Errorf("ResolveTodoRef(%q) = %v, want %v", tt.ref, got.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestResolveTodoRef_Errors(t *testing.T) {
+	todos := []*models.Todo{
+		{ID: "1763294400000000000", Title: "Buy groceries"},
+		{ID: "1763294411111111111", Title: "Groceries for the party"},
+	}
+
+	tests := []struct {
+		name     string
+		ref      string
+		wantText []string
+	}{
+		{"ambiguous ID prefix", "176329", []string{"matches 2 todos", "1763294400000000000  Buy groceries", "1763294411111111111  Groceries for the party"}},
+		{"ambiguous title", "groceries", []string{"matches 2 todos", "Buy groceries", "Groceries for the party"}},
+		{"no match", "dentist", []string{`no todo matches "dentist"`}},
+		{"empty", " ", []string{"no todo given"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveTodoRef(todos, tt.ref)
+			if err == nil {
+				t.Fatalf("ResolveTodoRef(%q) = %v, want error", tt.ref, got.ID)
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("ResolveTodoRef(%q) error = %q, want it to contain %q", tt.ref, err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// TodaysCompletions returns the todos completed on the same local calendar
+// day as now, most recently completed first
+func TodaysCompletions(todos []*models.Todo, now time.Time) []*models.Todo {
+	var completed []*models.Todo
+	for _, todo := range todos {
+		if todo.Completed && todo.CompletedAt != nil && sameDay(*todo.CompletedAt, now) {
+			completed = append(completed, todo)
+		}
+	}
+
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].CompletedAt.After(*completed[j].CompletedAt)
+	})
+
+	return completed
+}
+
+// sameDay checks if two times fall on the same local calendar day
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Local().Date()
+	by, bm, bd := b.Local().Date()
+	return ay == by && am == bm && ad == bd
+}
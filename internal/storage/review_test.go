@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestTodaysCompletions(t *testing.T) {
+	now := time.Date(2025, 11, 16, 18, 0, 0, 0, time.Local)
+	earlyToday := time.Date(2025, 11, 16, 0, 5, 0, 0, time.Local)
+	lateYesterday := time.Date(2025, 11, 15, 23, 55, 0, 0, time.Local)
+
+	todos := []*models.Todo{
+		{ID: "1", Title: "Morning", Completed: true, CompletedAt: timePtr(now.Add(-8 * time.Hour))},
+		{ID: "2", Title: "Yesterday", Completed: true, CompletedAt: &lateYesterday},
+		{ID: "3", Title: "Just after midnight", Completed: true, CompletedAt: &earlyToday},
+		{ID: "4", Title: "Open", Completed: false},
+		{ID: "5", Title: "Afternoon", Completed: true, CompletedAt: timePtr(now.Add(-2 * time.Hour))},
+		{ID: "6", Title: "Missing timestamp", Completed: true},
+	}
+
+	completed := TodaysCompletions(todos, now)
+
+	expected := []string{"5", "1", "3"}
+	if len(completed) != len(expected) {
+		t.Fatalf("TodaysCompletions returned %d todos, want %d", len(completed), len(expected))
+	}
+	for i, id := range expected {
+		if completed[i].ID != id {
+			t.Errorf("TodaysCompletions[%d] = %s, want %s", i, completed[i].Title, id)
+		}
+	}
+}
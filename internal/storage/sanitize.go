@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+var ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// SanitizeTodo strips control characters that would corrupt the TUI layout.
+// Titles, tags and subtasks are collapsed onto a single line, while the
+// description keeps its newlines. Attachments keep their inner spacing, but
+// blank and duplicate ones are dropped.
+func SanitizeTodo(todo *models.Todo) {
+	todo.Title = sanitizeLine(todo.Title)
+	todo.Description = sanitizeText(todo.Description)
+
+	for i, tag := range todo.Tags {
+		todo.Tags[i] = sanitizeLine(tag)
+	}
+	for i := range todo.Subtasks {
+		todo.Subtasks[i].Title = sanitizeLine(todo.Subtasks[i].Title)
+	}
+	todo.Attachments = sanitizeAttachments(todo.Attachments)
+	todo.WaitingOn = sanitizeLine(todo.WaitingOn)
+	todo.Project = sanitizeLine(todo.Project)
+	todo.CompletionNote = sanitizeText(todo.CompletionNote)
+}
+
+// sanitizeAttachments removes control characters from attachment paths and
+// drops blank and duplicate entries, keeping the first occurrence
+func sanitizeAttachments(attachments []string) []string {
+	var result []string
+	for _, attachment := range attachments {
+		attachment = ansiEscapeRegex.ReplaceAllString(attachment, "")
+		attachment = strings.TrimSpace(strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) {
+				return -1
+			}
+			return r
+		}, attachment))
+		if attachment != "" && !slices.Contains(result, attachment) {
+			result = append(result, attachment)
+		}
+	}
+	return result
+}
+
+// sanitizeLine removes control characters and collapses all whitespace,
+// including newlines and tabs, into single spaces
+func sanitizeLine(s string) string {
+	s = ansiEscapeRegex.ReplaceAllString(s, "")
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// sanitizeText removes control characters other than newlines, turning tabs
+// into spaces and normalizing line endings
+func sanitizeText(s string) string {
+	s = ansiEscapeRegex.ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n':
+			return r
+		case r == '\t' || r == '\r':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(s)
+}
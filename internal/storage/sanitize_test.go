@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestSanitizeTodo(t *testing.T) {
+	todo := &models.Todo{
+		Title:       "  Fix\n\tthe \x1bbuild\x00  now ",
+		Description: "Steps:\r\n1. pull\n2.\tbuild\x07\n\x1b[31mred\x1b[0m",
+		Tags:        []string{"work\n"},
+		Subtasks:    []models.Subtask{{Title: "a\tb"}},
+	}
+
+	SanitizeTodo(todo)
+
+	if todo.Title != "Fix the build now" {
+		t.Errorf("Title = %q, want %q", todo.Title, "Fix the build now")
+	}
+	if todo.Description != "Steps:\n1. pull\n2. build\nred" {
+		t.Errorf("Description = %q, want newlines kept and control characters removed", todo.Description)
+	}
+	if todo.Tags[0] != "work" {
+		t.Errorf("Tag = %q, want %q", todo.Tags[0], "work")
+	}
+	if todo.Subtasks[0].Title != "a b" {
+		t.Errorf("Subtask = %q, want %q", todo.Subtasks[0].Title, "a b")
+	}
+}
+
+func TestBoltStorage_SaveDeduplicatesAttachments(t *testing.T) {
+	s, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	todo := &models.Todo{
+		ID:    "1",
+		Title: "Review",
+		Attachments: []string{
+			"/docs/contract.pdf",
+			"  /docs/contract.pdf ",
+			"",
+			"   ",
+			"https://example.com/a  b",
+			"/docs/contract.pdf",
+			"/docs/notes\x1b[31m.txt",
+		},
+	}
+	if err := s.SaveTodo(todo); err != nil {
+		t.Fatalf("SaveTodo failed: %v", err)
+	}
+
+	saved, err := s.GetTodo("1")
+	if err != nil {
+		t.Fatalf("GetTodo failed: %v", err)
+	}
+
+	want := []string{"/docs/contract.pdf", "https://example.com/a  b", "/docs/notes.txt"}
+	if !slices.Equal(saved.Attachments, want) {
+		t.Errorf("Attachments = %q, want %q", saved.Attachments, want)
+	}
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/utils"
+)
+
+// DistributeDeadlines assigns deadlines to the given todos, at most perDay on
+// each business day starting with start's day. Todos are scheduled in the
+// given order, each due at the end of its day. The result maps todo IDs to
+// their new deadline.
+func DistributeDeadlines(noDeadline []*models.Todo, perDay int, start time.Time) map[string]*time.Time {
+	return DistributeDeadlinesAround(noDeadline, nil, perDay, start)
+}
+
+// DistributeDeadlinesAround works like DistributeDeadlines but counts the
+// incomplete scheduled todos already due on a day towards its limit, so busy
+// days get fewer new todos
+func DistributeDeadlinesAround(noDeadline, scheduled []*models.Todo, perDay int, start time.Time) map[string]*time.Time {
+	deadlines := make(map[string]*time.Time)
+	if perDay <= 0 {
+		return deadlines
+	}
+
+	load := make(map[string]int)
+	for _, todo := range scheduled {
+		if !todo.Completed && todo.Deadline != nil {
+			load[todo.Deadline.Local().Format(dayLayout)]++
+		}
+	}
+
+	start = start.Local()
+	day := time.Date(start.Year(), start.Month(), start.Day(), 23, 59, 0, 0, start.Location())
+	for _, todo := range noDeadline {
+		for utils.IsWeekend(day) || load[day.Format(dayLayout)] >= perDay {
+			day = day.AddDate(0, 0, 1)
+		}
+		deadline := day
+		deadlines[todo.ID] = &deadline
+		load[day.Format(dayLayout)]++
+	}
+	return deadlines
+}
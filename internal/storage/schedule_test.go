@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestDistributeDeadlines(t *testing.T) {
+	// Monday
+	start := time.Date(2025, 11, 17, 8, 0, 0, 0, time.Local)
+	var todos []*models.Todo
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		todos = append(todos, &models.Todo{ID: id})
+	}
+
+	got := DistributeDeadlines(todos, 2, start)
+
+	want := map[string]int{"a": 17, "b": 17, "c": 18, "d": 18, "e": 19}
+	if len(got) != len(want) {
+		t.Fatalf("DistributeDeadlines() scheduled %d todos, want %d", len(got), len(want))
+	}
+	for id, day := range want {
+		deadline := got[id]
+		if deadline == nil || deadline.Day() != day || deadline.Hour() != 23 || deadline.Minute() != 59 {
+			t.Errorf("Deadline for %s = %v, want Nov %d 23:59", id, deadline, day)
+		}
+	}
+}
+
+func TestDistributeDeadlinesAround_SkipsWeekendsAndBusyDays(t *testing.T) {
+	// Friday
+	start := time.Date(2025, 11, 21, 8, 0, 0, 0, time.Local)
+	friday := time.Date(2025, 11, 21, 12, 0, 0, 0, time.Local)
+	monday := time.Date(2025, 11, 24, 9, 0, 0, 0, time.Local)
+	scheduled := []*models.Todo{
+		{ID: "x", Deadline: &friday},
+		{ID: "y", Deadline: &friday},
+		{ID: "z", Deadline: &monday},
+		{ID: "done", Deadline: &monday, Completed: true},
+	}
+	todos := []*models.Todo{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+	got := DistributeDeadlinesAround(todos, scheduled, 2, start)
+
+	want := map[string]int{"a": 24, "b": 25, "c": 25}
+	for id, day := range want {
+		if deadline := got[id]; deadline == nil || deadline.Day() != day {
+			t.Errorf("Deadline for %s = %v, want Nov %d", id, deadline, day)
+		}
+	}
+
+	if got := DistributeDeadlines(todos, 0, start); len(got) != 0 {
+		t.Errorf("DistributeDeadlines() with perDay 0 = %v, want none", got)
+	}
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"strings"
+
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Scope selects the buckets SearchAll looks in
+type Scope int
+
+const (
+	// ScopeActive searches the todos in the list
+	ScopeActive Scope = 1 << iota
+	// ScopeArchive searches the archived todos
+	ScopeArchive
+
+	// ScopeAll searches both the list and the archive
+	ScopeAll = ScopeActive | ScopeArchive
+)
+
+// String names the source of a search result
+func (s Scope) String() string {
+	switch s {
+	case ScopeActive:
+		return "active"
+	case ScopeArchive:
+		return "archived"
+	default:
+		return "all"
+	}
+}
+
+// SearchResult is a todo matching a search and the bucket it was found in
+type SearchResult struct {
+	Todo   *models.Todo
+	Source Scope
+}
+
+// SearchAll returns the todos in scope whose title, description, tags,
+// project or completion note contain query, ignoring case. Active todos come
+// first, in list order, followed by archived ones.
+func (s *BoltStorage) SearchAll(query string, scope Scope) ([]SearchResult, error) {
+	var results []SearchResult
+
+	err := s.view(func(tx *bolt.Tx) error {
+		for _, source := range []Scope{ScopeActive, ScopeArchive} {
+			if scope&source == 0 {
+				continue
+			}
+			name := todoBucket
+			if source == ScopeArchive {
+				name = archiveBucket
+			}
+
+			var matches []*models.Todo
+			err := tx.Bucket(name).ForEach(func(k, v []byte) error {
+				var todo models.Todo
+				if err := s.decodeTodo(v, &todo); err != nil {
+					return err
+				}
+				if MatchesQuery(&todo, query) {
+					matches = append(matches, &todo)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			SortTodos(matches)
+			for _, todo := range matches {
+				results = append(results, SearchResult{Todo: todo, Source: source})
+			}
+		}
+		return nil
+	})
+	return results, err
+}
+
+// MatchesQuery reports whether the todo's title, description, tags, project
+// or completion note contain query, ignoring case. An empty query matches
+// nothing.
+func MatchesQuery(todo *models.Todo, query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return false
+	}
+
+	fields := append([]string{todo.Title, todo.Description, todo.Project, todo.CompletionNote}, todo.Tags...)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestSearchAll(t *testing.T) {
+	s, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	for _, todo := range []*models.Todo{
+		{ID: "1", Title: "Renew passport", Description: "Photo booth first"},
+		{ID: "2", Title: "Call plumber", Completed: true, CompletionNote: "Fixed the boiler"},
+		{ID: "3", Title: "File tax return", Tags: []string{"finance"}},
+	} {
+		if err := s.SaveTodo(todo); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+	if err := s.ArchiveTodo("2"); err != nil {
+		t.Fatalf("ArchiveTodo failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		scope     Scope
+		wantID    string
+		wantFound bool
+	}{
+		{"archived only in active scope", "boiler", ScopeActive, "", false},
+		{"archived in archive scope", "BOILER", ScopeArchive, "2", true},
+		{"archived in all scopes", "plumber", ScopeAll, "2", true},
+		{"active in archive scope", "passport", ScopeArchive, "", false},
+		{"description", "photo booth", ScopeActive, "1", true},
+		{"tag", "finance", ScopeAll, "3", true},
+		{"no match", "dentist", ScopeAll, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := s.SearchAll(tt.query, tt.scope)
+			if err != nil {
+				t.Fatalf("SearchAll() error = %v", err)
+			}
+			if !tt.wantFound {
+				if len(results) != 0 {
+					t.Errorf("SearchAll(%q) = %d results, want none", tt.query, len(results))
+				}
+				return
+			}
+			if len(results) != 1 || results[0].Todo.ID != tt.wantID {
+				t.Fatalf("SearchAll(%q) = %v, want todo %s", tt.query, results, tt.wantID)
+			}
+			wantSource := ScopeActive
+			if tt.wantID == "2" {
+				wantSource = ScopeArchive
+			}
+			if results[0].Source != wantSource {
+				t.Errorf("SearchAll(%q) source = %v, want %v", tt.query, results[0].Source, wantSource)
+			}
+		})
+	}
+}
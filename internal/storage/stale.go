@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// DefaultStaleAfter is how long a backlog todo can go untouched before it is
+// stale
+const DefaultStaleAfter = 30 * 24 * time.Hour
+
+// StaleBacklog returns the incomplete todos without a deadline that haven't
+// been updated for at least staleAfter, or when they were created if they
+// were never updated. A staleAfter of 0 or less finds none.
+func StaleBacklog(todos []*models.Todo, staleAfter time.Duration, now time.Time) []*models.Todo {
+	if staleAfter <= 0 {
+		return nil
+	}
+
+	var stale []*models.Todo
+	for _, todo := range todos {
+		if todo.Completed || todo.Deadline != nil {
+			continue
+		}
+		touched := todo.UpdatedAt
+		if touched.IsZero() {
+			touched = todo.CreatedAt
+		}
+		if now.Sub(touched) >= staleAfter {
+			stale = append(stale, todo)
+		}
+	}
+	return stale
+}
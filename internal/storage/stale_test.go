@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestStaleBacklog(t *testing.T) {
+	now := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+	deadline := now.Add(24 * time.Hour)
+
+	tests := []struct {
+		name string
+		todo *models.Todo
+		want bool
+	}{
+		{"just under the limit", &models.Todo{UpdatedAt: ago(DefaultStaleAfter - time.Second)}, false},
+		{"at the limit", &models.Todo{UpdatedAt: ago(DefaultStaleAfter)}, true},
+		{"long untouched", &models.Todo{UpdatedAt: ago(90 * 24 * time.Hour)}, true},
+		{"recently updated", &models.Todo{CreatedAt: ago(90 * 24 * time.Hour), UpdatedAt: ago(time.Hour)}, false},
+		{"never updated", &models.Todo{CreatedAt: ago(DefaultStaleAfter)}, true},
+		{"with a deadline", &models.Todo{UpdatedAt: ago(90 * 24 * time.Hour), Deadline: &deadline}, false},
+		{"completed", &models.Todo{UpdatedAt: ago(90 * 24 * time.Hour), Completed: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StaleBacklog([]*models.Todo{tt.todo}, DefaultStaleAfter, now)
+			if (len(got) == 1) != tt.want {
+				t.Errorf("StaleBacklog() = %v, want stale %v", got, tt.want)
+			}
+		})
+	}
+
+	old := []*models.Todo{{UpdatedAt: ago(90 * 24 * time.Hour)}}
+	if got := StaleBacklog(old, 0, now); got != nil {
+		t.Errorf("StaleBacklog() with 0 = %v, want nil", got)
+	}
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/utils"
+)
+
+// UntaggedLabel is the tag completed todos without tags are counted under
+const UntaggedLabel = "untagged"
+
+// TagCount pairs a tag with a number of todos
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// CompletionsByTag counts completed todos per tag. A todo with several tags
+// counts towards each of them.
+func CompletionsByTag(todos []*models.Todo) map[string]int {
+	counts := make(map[string]int)
+	for _, todo := range todos {
+		if !todo.Completed {
+			continue
+		}
+		if len(todo.Tags) == 0 {
+			counts[UntaggedLabel]++
+			continue
+		}
+		for _, tag := range todo.Tags {
+			counts[tag]++
+		}
+	}
+	return counts
+}
+
+// RankTagCounts orders tag counts from highest to lowest, breaking ties by tag
+func RankTagCounts(counts map[string]int) []TagCount {
+	ranked := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		ranked = append(ranked, TagCount{Tag: tag, Count: count})
+	}
+	slices.SortFunc(ranked, func(a, b TagCount) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+	return ranked
+}
+
+// TodayBurndown counts the todos due on the same local calendar day as now by
+// whether they are done. Todos due on other days are ignored, even when they
+// were completed today.
+func TodayBurndown(todos []*models.Todo, now time.Time) (done, remaining int) {
+	for _, todo := range todos {
+		if todo == nil || todo.Deadline == nil || !sameDay(*todo.Deadline, now) {
+			continue
+		}
+		if todo.Completed {
+			done++
+		} else {
+			remaining++
+		}
+	}
+	return done, remaining
+}
+
+// Summary counts todos for dashboards and prompts
+type Summary struct {
+	Total      int `json:"total"`
+	Incomplete int `json:"incomplete"`
+	Overdue    int `json:"overdue"`
+	DueToday   int `json:"due_today"`
+	Completed  int `json:"completed"`
+}
+
+// SummarizeTodos counts todos as of now. Overdue and due today only count
+// incomplete todos, so one due earlier today counts as both.
+func SummarizeTodos(todos []*models.Todo, now time.Time) Summary {
+	var summary Summary
+	for _, todo := range todos {
+		if todo == nil {
+			continue
+		}
+		summary.Total++
+		if todo.Completed {
+			summary.Completed++
+			continue
+		}
+		summary.Incomplete++
+		if todo.Deadline == nil {
+			continue
+		}
+		if todo.Deadline.Before(now) {
+			summary.Overdue++
+		}
+		if sameDay(*todo.Deadline, now) {
+			summary.DueToday++
+		}
+	}
+	return summary
+}
+
+// DayCount pairs a day with the number of todos completed on it
+type DayCount struct {
+	Day   time.Time
+	Count int
+}
+
+// WeeklyProgress returns how many todos were completed so far in the week of
+// now, which starts on weekStart, and the weekly goal
+func WeeklyProgress(streak *Streak, now time.Time, weekStart time.Weekday) (done, goal int) {
+	now = now.In(time.Local)
+	elapsed := (int(now.Weekday()) - int(weekStart) + 7) % 7
+	for i := 0; i <= elapsed; i++ {
+		done += streak.DailyCompletions[now.AddDate(0, 0, -i).Format(dayLayout)]
+	}
+	return done, streak.WeeklyGoal
+}
+
+// WeeklyCommittedPoints sums the points of the incomplete todos due in the
+// week of now, Monday to Sunday like the W filter
+func WeeklyCommittedPoints(todos []*models.Todo, now time.Time) int {
+	points := 0
+	for _, todo := range todos {
+		if todo == nil || todo.Completed || todo.Deadline == nil {
+			continue
+		}
+		if utils.InPeriod(*todo.Deadline, utils.PeriodWeek, now) {
+			points += todo.Points
+		}
+	}
+	return points
+}
+
+// Overcommitted reports whether committed points exceed a weekly capacity,
+// never when no capacity is set
+func Overcommitted(committed, capacity int) bool {
+	return capacity > 0 && committed > capacity
+}
+
+// WeekTrend returns the completions for the seven days ending with now,
+// oldest first
+func WeekTrend(streak *Streak, now time.Time) []DayCount {
+	trend := make([]DayCount, 0, 7)
+	for i := 6; i >= 0; i-- {
+		day := now.AddDate(0, 0, -i)
+		trend = append(trend, DayCount{Day: day, Count: streak.DailyCompletions[day.Format(dayLayout)]})
+	}
+	return trend
+}
@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestCompletionsByTag(t *testing.T) {
+	todos := []*models.Todo{
+		{ID: "1", Tags: []string{"work", "urgent"}, Completed: true},
+		{ID: "2", Tags: []string{"work"}, Completed: true},
+		{ID: "3", Tags: []string{"home"}, Completed: false},
+		{ID: "4", Completed: true},
+		{ID: "5", Tags: []string{"home"}, Completed: true},
+		{ID: "6", Completed: false},
+	}
+
+	got := CompletionsByTag(todos)
+
+	want := map[string]int{"work": 2, "urgent": 1, "home": 1, UntaggedLabel: 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompletionsByTag() = %v, want %v", got, want)
+	}
+}
+
+func TestRankTagCounts(t *testing.T) {
+	got := RankTagCounts(map[string]int{"home": 1, "work": 3, "errands": 1, UntaggedLabel: 2})
+
+	want := []TagCount{
+		{Tag: "work", Count: 3},
+		{Tag: UntaggedLabel, Count: 2},
+		{Tag: "errands", Count: 1},
+		{Tag: "home", Count: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RankTagCounts() = %v, want %v", got, want)
+	}
+}
+
+func TestTodayBurndown(t *testing.T) {
+	now := time.Date(2025, 11, 14, 12, 0, 0, 0, time.Local)
+	earlier := now.Add(-2 * time.Hour)
+	later := now.Add(6 * time.Hour)
+	yesterday := now.AddDate(0, 0, -1)
+	tomorrow := now.AddDate(0, 0, 1)
+
+	todos := []*models.Todo{
+		{ID: "1", Deadline: &earlier, Completed: true, CompletedAt: &now},
+		{ID: "2", Deadline: &later},
+		{ID: "3", Deadline: &later},
+		{ID: "4", Deadline: &yesterday, Completed: true, CompletedAt: &now},
+		{ID: "5", Deadline: &tomorrow},
+		{ID: "6", Completed: true, CompletedAt: &now},
+	}
+
+	tests := []struct {
+		name          string
+		todos         []*models.Todo
+		wantDone      int
+		wantRemaining int
+	}{
+		{"Mixed deadlines", todos, 1, 2},
+		{"Completed today but due yesterday", todos[3:4], 0, 0},
+		{"No todos", nil, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, remaining := TodayBurndown(tt.todos, now)
+			if done != tt.wantDone || remaining != tt.wantRemaining {
+				t.Errorf("TodayBurndown() = (%v, %v), want (%v, %v)", done, remaining, tt.wantDone, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestWeekTrend(t *testing.T) {
+	now := time.Date(2025, 11, 14, 12, 0, 0, 0, time.Local)
+	streak := &Streak{DailyCompletions: map[string]int{
+		"2025-11-07": 9,
+		"2025-11-08": 1,
+		"2025-11-12": 3,
+		"2025-11-14": 2,
+	}}
+
+	got := WeekTrend(streak, now)
+
+	want := []int{1, 0, 0, 0, 3, 0, 2}
+	if len(got) != len(want) {
+		t.Fatalf("WeekTrend() returned %d days, want %d", len(got), len(want))
+	}
+	for i, day := range got {
+		if day.Count != want[i] {
+			t.Errorf("WeekTrend()[%d].Count = %v, want %v", i, day.Count, want[i])
+		}
+	}
+	if !got[len(got)-1].Day.Equal(now) {
+		t.Errorf("WeekTrend() should end with today, got %v", got[len(got)-1].Day)
+	}
+}
+
+func TestWeeklyProgress(t *testing.T) {
+	streak := &Streak{
+		WeeklyGoal: 15,
+		DailyCompletions: map[string]int{
+			"2025-11-08": 9, // Saturday the week before
+			"2025-11-09": 1, // Sunday
+			"2025-11-10": 2, // Monday
+			"2025-11-12": 3, // Wednesday
+			"2025-11-13": 4, // Thursday, after now
+		},
+	}
+	now := time.Date(2025, 11, 12, 18, 0, 0, 0, time.Local) // Wednesday
+
+	tests := []struct {
+		name      string
+		weekStart time.Weekday
+		wantDone  int
+	}{
+		{"Week starting Monday", time.Monday, 5},
+		{"Week starting Sunday", time.Sunday, 6},
+		{"Week starting Saturday", time.Saturday, 15},
+		{"Week starting today", time.Wednesday, 3},
+		{"Week starting tomorrow", time.Thursday, 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, goal := WeeklyProgress(streak, now, tt.weekStart)
+			if done != tt.wantDone || goal != 15 {
+				t.Errorf("WeeklyProgress() = %d/%d, want %d/15", done, goal, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestSummarizeTodos(t *testing.T) {
+	now := time.Date(2025, 11, 14, 12, 0, 0, 0, time.Local)
+	earlier := now.Add(-2 * time.Hour)
+	tomorrow := now.AddDate(0, 0, 1)
+
+	todos := []*models.Todo{
+		{ID: "1", Deadline: &earlier},
+		{ID: "2", Deadline: &tomorrow},
+		{ID: "3", Deadline: &earlier, Completed: true},
+		{ID: "4"},
+		nil,
+	}
+
+	want := Summary{Total: 4, Incomplete: 3, Overdue: 1, DueToday: 1, Completed: 1}
+	if got := SummarizeTodos(todos, now); got != want {
+		t.Errorf("SummarizeTodos() = %+v, want %+v", got, want)
+	}
+	if got := SummarizeTodos(nil, now); got != (Summary{}) {
+		t.Errorf("SummarizeTodos(nil) = %+v, want zero counts", got)
+	}
+}
+
+func TestWeeklyCommittedPoints(t *testing.T) {
+	// A Wednesday, so the week runs from Monday the 10th to Sunday the 16th
+	now := time.Date(2025, 11, 12, 12, 0, 0, 0, time.Local)
+	monday := time.Date(2025, 11, 10, 9, 0, 0, 0, time.Local)
+	sunday := time.Date(2025, 11, 16, 23, 0, 0, 0, time.Local)
+	nextMonday := time.Date(2025, 11, 17, 9, 0, 0, 0, time.Local)
+	lastSunday := time.Date(2025, 11, 9, 9, 0, 0, 0, time.Local)
+
+	todos := []*models.Todo{
+		{ID: "1", Deadline: &monday, Points: 3},
+		{ID: "2", Deadline: &sunday, Points: 5},
+		{ID: "3", Deadline: &nextMonday, Points: 8},
+		{ID: "4", Deadline: &lastSunday, Points: 2},
+		{ID: "5", Deadline: &sunday, Points: 13, Completed: true},
+		{ID: "6", Points: 1},
+		{ID: "7", Deadline: &sunday},
+		nil,
+	}
+
+	if got := WeeklyCommittedPoints(todos, now); got != 8 {
+		t.Errorf("WeeklyCommittedPoints() = %d, want 8", got)
+	}
+	if got := WeeklyCommittedPoints(nil, now); got != 0 {
+		t.Errorf("WeeklyCommittedPoints(nil) = %d, want 0", got)
+	}
+}
+
+func TestOvercommitted(t *testing.T) {
+	tests := []struct {
+		name      string
+		committed int
+		capacity  int
+		want      bool
+	}{
+		{"Under capacity", 8, 10, false},
+		{"At capacity", 10, 10, false},
+		{"Over capacity", 11, 10, true},
+		{"No capacity", 50, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Overcommitted(tt.committed, tt.capacity); got != tt.want {
+				t.Errorf("Overcommitted(%d, %d) = %v, want %v", tt.committed, tt.capacity, got, tt.want)
+			}
+		})
+	}
+}
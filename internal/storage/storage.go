@@ -1,34 +1,73 @@
 package storage
 
 import (
+	"crypto/cipher"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/utils"
 	bolt "go.etcd.io/bbolt"
 )
 
 var (
-	todoBucket   = []byte("todos")
-	streakBucket = []byte("streaks")
+	todoBucket     = []byte("todos")
+	streakBucket   = []byte("streaks")
+	upcomingBucket = []byte("upcoming")
+	archiveBucket  = []byte("archive")
 )
 
+// ErrUnreadableTodo is returned by GetAllTodos along with the todos it could
+// read when some stored records can't be decoded
+var ErrUnreadableTodo = errors.New("some todos could not be read, run doit -doctor")
+
+// ErrDuplicateID is returned when saving a new todo whose ID is already taken
+var ErrDuplicateID = errors.New("a todo with this ID already exists")
+
 // Storage interface for todo storage operations
 type Storage interface {
 	SaveTodo(todo *models.Todo) error
+	SaveTodos(todos []*models.Todo) error
 	GetTodo(id string) (*models.Todo, error)
 	GetAllTodos() ([]*models.Todo, error)
 	UpdateTodo(todo *models.Todo) error
+	UpdateTodos(todos []*models.Todo) error
 	DeleteTodo(id string) error
+	ArchiveTodo(id string) error
+	CompleteTodos(todos []*models.Todo, at time.Time) error
+	RescheduleTodos(todos []*models.Todo, deadline time.Time) error
 	GetStreak() (*Streak, error)
 	UpdateStreak(streak *Streak) error
 	Close() error
 }
 
+// lockTimeout is how long opening the database waits for another doit
+// process to finish its transaction
+const lockTimeout = 5 * time.Second
+
 type BoltStorage struct {
-	db *bolt.DB
+	path  string
+	timer *utils.Timer
+	// mu serializes transactions, each of which opens the database, and
+	// closed is set once Close has run
+	mu     sync.Mutex
+	closed bool
+	// readOnly opens the database read-only, for merging from another one
+	readOnly bool
+	// salt is set for encrypted databases, and aead once they are unlocked
+	salt []byte
+	aead cipher.AEAD
+	// version is the last write version this storage wrote or saw, and
+	// external is set when one of its writes found a newer version that
+	// ChangedExternally hasn't reported yet
+	version  atomic.Uint64
+	external atomic.Bool
 }
 
 // Streak represents the user's streak information
@@ -38,52 +77,172 @@ type Streak struct {
 	LastCompletedAt  time.Time      `json:"last_completed_at"`
 	TotalCompleted   int            `json:"total_completed"`
 	DailyCompletions map[string]int `json:"daily_completions"`
+	// WeeklyGoal is how many todos the user aims to complete a week, 0 for
+	// no goal
+	WeeklyGoal int `json:"weekly_goal,omitempty"`
+	// WeeklyCapacity is how many points of todos the user can take on a
+	// week, 0 for no limit
+	WeeklyCapacity int `json:"weekly_capacity,omitempty"`
 }
 
-// NewBoltStorage creates a new BoltStorage instance
+// NewBoltStorage creates a new BoltStorage instance. The database is opened
+// for each transaction and closed after it, so other doit processes can
+// use it in between.
 func NewBoltStorage(dbPath string) (*BoltStorage, error) {
-	db, err := bolt.Open(dbPath, 0o600, nil)
+	s := &BoltStorage{path: dbPath}
+	err := s.withDB(func(db *bolt.DB) error {
+		if err := db.Update(s.setup); err != nil {
+			return fmt.Errorf("failed to create buckets: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
+		return nil, err
 	}
+	return s, nil
+}
 
-	err = db.Update(func(tx *bolt.Tx) error {
-		if _, err := tx.CreateBucketIfNotExists(todoBucket); err != nil {
+// setup creates the buckets and fills the indexes and counts missing from
+// databases written by older versions
+func (s *BoltStorage) setup(tx *bolt.Tx) error {
+	s.loadSalt(tx)
+	s.version.Store(readVersion(tx))
+	if _, err := tx.CreateBucketIfNotExists(todoBucket); err != nil {
+		return err
+	}
+	if _, err := tx.CreateBucketIfNotExists(streakBucket); err != nil {
+		return err
+	}
+	if _, err := tx.CreateBucketIfNotExists(archiveBucket); err != nil {
+		return err
+	}
+	if tx.Bucket(upcomingBucket) == nil {
+		if _, err := tx.CreateBucket(upcomingBucket); err != nil {
 			return err
 		}
-		if _, err := tx.CreateBucketIfNotExists(streakBucket); err != nil {
+		if err := s.rebuildUpcomingIndex(tx); err != nil {
 			return err
 		}
-		return nil
-	})
-	if err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to create buckets: %w", err)
 	}
-	return &BoltStorage{db: db}, nil
+	for _, name := range [][]byte{todoBucket, upcomingBucket} {
+		if err := recountKeys(tx.Bucket(name)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// SaveTodo saves a new todo
+// SetTimer logs how long loading and sorting todos take to timer, nil
+// disables it
+func (s *BoltStorage) SetTimer(timer *utils.Timer) {
+	s.timer = timer
+}
+
+// SaveTodo saves a new todo, stamping its CreatedAt and UpdatedAt with now. It
+// fails with ErrDuplicateID when a todo with the same ID is already stored.
 func (s *BoltStorage) SaveTodo(todo *models.Todo) error {
-	return s.db.Update(func(tx *bolt.Tx) error {
+	return s.saveTodo(todo, false)
+}
+
+// SaveTodoPreservingTimestamps saves a todo like SaveTodo, but keeps the
+// CreatedAt, UpdatedAt and CompletedAt it already has so imported history is
+// not restamped. Zero CreatedAt and UpdatedAt are set to now.
+func (s *BoltStorage) SaveTodoPreservingTimestamps(todo *models.Todo) error {
+	return s.saveTodo(todo, true)
+}
+
+// SaveTodos saves new todos like SaveTodo, all in one transaction. Nothing is
+// saved when any of them fails.
+func (s *BoltStorage) SaveTodos(todos []*models.Todo) error {
+	return s.saveTodos(todos, false)
+}
+
+// SaveTodosPreservingTimestamps saves todos like
+// SaveTodoPreservingTimestamps, all in one transaction. Nothing is saved when
+// any of them fails.
+func (s *BoltStorage) SaveTodosPreservingTimestamps(todos []*models.Todo) error {
+	return s.saveTodos(todos, true)
+}
+
+func (s *BoltStorage) saveTodo(todo *models.Todo, preserve bool) error {
+	return s.saveTodos([]*models.Todo{todo}, preserve)
+}
+
+func (s *BoltStorage) saveTodos(todos []*models.Todo, preserve bool) error {
+	return s.update(func(tx *bolt.Tx) error {
 		b := tx.Bucket(todoBucket)
+		now := time.Now()
 
-		todo.CreatedAt = time.Now()
-		todo.UpdatedAt = time.Now()
+		for _, todo := range todos {
+			// Imports restore history over stored todos, new ones never do
+			if !preserve && b.Get([]byte(todo.ID)) != nil {
+				return fmt.Errorf("%w: %s", ErrDuplicateID, todo.ID)
+			}
 
-		data, err := json.Marshal(todo)
-		if err != nil {
-			return err
+			SanitizeTodo(todo)
+			if !preserve || todo.CreatedAt.IsZero() {
+				todo.CreatedAt = now
+			}
+			if !preserve || todo.UpdatedAt.IsZero() {
+				todo.UpdatedAt = now
+			}
+
+			if err := s.putTodo(tx, todo); err != nil {
+				return err
+			}
 		}
-		return b.Put([]byte(todo.ID), data)
+		return nil
 	})
 }
 
+// putTodo writes a todo to the todos bucket, moving its upcoming index entry
+// from the version it replaces and counting it when it is new
+func (s *BoltStorage) putTodo(tx *bolt.Tx, todo *models.Todo) error {
+	data, err := s.encodeTodo(todo)
+	if err != nil {
+		return err
+	}
+	stored, err := s.storedTodo(tx, todo.ID)
+	if err != nil {
+		return err
+	}
+	if err := reindexTodo(tx, stored, todo); err != nil {
+		return err
+	}
+	b := tx.Bucket(todoBucket)
+	if stored == nil {
+		if err := countKeys(b, 1); err != nil {
+			return err
+		}
+	}
+	return b.Put([]byte(todo.ID), data)
+}
+
+// deleteTodo removes a todo from the todos bucket and the upcoming index, if
+// it is stored
+func (s *BoltStorage) deleteTodo(tx *bolt.Tx, id string) error {
+	stored, err := s.storedTodo(tx, id)
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		return nil
+	}
+	if err := reindexTodo(tx, stored, nil); err != nil {
+		return err
+	}
+	b := tx.Bucket(todoBucket)
+	if err := countKeys(b, -1); err != nil {
+		return err
+	}
+	return b.Delete([]byte(id))
+}
+
 // GetTodo retrieves a todo by ID
 func (s *BoltStorage) GetTodo(id string) (*models.Todo, error) {
 	var todo *models.Todo
 
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(todoBucket)
 		data := b.Get([]byte(id))
 
@@ -92,33 +251,53 @@ func (s *BoltStorage) GetTodo(id string) (*models.Todo, error) {
 		}
 
 		todo = &models.Todo{}
-		return json.Unmarshal(data, todo)
+		return s.decodeTodo(data, todo)
 	})
 
 	return todo, err
 }
 
-// GetAllTodos retrieves all todo
+// GetAllTodos retrieves all todos in list order. Records that can't be
+// decoded are skipped, and the todos that could be read are returned along
+// with an error wrapping ErrUnreadableTodo.
 func (s *BoltStorage) GetAllTodos() ([]*models.Todo, error) {
 	var todos []*models.Todo
+	var readErr error
 
-	err := s.db.View(func(tx *bolt.Tx) error {
+	stop := s.timer.Stage("GetAllTodos")
+	err := s.view(func(tx *bolt.Tx) error {
 		b := tx.Bucket(todoBucket)
 
 		return b.ForEach(func(k, v []byte) error {
 			var todo models.Todo
-			if err := json.Unmarshal(v, &todo); err != nil {
+			if err := s.decodeTodo(v, &todo); errors.Is(err, ErrLocked) {
 				return err
+			} else if err != nil {
+				// Keep reading so one bad record doesn't hide the rest
+				if readErr == nil {
+					readErr = fmt.Errorf("%w: todo %s: %w", ErrUnreadableTodo, k, err)
+				}
+				return nil
 			}
 			todos = append(todos, &todo)
 			return nil
 		})
 	})
+	stop()
 	if err != nil {
 		return nil, err
 	}
 
-	// Sort todos
+	stop = s.timer.Stage("sort")
+	SortTodos(todos)
+	stop()
+
+	return todos, readErr
+}
+
+// SortTodos sorts todos in list order: incomplete todos first, those by
+// closest deadline, then everything else by newest creation time
+func SortTodos(todos []*models.Todo) {
 	sort.Slice(todos, func(i, j int) bool {
 		// Incomplete todos first
 		if todos[i].Completed != todos[j].Completed {
@@ -141,45 +320,80 @@ func (s *BoltStorage) GetAllTodos() ([]*models.Todo, error) {
 		// Fallback to creation time
 		return todos[i].CreatedAt.After(todos[j].CreatedAt)
 	})
-
-	return todos, nil
 }
 
-// UpdateTodo updates an existing todo
+// UpdateTodo updates an existing todo, counting it in the streak when it is
+// completed and taking it back out when it is reopened
 func (s *BoltStorage) UpdateTodo(todo *models.Todo) error {
-	var wasCompleted bool
-	existingTodo, _ := s.GetTodo(todo.ID)
-	if existingTodo != nil {
-		wasCompleted = existingTodo.Completed
-	}
-
-	err := s.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(todoBucket)
-
-		todo.UpdatedAt = time.Now()
+	return s.update(func(tx *bolt.Tx) error {
+		return s.updateTodo(tx, todo)
+	})
+}
 
-		data, err := json.Marshal(todo)
-		if err != nil {
-			return err
+// UpdateTodos updates todos like UpdateTodo, all in one transaction. Nothing
+// is saved when any of them fails.
+func (s *BoltStorage) UpdateTodos(todos []*models.Todo) error {
+	return s.update(func(tx *bolt.Tx) error {
+		for _, todo := range todos {
+			if err := s.updateTodo(tx, todo); err != nil {
+				return err
+			}
 		}
-
-		return b.Put([]byte(todo.ID), data)
+		return nil
 	})
+}
+
+// updateTodo writes todo over its stored version and keeps the streak in
+// step. The stored version is read in the same transaction, so two
+// processes completing the same todo count it once.
+func (s *BoltStorage) updateTodo(tx *bolt.Tx, todo *models.Todo) error {
+	existing, err := s.storedTodo(tx, todo.ID)
+	if err != nil {
+		return err
+	}
+	wasCompleted := existing != nil && existing.Completed
+
+	SanitizeTodo(todo)
+	todo.UpdatedAt = time.Now()
+	if err := s.putTodo(tx, todo); err != nil {
+		return err
+	}
 
 	// Update streak if todo was marked as complete
-	if err != nil && !wasCompleted && todo.Completed {
+	if !wasCompleted && todo.Completed {
+		completedAt := time.Now()
+		if todo.CompletedAt != nil {
+			completedAt = *todo.CompletedAt
+		}
 		// Ignore if failed
-		_ = s.updateStreakOnCompletion()
+		_ = s.updateStreakOnCompletion(tx, completedAt, 1)
 	}
-
-	return err
+	// Reopening, such as undoing a completion, takes it back out of the
+	// streak
+	if wasCompleted && !todo.Completed {
+		// Ignore if failed
+		_ = s.updateStreakOnRemoval(tx, existing)
+	}
+	return nil
 }
 
 // DeleteTodo deletes a todo by ID
 func (s *BoltStorage) DeleteTodo(id string) error {
-	return s.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(todoBucket)
-		return b.Delete([]byte(id))
+	return s.update(func(tx *bolt.Tx) error {
+		existing, err := s.storedTodo(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.deleteTodo(tx, id); err != nil {
+			return err
+		}
+
+		// Remove deleted completions from the streak
+		if existing != nil && existing.Completed {
+			// Ignore if failed
+			_ = s.updateStreakOnRemoval(tx, existing)
+		}
+		return nil
 	})
 }
 
@@ -187,55 +401,75 @@ func (s *BoltStorage) DeleteTodo(id string) error {
 func (s *BoltStorage) GetStreak() (*Streak, error) {
 	var streak *Streak
 
-	err := s.db.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket(streakBucket)
-		data := b.Get([]byte("current"))
-
-		if data == nil {
-			streak = &Streak{
-				CurrentStreak:    0,
-				MaxStreak:        0,
-				TotalCompleted:   0,
-				DailyCompletions: make(map[string]int),
-			}
-			return nil
-		}
-
-		streak = &Streak{}
-		return json.Unmarshal(data, streak)
+	err := s.view(func(tx *bolt.Tx) error {
+		var err error
+		streak, err = readStreak(tx)
+		return err
 	})
 	return streak, err
 }
 
-// UpdateStreak updates the streak information
-func (s *BoltStorage) UpdateStreak(streak *Streak) error {
-	return s.db.Update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(streakBucket)
+// readStreak reads the streak inside a transaction, returning an empty one
+// when none was saved yet
+func readStreak(tx *bolt.Tx) (*Streak, error) {
+	data := tx.Bucket(streakBucket).Get([]byte("current"))
+	if data == nil {
+		return &Streak{
+			CurrentStreak:    0,
+			MaxStreak:        0,
+			TotalCompleted:   0,
+			DailyCompletions: make(map[string]int),
+		}, nil
+	}
 
-		data, err := json.Marshal(streak)
-		if err != nil {
-			return err
-		}
+	streak := &Streak{}
+	if err := json.Unmarshal(data, streak); err != nil {
+		return nil, err
+	}
+	return streak, nil
+}
+
+// writeStreak saves the streak inside a transaction
+func writeStreak(tx *bolt.Tx, streak *Streak) error {
+	data, err := json.Marshal(streak)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(streakBucket).Put([]byte("current"), data)
+}
 
-		return b.Put([]byte("current"), data)
+// UpdateStreak updates the streak information
+func (s *BoltStorage) UpdateStreak(streak *Streak) error {
+	return s.update(func(tx *bolt.Tx) error {
+		return writeStreak(tx, streak)
 	})
 }
 
-// updateStreakOnCompletion updates the streak when a todo is completed
-func (s *BoltStorage) updateStreakOnCompletion() error {
-	streak, err := s.GetStreak()
+// updateStreakOnCompletion updates the streak when count todos are completed
+// at the given time. Completions backdated to an earlier day are counted on
+// that day and the streak is recomputed.
+func (s *BoltStorage) updateStreakOnCompletion(tx *bolt.Tx, at time.Time, count int) error {
+	streak, err := readStreak(tx)
 	if err != nil {
 		return err
 	}
 
-	now := time.Now()
-	today := now.Format("2006-01-02")
-
 	if streak.DailyCompletions == nil {
 		streak.DailyCompletions = make(map[string]int)
 	}
-	streak.DailyCompletions[today]++
-	streak.TotalCompleted++
+	streak.DailyCompletions[at.Local().Format(dayLayout)] += count
+	streak.TotalCompleted += count
+
+	now := time.Now()
+	if !sameDay(at, now) {
+		longest := streak.MaxStreak
+		RecomputeStreak(streak, now)
+		streak.MaxStreak = max(longest, streak.MaxStreak)
+		if at.After(streak.LastCompletedAt) {
+			streak.LastCompletedAt = at
+		}
+		return writeStreak(tx, streak)
+	}
 
 	if !streak.LastCompletedAt.IsZero() {
 		daysSinceLastCompletion := int(now.Sub(streak.LastCompletedAt).Hours() / 24)
@@ -260,36 +494,93 @@ func (s *BoltStorage) updateStreakOnCompletion() error {
 
 	streak.LastCompletedAt = now
 
-	return s.UpdateStreak(streak)
+	return writeStreak(tx, streak)
 }
 
-// Close closes the database connection
+// withDB opens the database, runs fn and closes it again. Bolt locks the
+// file while it is open, so holding it only for one transaction lets other
+// doit processes read and write between them.
+func (s *BoltStorage) withDB(fn func(db *bolt.DB) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return bolt.ErrDatabaseNotOpen
+	}
+
+	db, err := bolt.Open(s.path, 0o600, &bolt.Options{ReadOnly: s.readOnly, Timeout: lockTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := fn(db); err != nil {
+		db.Close()
+		return err
+	}
+	return db.Close()
+}
+
+// view runs fn in a read transaction
+func (s *BoltStorage) view(fn func(tx *bolt.Tx) error) error {
+	return s.withDB(func(db *bolt.DB) error {
+		return db.View(fn)
+	})
+}
+
+// Close waits for a running transaction to finish and stops the storage
+// from opening the database again
 func (s *BoltStorage) Close() error {
-	return s.db.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
 }
 
-// GetTopUpcomingTodos returns the top N todos with the closest deadline
+// GetTopUpcomingTodos returns the top N incomplete todos with the closest
+// deadline. Deadlines are copied while filtering, so the sort never
+// dereferences a todo's Deadline and cannot see it become nil; nil todos and
+// todos without a deadline are skipped. Todos with equal deadlines keep their
+// input order.
 func GetTopUpcomingTodos(todos []*models.Todo, limit int) []*models.Todo {
-	var upcomingTodos []*models.Todo
+	type upcoming struct {
+		todo     *models.Todo
+		deadline time.Time
+	}
+
+	var entries []upcoming
 	for _, todo := range todos {
-		if !todo.Completed && todo.Deadline != nil {
-			upcomingTodos = append(upcomingTodos, todo)
+		if todo == nil || todo.Completed || todo.Deadline == nil {
+			continue
 		}
+		entries = append(entries, upcoming{todo: todo, deadline: *todo.Deadline})
 	}
 
-	sort.Slice(upcomingTodos, func(i, j int) bool {
-		if upcomingTodos[i].Deadline == nil || upcomingTodos[j].Deadline == nil {
-			return false
-		}
-		return upcomingTodos[i].Deadline.Before(*upcomingTodos[j].Deadline)
+	slices.SortStableFunc(entries, func(a, b upcoming) int {
+		return a.deadline.Compare(b.deadline)
 	})
 
-	if len(upcomingTodos) > limit {
-		return upcomingTodos[:limit]
+	var upcomingTodos []*models.Todo
+	for _, entry := range entries[:min(limit, len(entries))] {
+		upcomingTodos = append(upcomingTodos, entry.todo)
 	}
 	return upcomingTodos
 }
 
+// PartitionTodos splits todos already in SortTodos order into the top N
+// upcoming todos, incomplete todos without deadline and completed todos in a
+// single pass, without sorting again
+func PartitionTodos(sorted []*models.Todo, limit int) (upcoming, noDeadline, completed []*models.Todo) {
+	for _, todo := range sorted {
+		switch {
+		case todo.Completed:
+			completed = append(completed, todo)
+		case todo.Deadline == nil:
+			noDeadline = append(noDeadline, todo)
+		case len(upcoming) < limit:
+			upcoming = append(upcoming, todo)
+		}
+	}
+	return upcoming, noDeadline, completed
+}
+
 // GetTodosWithoutDeadline returns todos without deadline
 func GetTodosWithoutDeadline(todos []*models.Todo) []*models.Todo {
 	var noDeadlineTodos []*models.Todo
@@ -300,3 +591,44 @@ func GetTodosWithoutDeadline(todos []*models.Todo) []*models.Todo {
 	}
 	return noDeadlineTodos
 }
+
+// TagCounts returns the number of incomplete todos for each tag, including
+// the tags above nested ones such as "work" for "work/clientA"
+func TagCounts(todos []*models.Todo) map[string]int {
+	counts := make(map[string]int)
+	for _, todo := range todos {
+		if todo.Completed {
+			continue
+		}
+		// A nested tag also counts towards the tags above it, once per todo
+		seen := make(map[string]bool)
+		for _, tag := range todo.Tags {
+			for _, ancestor := range utils.TagAncestors(tag) {
+				if !seen[ancestor] {
+					seen[ancestor] = true
+					counts[ancestor]++
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// EachTodo calls fn with every todo in ID order, decoding one at a time
+// instead of loading them all. It stops at the first error fn returns. fn
+// must not use the storage, since the read transaction is still open.
+func (s *BoltStorage) EachTodo(fn func(todo *models.Todo) error) error {
+	return s.view(func(tx *bolt.Tx) error {
+		c := tx.Bucket(todoBucket).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var todo models.Todo
+			if err := s.decodeTodo(v, &todo); err != nil {
+				return fmt.Errorf("todo %s: %w", k, err)
+			}
+			if err := fn(&todo); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+const dayLayout = "2006-01-02"
+
+// RecomputeStreak rebuilds the current and max streak from DailyCompletions.
+// A streak is still current if its last day was yesterday.
+func RecomputeStreak(streak *Streak, now time.Time) {
+	day := now.Local()
+	if streak.DailyCompletions[day.Format(dayLayout)] == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	current := 0
+	for streak.DailyCompletions[day.Format(dayLayout)] > 0 {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+	streak.CurrentStreak = current
+
+	longest := 0
+	for key, count := range streak.DailyCompletions {
+		if count <= 0 {
+			continue
+		}
+		start, err := time.ParseInLocation(dayLayout, key, time.Local)
+		if err != nil {
+			continue
+		}
+		// Only count runs from their first day
+		if streak.DailyCompletions[start.AddDate(0, 0, -1).Format(dayLayout)] > 0 {
+			continue
+		}
+		run := 0
+		for d := start; streak.DailyCompletions[d.Format(dayLayout)] > 0; d = d.AddDate(0, 0, 1) {
+			run++
+		}
+		longest = max(longest, run)
+	}
+	streak.MaxStreak = max(longest, current)
+}
+
+// lastCompletionDay returns the start of the latest day with completions, or
+// the zero time when there are none
+func lastCompletionDay(daily map[string]int) time.Time {
+	var last time.Time
+	for key, count := range daily {
+		if count <= 0 {
+			continue
+		}
+		day, err := time.ParseInLocation(dayLayout, key, time.Local)
+		if err == nil && day.After(last) {
+			last = day
+		}
+	}
+	return last
+}
+
+// updateStreakOnRemoval removes a deleted completed todo from the streak
+// counts. If its day no longer has any completions the current streak is
+// recomputed and LastCompletedAt moves back to the latest remaining day, while
+// MaxStreak keeps the longest streak ever reached.
+func (s *BoltStorage) updateStreakOnRemoval(tx *bolt.Tx, todo *models.Todo) error {
+	streak, err := readStreak(tx)
+	if err != nil {
+		return err
+	}
+
+	if streak.TotalCompleted > 0 {
+		streak.TotalCompleted--
+	}
+
+	if todo.CompletedAt != nil && streak.DailyCompletions != nil {
+		day := todo.CompletedAt.Local().Format(dayLayout)
+		if streak.DailyCompletions[day] > 0 {
+			streak.DailyCompletions[day]--
+		}
+		if streak.DailyCompletions[day] == 0 {
+			delete(streak.DailyCompletions, day)
+			longest := streak.MaxStreak
+			RecomputeStreak(streak, time.Now())
+			streak.MaxStreak = max(longest, streak.MaxStreak)
+			streak.LastCompletedAt = lastCompletionDay(streak.DailyCompletions)
+		}
+	}
+
+	return writeStreak(tx, streak)
+}
+
+// NormalizeStreak validates a streak coming from outside, such as an import.
+// Days must be dates and counts must not be negative. A MaxStreak below the
+// longest run or the current streak is raised to match.
+func NormalizeStreak(streak *Streak) error {
+	if streak.DailyCompletions == nil {
+		streak.DailyCompletions = make(map[string]int)
+	}
+	if streak.CurrentStreak < 0 || streak.MaxStreak < 0 || streak.TotalCompleted < 0 {
+		return fmt.Errorf("streak counts must not be negative")
+	}
+
+	for day, count := range streak.DailyCompletions {
+		if _, err := time.Parse(dayLayout, day); err != nil {
+			return fmt.Errorf("invalid day %q, want YYYY-MM-DD", day)
+		}
+		if count < 0 {
+			return fmt.Errorf("negative completion count for %s", day)
+		}
+	}
+
+	longest := *streak
+	RecomputeStreak(&longest, streak.LastCompletedAt)
+	streak.MaxStreak = max(streak.MaxStreak, longest.MaxStreak, streak.CurrentStreak)
+	return nil
+}
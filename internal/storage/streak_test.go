@@ -0,0 +1,369 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestBoltStorage_DeleteCompletedTodoRestoresStreak(t *testing.T) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "test.db")
+
+	storage, err := NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer storage.Close()
+
+	for _, id := range []string{"keep", "remove"} {
+		if err := storage.SaveTodo(&models.Todo{ID: id, Title: id}); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+
+	keep, _ := storage.GetTodo("keep")
+	keep.MarkComplete()
+	if err := storage.UpdateTodo(keep); err != nil {
+		t.Fatalf("UpdateTodo failed: %v", err)
+	}
+
+	before, _ := storage.GetStreak()
+	today := time.Now().Format(dayLayout)
+
+	remove, _ := storage.GetTodo("remove")
+	remove.MarkComplete()
+	if err := storage.UpdateTodo(remove); err != nil {
+		t.Fatalf("UpdateTodo failed: %v", err)
+	}
+
+	completed, _ := storage.GetStreak()
+	if completed.TotalCompleted != before.TotalCompleted+1 {
+		t.Fatalf("TotalCompleted after completion = %d, want %d", completed.TotalCompleted, before.TotalCompleted+1)
+	}
+
+	if err := storage.DeleteTodo("remove"); err != nil {
+		t.Fatalf("DeleteTodo failed: %v", err)
+	}
+
+	after, _ := storage.GetStreak()
+	if after.TotalCompleted != before.TotalCompleted {
+		t.Errorf("TotalCompleted after delete = %d, want %d", after.TotalCompleted, before.TotalCompleted)
+	}
+	if after.DailyCompletions[today] != before.DailyCompletions[today] {
+		t.Errorf("DailyCompletions[today] after delete = %d, want %d", after.DailyCompletions[today], before.DailyCompletions[today])
+	}
+	if after.CurrentStreak != 1 {
+		t.Errorf("CurrentStreak after delete = %d, want 1", after.CurrentStreak)
+	}
+
+	// Deleting the last completion of the day clears the streak
+	if err := storage.DeleteTodo("keep"); err != nil {
+		t.Fatalf("DeleteTodo failed: %v", err)
+	}
+
+	cleared, _ := storage.GetStreak()
+	if cleared.TotalCompleted != 0 || cleared.DailyCompletions[today] != 0 {
+		t.Errorf("Expected counts to be clamped at 0, got total %d, today %d", cleared.TotalCompleted, cleared.DailyCompletions[today])
+	}
+	if cleared.CurrentStreak != 0 {
+		t.Errorf("CurrentStreak after deleting all completions = %d, want 0", cleared.CurrentStreak)
+	}
+}
+
+func TestBoltStorage_CompleteAfterDeletingTodaysCompletion(t *testing.T) {
+	storage, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer storage.Close()
+
+	if err := storage.UpdateStreak(&Streak{MaxStreak: 5, DailyCompletions: map[string]int{}}); err != nil {
+		t.Fatalf("UpdateStreak failed: %v", err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := storage.SaveTodo(&models.Todo{ID: id, Title: id}); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+
+	a, _ := storage.GetTodo("a")
+	a.MarkComplete()
+	if err := storage.UpdateTodo(a); err != nil {
+		t.Fatalf("UpdateTodo failed: %v", err)
+	}
+	if err := storage.DeleteTodo("a"); err != nil {
+		t.Fatalf("DeleteTodo failed: %v", err)
+	}
+
+	b, _ := storage.GetTodo("b")
+	b.MarkComplete()
+	if err := storage.UpdateTodo(b); err != nil {
+		t.Fatalf("UpdateTodo failed: %v", err)
+	}
+
+	streak, _ := storage.GetStreak()
+	if streak.DailyCompletions[time.Now().Format(dayLayout)] != 1 {
+		t.Errorf("DailyCompletions[today] = %d, want 1", streak.DailyCompletions[time.Now().Format(dayLayout)])
+	}
+	if streak.CurrentStreak != 1 {
+		t.Errorf("CurrentStreak = %d, want 1", streak.CurrentStreak)
+	}
+	if streak.MaxStreak != 5 {
+		t.Errorf("MaxStreak = %d, want the historical 5 kept", streak.MaxStreak)
+	}
+}
+
+func TestBoltStorage_ReopenTakesCompletionOutOfStreak(t *testing.T) {
+	storage, err := NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer storage.Close()
+
+	if err := storage.SaveTodo(&models.Todo{ID: "1", Title: "Pay rent"}); err != nil {
+		t.Fatalf("SaveTodo failed: %v", err)
+	}
+	todo, _ := storage.GetTodo("1")
+	before := *todo
+
+	// Complete, undo by saving the todo as it was, and complete again
+	todo.MarkComplete()
+	if err := storage.UpdateTodo(todo); err != nil {
+		t.Fatalf("UpdateTodo failed: %v", err)
+	}
+	if err := storage.UpdateTodo(&before); err != nil {
+		t.Fatalf("UpdateTodo failed: %v", err)
+	}
+
+	undone, _ := storage.GetStreak()
+	today := time.Now().Format(dayLayout)
+	if undone.TotalCompleted != 0 || undone.DailyCompletions[today] != 0 || undone.CurrentStreak != 0 {
+		t.Errorf("Streak after undoing = total %d, today %d, current %d, want all 0",
+			undone.TotalCompleted, undone.DailyCompletions[today], undone.CurrentStreak)
+	}
+
+	before.MarkComplete()
+	if err := storage.UpdateTodo(&before); err != nil {
+		t.Fatalf("UpdateTodo failed: %v", err)
+	}
+	redone, _ := storage.GetStreak()
+	if redone.TotalCompleted != 1 || redone.DailyCompletions[today] != 1 || redone.CurrentStreak != 1 {
+		t.Errorf("Streak after completing again = total %d, today %d, current %d, want all 1",
+			redone.TotalCompleted, redone.DailyCompletions[today], redone.CurrentStreak)
+	}
+}
+
+func TestBoltStorage_CompletionFromTwoProcessesCountsOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+	other, err := NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to open the database a second time: %v", err)
+	}
+	defer other.Close()
+
+	if err := s.SaveTodo(&models.Todo{ID: "1", Title: "Call bank"}); err != nil {
+		t.Fatalf("SaveTodo() error = %v", err)
+	}
+
+	// Both processes read the todo while it is still open, then complete it
+	ours, err := s.GetTodo("1")
+	if err != nil {
+		t.Fatalf("GetTodo() error = %v", err)
+	}
+	theirs, err := other.GetTodo("1")
+	if err != nil {
+		t.Fatalf("GetTodo() from the other storage error = %v", err)
+	}
+	stale := *theirs
+	ours.MarkComplete()
+	if err := s.UpdateTodo(ours); err != nil {
+		t.Fatalf("UpdateTodo() error = %v", err)
+	}
+	if err := other.CompleteTodos([]*models.Todo{&stale}, time.Now()); err != nil {
+		t.Fatalf("CompleteTodos() from the other storage error = %v", err)
+	}
+	theirs.MarkComplete()
+	if err := other.UpdateTodo(theirs); err != nil {
+		t.Fatalf("UpdateTodo() from the other storage error = %v", err)
+	}
+
+	streak, err := s.GetStreak()
+	if err != nil {
+		t.Fatalf("GetStreak() error = %v", err)
+	}
+	if streak.TotalCompleted != 1 {
+		t.Errorf("TotalCompleted = %d, want 1", streak.TotalCompleted)
+	}
+}
+
+func TestBoltStorage_BackdatedCompletion(t *testing.T) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "test.db")
+
+	storage, err := NewBoltStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer storage.Close()
+
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	lastWeek := now.AddDate(0, 0, -7)
+
+	for _, id := range []string{"yesterday", "last-week", "today"} {
+		if err := storage.SaveTodo(&models.Todo{ID: id, Title: id}); err != nil {
+			t.Fatalf("SaveTodo failed: %v", err)
+		}
+	}
+
+	for _, c := range []struct {
+		id string
+		at time.Time
+	}{
+		{"yesterday", yesterday},
+		{"last-week", lastWeek},
+		{"today", now},
+	} {
+		todo, _ := storage.GetTodo(c.id)
+		todo.MarkCompleteAt(c.at)
+		if err := storage.UpdateTodo(todo); err != nil {
+			t.Fatalf("UpdateTodo failed: %v", err)
+		}
+	}
+
+	streak, _ := storage.GetStreak()
+	for _, day := range []time.Time{yesterday, lastWeek, now} {
+		key := day.Format(dayLayout)
+		if streak.DailyCompletions[key] != 1 {
+			t.Errorf("DailyCompletions[%s] = %d, want 1", key, streak.DailyCompletions[key])
+		}
+	}
+	if streak.TotalCompleted != 3 {
+		t.Errorf("TotalCompleted = %d, want 3", streak.TotalCompleted)
+	}
+	if streak.CurrentStreak != 2 {
+		t.Errorf("CurrentStreak = %d, want 2", streak.CurrentStreak)
+	}
+
+	stored, _ := storage.GetTodo("yesterday")
+	if stored.CompletedAt == nil || !stored.CompletedAt.Equal(yesterday) {
+		t.Errorf("CompletedAt = %v, want %v", stored.CompletedAt, yesterday)
+	}
+}
+
+func TestRecomputeStreak(t *testing.T) {
+	now := time.Date(2025, 11, 16, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name            string
+		days            map[string]int
+		expectedCurrent int
+		expectedMax     int
+	}{
+		{
+			name:            "empty",
+			days:            map[string]int{},
+			expectedCurrent: 0,
+			expectedMax:     0,
+		},
+		{
+			name:            "run ending today",
+			days:            map[string]int{"2025-11-14": 1, "2025-11-15": 2, "2025-11-16": 1},
+			expectedCurrent: 3,
+			expectedMax:     3,
+		},
+		{
+			name:            "run ending yesterday is still current",
+			days:            map[string]int{"2025-11-14": 1, "2025-11-15": 1},
+			expectedCurrent: 2,
+			expectedMax:     2,
+		},
+		{
+			name:            "broken streak keeps longest run",
+			days:            map[string]int{"2025-11-01": 1, "2025-11-02": 1, "2025-11-03": 1, "2025-11-16": 1},
+			expectedCurrent: 1,
+			expectedMax:     3,
+		},
+		{
+			name:            "zero counts are ignored",
+			days:            map[string]int{"2025-11-15": 0, "2025-11-16": 1},
+			expectedCurrent: 1,
+			expectedMax:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streak := &Streak{DailyCompletions: tt.days}
+			RecomputeStreak(streak, now)
+
+			if streak.CurrentStreak != tt.expectedCurrent {
+				t.Errorf("CurrentStreak = %d, want %d", streak.CurrentStreak, tt.expectedCurrent)
+			}
+			if streak.MaxStreak != tt.expectedMax {
+				t.Errorf("MaxStreak = %d, want %d", streak.MaxStreak, tt.expectedMax)
+			}
+		})
+	}
+}
+
+func TestNormalizeStreak(t *testing.T) {
+	last := time.Date(2025, 11, 16, 18, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		streak  Streak
+		wantMax int
+		wantErr bool
+	}{
+		{
+			name: "consistent max is kept",
+			streak: Streak{MaxStreak: 5, CurrentStreak: 2, LastCompletedAt: last, DailyCompletions: map[string]int{
+				"2025-11-15": 1, "2025-11-16": 1,
+			}},
+			wantMax: 5,
+		},
+		{
+			name: "max below the longest run is raised",
+			streak: Streak{MaxStreak: 1, LastCompletedAt: last, DailyCompletions: map[string]int{
+				"2025-11-01": 1, "2025-11-02": 1, "2025-11-03": 1, "2025-11-16": 1,
+			}},
+			wantMax: 3,
+		},
+		{
+			name:    "max below the current streak is raised",
+			streak:  Streak{MaxStreak: 1, CurrentStreak: 4},
+			wantMax: 4,
+		},
+		{
+			name:    "invalid day",
+			streak:  Streak{DailyCompletions: map[string]int{"16/11/2025": 1}},
+			wantErr: true,
+		},
+		{
+			name:    "negative total",
+			streak:  Streak{TotalCompleted: -1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NormalizeStreak(&tt.streak)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeStreak() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && tt.streak.MaxStreak != tt.wantMax {
+				t.Errorf("NormalizeStreak() MaxStreak = %v, want %v", tt.streak.MaxStreak, tt.wantMax)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"encoding/binary"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// versionKey holds the write version in the meta bucket, a counter bumped by
+// every write transaction
+var versionKey = []byte("version")
+
+// Versioned is implemented by storages that count their writes, so the list
+// can tell when another process changed the todos since it last loaded them
+type Versioned interface {
+	// ChangedExternally reports whether the data was written by someone
+	// else since this storage last wrote or checked it
+	ChangedExternally() (bool, error)
+}
+
+// readVersion returns the write version, 0 for databases never written since
+// versions were introduced
+func readVersion(tx *bolt.Tx) uint64 {
+	meta := tx.Bucket(metaBucket)
+	if meta == nil {
+		return 0
+	}
+	data := meta.Get(versionKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// bumpVersion increments the write version, returning the new one
+func bumpVersion(tx *bolt.Tx) (uint64, error) {
+	meta, err := tx.CreateBucketIfNotExists(metaBucket)
+	if err != nil {
+		return 0, err
+	}
+	version := readVersion(tx) + 1
+	return version, meta.Put(versionKey, binary.BigEndian.AppendUint64(nil, version))
+}
+
+// update runs fn in a write transaction that also bumps the write version,
+// remembering the new version once it is committed. A version moved on by
+// another process since this storage last saw it is kept as a pending
+// external change, since bumping past it would hide it.
+func (s *BoltStorage) update(fn func(tx *bolt.Tx) error) error {
+	var version uint64
+	err := s.withDB(func(db *bolt.DB) error {
+		return db.Update(func(tx *bolt.Tx) error {
+			if err := fn(tx); err != nil {
+				return err
+			}
+			if readVersion(tx) != s.version.Load() {
+				s.external.Store(true)
+			}
+			var err error
+			version, err = bumpVersion(tx)
+			return err
+		})
+	})
+	if err == nil {
+		s.version.Store(version)
+	}
+	return err
+}
+
+// Version returns the write version of the database
+func (s *BoltStorage) Version() (uint64, error) {
+	var version uint64
+	err := s.view(func(tx *bolt.Tx) error {
+		version = readVersion(tx)
+		return nil
+	})
+	return version, err
+}
+
+// ChangedExternally reports whether the write version moved past the last
+// one this storage wrote or saw, or one of its writes found it had, and
+// remembers the current one
+func (s *BoltStorage) ChangedExternally() (bool, error) {
+	version, err := s.Version()
+	if err != nil {
+		return false, err
+	}
+	moved := s.version.Swap(version) != version
+	pending := s.external.Swap(false)
+	return moved || pending, nil
+}
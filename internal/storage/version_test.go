@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+func TestBoltStorage_Version(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+
+	before, err := s.Version()
+	if err != nil {
+		t.Fatalf("Version() error = %v", err)
+	}
+	todo := &models.Todo{ID: "1", Title: "Pay rent"}
+	if err := s.SaveTodo(todo); err != nil {
+		t.Fatalf("SaveTodo() error = %v", err)
+	}
+	if err := s.DeleteTodo(todo.ID); err != nil {
+		t.Fatalf("DeleteTodo() error = %v", err)
+	}
+	if after, _ := s.Version(); after != before+2 {
+		t.Errorf("Version() after two writes = %d, want %d", after, before+2)
+	}
+
+	// Its own writes are not external changes
+	if changed, err := s.ChangedExternally(); err != nil || changed {
+		t.Errorf("ChangedExternally() = %v, %v, want false", changed, err)
+	}
+
+	// Another process writes while this storage is still open
+	other, err := NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to open the database a second time: %v", err)
+	}
+	defer other.Close()
+	if err := other.SaveTodo(&models.Todo{ID: "2", Title: "Call bank"}); err != nil {
+		t.Fatalf("SaveTodo() from the other storage error = %v", err)
+	}
+	if changed, err := s.ChangedExternally(); err != nil || !changed {
+		t.Errorf("ChangedExternally() = %v, %v, want true", changed, err)
+	}
+	if changed, _ := s.ChangedExternally(); changed {
+		t.Error("ChangedExternally() should report a change only once")
+	}
+}
+
+func TestBoltStorage_VersionSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	if err := s.SaveTodo(&models.Todo{ID: "1", Title: "Pay rent"}); err != nil {
+		t.Fatalf("SaveTodo() error = %v", err)
+	}
+	version, _ := s.Version()
+	s.Close()
+
+	s, err = NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen storage: %v", err)
+	}
+	defer s.Close()
+	if got, _ := s.Version(); got != version {
+		t.Errorf("Version() after reopening = %d, want %d", got, version)
+	}
+	if changed, _ := s.ChangedExternally(); changed {
+		t.Error("A freshly opened storage should not report external changes")
+	}
+}
+
+func TestBoltStorage_ExternalWriteBeforeOwnWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer s.Close()
+	other, err := NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to open the database a second time: %v", err)
+	}
+	defer other.Close()
+
+	// The other write lands first, then this storage writes on top of it
+	if err := other.SaveTodo(&models.Todo{ID: "1", Title: "Call bank"}); err != nil {
+		t.Fatalf("SaveTodo() from the other storage error = %v", err)
+	}
+	if err := s.SaveTodo(&models.Todo{ID: "2", Title: "Pay rent"}); err != nil {
+		t.Fatalf("SaveTodo() error = %v", err)
+	}
+
+	if changed, err := s.ChangedExternally(); err != nil || !changed {
+		t.Errorf("ChangedExternally() = %v, %v, want true", changed, err)
+	}
+	if changed, _ := s.ChangedExternally(); changed {
+		t.Error("ChangedExternally() should report a change only once")
+	}
+}
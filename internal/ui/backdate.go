@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/akr411/doit/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startBackdate prompts for when the selected todo was completed, for logging
+// work done earlier
+func (m *ListModel) startBackdate() {
+	todo := m.getCurrentTodo()
+	if todo == nil || todo.Completed {
+		return
+	}
+
+	m.backdating = true
+	m.backdateTodo = todo
+	m.backdateInput = ""
+}
+
+// handleBackdate reads the completion time until it is saved or cancelled
+func (m *ListModel) handleBackdate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		m.backdating = false
+		m.backdateTodo = nil
+
+	case tea.KeyEnter:
+		now := time.Now()
+		at, err := utils.ParseCompletionTime(m.backdateInput, time.Local, now)
+		if err != nil {
+			m.toast.show(toastError, err.Error())
+			return m, nil
+		}
+
+		todo := m.backdateTodo
+		m.backdating = false
+		m.backdateTodo = nil
+		// Shown first so a note about skipped occurrences replaces it
+		m.toast.show(toastSuccess, "Completed at "+m.formatDeadline(at))
+		if err := m.completeTodoAt(todo, at); err != nil {
+			m.toast.show(toastError, err.Error())
+			return m, nil
+		}
+		return m, m.loadData
+
+	case tea.KeyBackspace:
+		input := []rune(m.backdateInput)
+		if len(input) > 0 {
+			m.backdateInput = string(input[:len(input)-1])
+		}
+
+	case tea.KeySpace, tea.KeyRunes:
+		m.backdateInput += msg.String()
+	}
+
+	return m, nil
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/akr411/doit/internal/storage"
+	"github.com/akr411/doit/internal/utils"
+)
+
+// dueSoonBanner returns a banner counting the todos that need a reminder,
+// which by default are the incomplete todos due within the next day, or an
+// empty string when there are none or now is within the quiet hours. Todos
+// waiting on someone else are left out.
+func (m *ListModel) dueSoonBanner(now time.Time) string {
+	if utils.InQuietHours(now, m.quietStart, m.quietEnd) {
+		return ""
+	}
+
+	count := 0
+	withinDay := true
+	for _, todo := range m.todos {
+		if !storage.NeedsReminder(todo, now) {
+			continue
+		}
+		count++
+		// A todo with a longer reminder lead may be due after tomorrow
+		if todo.Deadline.Sub(now) > storage.DefaultReminderBefore {
+			withinDay = false
+		}
+	}
+
+	window := "in the next 24 hours"
+	if !withinDay {
+		window = "soon"
+	}
+
+	switch count {
+	case 0:
+		return ""
+	case 1:
+		return "⏰ 1 todo due " + window
+	default:
+		return fmt.Sprintf("⏰ %d todos due %s", count, window)
+	}
+}
+
+// markStale records the backlog todos untouched for StaleAfter, and with
+// nudge set reports how many there are
+func (m *ListModel) markStale(nudge bool) {
+	stale := storage.StaleBacklog(m.todos, m.options.StaleAfter, time.Now())
+	m.stale = make(map[string]bool, len(stale))
+	for _, todo := range stale {
+		m.stale[todo.ID] = true
+	}
+
+	if nudge && len(stale) > 0 {
+		days := int(m.options.StaleAfter.Hours() / 24)
+		m.toast.show(toastInfo, fmt.Sprintf("💤 %d backlog items untouched for %d+ days", len(stale), days))
+	}
+}
@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akr411/doit/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// BulkResult summarizes the outcome of a bulk operation
+type BulkResult struct {
+	Verb      string
+	Succeeded int
+	Skipped   map[string]int
+	Errors    []error
+}
+
+func newBulkResult(verb string) BulkResult {
+	return BulkResult{Verb: verb, Skipped: make(map[string]int)}
+}
+
+// Summary renders the result as a single line, e.g.
+// "Completed 3, skipped 1 already completed"
+func (r BulkResult) Summary() string {
+	parts := []string{fmt.Sprintf("%s %d", r.Verb, r.Succeeded)}
+
+	reasons := make([]string, 0, len(r.Skipped))
+	for reason := range r.Skipped {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		parts = append(parts, fmt.Sprintf("skipped %d %s", r.Skipped[reason], reason))
+	}
+
+	if len(r.Errors) > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed (%v)", len(r.Errors), r.Errors[0]))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// markedTodos returns the marked todos in display order
+func (m *ListModel) markedTodos() []*models.Todo {
+	var marked []*models.Todo
+	for _, todo := range m.getVisibleTodos() {
+		if m.marked[todo.ID] {
+			marked = append(marked, todo)
+		}
+	}
+	return marked
+}
+
+// showBulkResult shows the summary of a bulk operation as a toast
+func (m *ListModel) showBulkResult(result BulkResult) {
+	level := toastSuccess
+	if len(result.Errors) > 0 {
+		level = toastError
+	}
+	m.toast.show(level, result.Summary())
+}
+
+// bulkComplete marks every given todo as complete, skipping those already done
+func (m *ListModel) bulkComplete(todos []*models.Todo) BulkResult {
+	result := newBulkResult("Completed")
+
+	for _, todo := range todos {
+		if todo.Completed {
+			result.Skipped["already completed"]++
+			continue
+		}
+
+		if err := m.completeTodo(todo); err != nil {
+			result.Errors = append(result.Errors, err)
+			continue
+		}
+		result.Succeeded++
+	}
+
+	return result
+}
+
+// bulkDelete deletes every given todo
+func (m *ListModel) bulkDelete(todos []*models.Todo) BulkResult {
+	result := newBulkResult("Deleted")
+
+	for _, todo := range todos {
+		if err := m.storage.DeleteTodo(todo.ID); err != nil {
+			result.Errors = append(result.Errors, err)
+			continue
+		}
+		result.Succeeded++
+	}
+
+	return result
+}
+
+// DefaultConfirmThreshold is how many marked todos D deletes with a single y
+const DefaultConfirmThreshold = 5
+
+// needsTypedConfirm reports whether the pending bulk delete is large enough
+// that the count or "yes" has to be typed to confirm it
+func (m *ListModel) needsTypedConfirm() bool {
+	return m.confirmingDelete && len(m.bulkToDelete) > m.options.ConfirmOver
+}
+
+// confirmBulkDelete deletes the marked todos awaiting confirmation
+func (m *ListModel) confirmBulkDelete() tea.Cmd {
+	m.showBulkResult(m.bulkDelete(m.bulkToDelete))
+	m.cancelDelete()
+	m.marked = make(map[string]bool)
+	return m.loadData
+}
+
+// cancelDelete closes the delete confirmation
+func (m *ListModel) cancelDelete() {
+	m.confirmingDelete = false
+	m.todoToDelete = nil
+	m.bulkToDelete = nil
+	m.confirmInput = ""
+}
+
+// handleTypedConfirm reads the confirmation of a large bulk delete, which
+// goes ahead once the number of todos or "yes" is entered
+func (m *ListModel) handleTypedConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		m.cancelDelete()
+
+	case tea.KeyEnter:
+		input := strings.ToLower(strings.TrimSpace(m.confirmInput))
+		if input == "yes" || input == strconv.Itoa(len(m.bulkToDelete)) {
+			return m, m.confirmBulkDelete()
+		}
+		m.confirmInput = ""
+
+	case tea.KeyBackspace:
+		input := []rune(m.confirmInput)
+		if len(input) > 0 {
+			m.confirmInput = string(input[:len(input)-1])
+		}
+
+	case tea.KeyRunes:
+		m.confirmInput += msg.String()
+	}
+
+	return m, nil
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBulkResult_Summary(t *testing.T) {
+	result := newBulkResult("Completed")
+	result.Succeeded = 3
+	result.Skipped["already completed"] = 1
+
+	if got := result.Summary(); got != "Completed 3, skipped 1 already completed" {
+		t.Errorf("Summary() = %q", got)
+	}
+}
+
+func TestListModel_BulkComplete(t *testing.T) {
+	todos := []*models.Todo{
+		{ID: "1", Title: "First"},
+		{ID: "2", Title: "Second"},
+		{ID: "3", Title: "Third"},
+		{ID: "4", Title: "Already done", Completed: true},
+	}
+
+	result := NewListModel(&mockStorage{}, ListOptions{}).bulkComplete(todos)
+
+	if result.Succeeded != 3 {
+		t.Errorf("Succeeded = %d, want 3", result.Succeeded)
+	}
+	if result.Skipped["already completed"] != 1 {
+		t.Errorf("Skipped already completed = %d, want 1", result.Skipped["already completed"])
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("Unexpected errors: %v", result.Errors)
+	}
+	for _, todo := range todos {
+		if !todo.Completed {
+			t.Errorf("Todo %s should be completed", todo.Title)
+		}
+	}
+}
+
+func TestListModel_BulkCompleteMarked(t *testing.T) {
+	todos := []*models.Todo{
+		{ID: "1", Title: "First"},
+		{ID: "2", Title: "Second"},
+		{ID: "3", Title: "Done", Completed: true},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	// Mark the first todo and the completed one
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	model.cursor = 2
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+	if cmd == nil {
+		t.Error("Expected bulk complete to reload the list")
+	}
+
+	if !todos[0].Completed || todos[1].Completed {
+		t.Error("Expected only the marked incomplete todo to be completed")
+	}
+	if len(model.marked) != 0 {
+		t.Error("Expected marks to be cleared after the bulk operation")
+	}
+	if !strings.Contains(model.View(), "Completed 1, skipped 1 already completed") {
+		t.Error("Expected the bulk summary in the view")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if model.toast.text != "" {
+		t.Error("Expected the summary to be dismissed by the next key")
+	}
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// ErrNoClipboard is returned when no clipboard tool is available
+var ErrNoClipboard = errors.New("no clipboard tool found (install pbcopy, wl-copy, xclip or xsel)")
+
+// Clipboard writes text to a clipboard
+type Clipboard interface {
+	Copy(text string) error
+}
+
+// clipboardCommands are tried in order until one is installed
+var clipboardCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// SystemClipboard copies text by piping it to the first available
+// clipboard command
+type SystemClipboard struct{}
+
+// Copy writes text to the system clipboard
+func (SystemClipboard) Copy(text string) error {
+	for _, args := range clipboardCommands {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return ErrNoClipboard
+}
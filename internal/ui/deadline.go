@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// DeadlineMode controls how a todo's deadline is labeled in the list
+type DeadlineMode int
+
+const (
+	// DeadlineAuto shows the days left for urgent deadlines and the date for
+	// the rest
+	DeadlineAuto DeadlineMode = iota
+	// DeadlineBoth always shows the date followed by the days left, such as
+	// "Jan 2, 3:04 PM (in 5 days)"
+	DeadlineBoth
+)
+
+// FormatDeadlineLabel labels a deadline given as the formatted date, with days
+// whole days and left the exact time left, and its urgency band
+func FormatDeadlineLabel(date string, days int, left time.Duration, band Band, mode DeadlineMode) string {
+	if mode == DeadlineBoth {
+		return fmt.Sprintf("%s (%s)", date, relativeDays(days))
+	}
+
+	switch {
+	case band == BandOverdue && left > -24*time.Hour:
+		return fmt.Sprintf("(Overdue by %s)", formatTimeLeft(-left))
+	case band == BandOverdue:
+		return fmt.Sprintf("(Overdue by %d days)", -days)
+	case band == BandSoon && days == 0:
+		return fmt.Sprintf("(Due today — %s left)", formatTimeLeft(left))
+	case band == BandSoon || band == BandWarn:
+		return fmt.Sprintf("(%d days left)", days)
+	default:
+		return "(" + date + ")"
+	}
+}
+
+// formatTimeLeft phrases less than a day in whole hours, or in minutes for
+// the last hour, such as "4h" or "25m"
+func formatTimeLeft(d time.Duration) string {
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dm", max(int(d.Minutes()), 1))
+}
+
+// relativeDays phrases days whole days left, such as "in 5 days" or "overdue
+// by 1 day"
+func relativeDays(days int) string {
+	switch {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "in 1 day"
+	case days > 1:
+		return fmt.Sprintf("in %d days", days)
+	case days == -1:
+		return "overdue by 1 day"
+	default:
+		return fmt.Sprintf("overdue by %d days", -days)
+	}
+}
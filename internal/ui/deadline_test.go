@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatDeadlineLabel(t *testing.T) {
+	const day = 24 * time.Hour
+
+	tests := []struct {
+		name string
+		days int
+		left time.Duration
+		band Band
+		mode DeadlineMode
+		want string
+	}{
+		{"Auto far away", 10, 10*day + time.Hour, BandNone, DeadlineAuto, "(Jan 2, 3:04 PM)"},
+		{"Auto warning", 2, 2*day + time.Hour, BandWarn, DeadlineAuto, "(2 days left)"},
+		{"Auto due later today", 0, 4*time.Hour + 20*time.Minute, BandSoon, DeadlineAuto, "(Due today — 4h left)"},
+		{"Auto due within the hour", 0, 25 * time.Minute, BandSoon, DeadlineAuto, "(Due today — 25m left)"},
+		{"Auto passed earlier today", -1, -2*time.Hour - 10*time.Minute, BandOverdue, DeadlineAuto, "(Overdue by 2h)"},
+		{"Auto overdue", -3, -3*day - time.Hour, BandOverdue, DeadlineAuto, "(Overdue by 3 days)"},
+		{"Both far away", 5, 5*day + time.Hour, BandNone, DeadlineBoth, "Jan 2, 3:04 PM (in 5 days)"},
+		{"Both tomorrow", 1, day + time.Hour, BandWarn, DeadlineBoth, "Jan 2, 3:04 PM (in 1 day)"},
+		{"Both today", 0, time.Hour, BandSoon, DeadlineBoth, "Jan 2, 3:04 PM (today)"},
+		{"Both overdue", -2, -2*day - time.Hour, BandOverdue, DeadlineBoth, "Jan 2, 3:04 PM (overdue by 2 days)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDeadlineLabel("Jan 2, 3:04 PM", tt.days, tt.left, tt.band, tt.mode); got != tt.want {
+				t.Errorf("FormatDeadlineLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/storage"
+	"github.com/akr411/doit/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DetailModel shows every field of a single todo in a scrollable pane, opened
+// with Enter from the list
+type DetailModel struct {
+	storage          storage.Storage
+	todo             *models.Todo
+	list             *ListModel
+	offset           int
+	confirmingDelete bool
+	toast            toast
+	width            int
+	height           int
+}
+
+type detailLoadedMsg struct {
+	todo *models.Todo
+}
+
+// NewDetailModel creates a detail view for the todo that returns to list
+func NewDetailModel(storage storage.Storage, todo *models.Todo, list *ListModel) *DetailModel {
+	return &DetailModel{
+		storage: storage,
+		todo:    todo,
+		list:    list,
+		width:   list.width,
+		height:  list.height,
+	}
+}
+
+// Init reloads the todo, picking up changes made in the edit form
+func (m *DetailModel) Init() tea.Cmd {
+	return m.reload
+}
+
+func (m *DetailModel) reload() tea.Msg {
+	todo, err := m.storage.GetTodo(m.todo.ID)
+	if err != nil {
+		return errMsg{err}
+	}
+	return detailLoadedMsg{todo: todo}
+}
+
+// Update handles a message and schedules clearing any toast it showed
+func (m *DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	if expire := m.toast.expireCmd(); expire != nil {
+		cmd = tea.Batch(cmd, expire)
+	}
+	return model, cmd
+}
+
+func (m *DetailModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case toastExpiredMsg:
+		m.toast.expire(msg)
+
+	case detailLoadedMsg:
+		if msg.todo != nil {
+			m.todo = msg.todo
+		}
+
+	case errMsg:
+		m.toast.show(toastError, msg.Error())
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		// Keep the list in step for when we return to it
+		m.list.Update(msg)
+
+	case tea.KeyMsg:
+		if m.confirmingDelete {
+			return m.handleDeleteConfirm(msg)
+		}
+
+		m.toast.text = ""
+
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+
+		case "esc", "q", "backspace", "left":
+			return m.list, m.list.loadData
+
+		case "up", "k":
+			m.offset = max(m.offset-1, 0)
+
+		case "down", "j":
+			m.offset = min(m.offset+1, m.maxOffset())
+
+		case "e":
+			form := NewEditFormModel(m.storage, m.todo, m)
+			form.SetRequireDeadline(m.list.options.RequireDeadline)
+			return form, nil
+
+		case "c":
+			m.toggleComplete()
+
+		case "d":
+			m.confirmingDelete = true
+		}
+	}
+
+	return m, nil
+}
+
+// handleDeleteConfirm deletes the todo on y and returns to the list
+func (m *DetailModel) handleDeleteConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "y":
+		m.confirmingDelete = false
+		if err := m.storage.DeleteTodo(m.todo.ID); err != nil {
+			m.toast.show(toastError, err.Error())
+			return m, nil
+		}
+		m.list.toast.show(toastSuccess, "Deleted")
+		return m.list, m.list.loadData
+
+	default:
+		m.confirmingDelete = false
+	}
+
+	return m, nil
+}
+
+// toggleComplete completes or reopens the todo, scheduling the next
+// occurrence of a repeating one like the list does
+func (m *DetailModel) toggleComplete() {
+	if m.todo.Completed {
+		m.todo.MarkIncomplete()
+		if err := m.storage.UpdateTodo(m.todo); err != nil {
+			m.toast.show(toastError, err.Error())
+			return
+		}
+		m.toast.show(toastInfo, "Reopened")
+		return
+	}
+
+	if err := m.list.completeTodo(m.todo); err != nil {
+		m.toast.show(toastError, err.Error())
+		return
+	}
+	m.toast.show(toastSuccess, "Completed")
+}
+
+// size returns the terminal size, or 80x24 until it is known
+func (m *DetailModel) size() (width, height int) {
+	width, height = m.width, m.height
+	if width <= 0 {
+		width = defaultViewWidth
+	}
+	if height <= 0 {
+		height = defaultViewHeight
+	}
+	return width, height
+}
+
+// pageHeight is the number of content lines shown above the footer
+func (m *DetailModel) pageHeight() int {
+	_, height := m.size()
+	return max(height-3, 1)
+}
+
+func (m *DetailModel) maxOffset() int {
+	return max(len(m.contentLines())-m.pageHeight(), 0)
+}
+
+// contentLines renders every field of the todo, one line each except for the
+// wrapped description
+func (m *DetailModel) contentLines() []string {
+	width, _ := m.size()
+	dateFormat := m.list.dateFormat
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#7C3AED")).
+		Bold(true).
+		Width(width - 2)
+
+	headingStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#9333EA"))
+
+	labelStyle := headingStyle.Width(11)
+
+	bodyStyle := lipgloss.NewStyle().
+		Width(width - 4).
+		PaddingLeft(2)
+
+	formatTime := func(t time.Time) string {
+		return utils.FormatTime(t.Local(), dateFormat)
+	}
+	field := func(label, value string) string {
+		return labelStyle.Render(label) + value
+	}
+
+	todo := m.todo
+	var lines []string
+	lines = append(lines, strings.Split(titleStyle.Render(todo.Title), "\n")...)
+	lines = append(lines, "")
+
+	status := "Open"
+	if todo.Completed {
+		status = "Completed"
+		if todo.CompletedAt != nil {
+			status += " " + formatTime(*todo.CompletedAt)
+		}
+	}
+	lines = append(lines, field("Status", status))
+	if todo.Completed && todo.CompletionNote != "" {
+		lines = append(lines, field("Note", todo.CompletionNote))
+	}
+
+	if todo.Deadline != nil {
+		deadline := m.list.formatDeadline(*todo.Deadline)
+		if due := dueInLabel(todo); due != "" {
+			deadline += " (" + due + ")"
+		}
+		lines = append(lines, field("Deadline", deadline))
+	}
+	if todo.PlannedFor != nil {
+		lines = append(lines, field("Planned", utils.FormatDate(todo.PlannedFor.Local(), dateFormat)))
+	}
+	if todo.Points > 0 {
+		lines = append(lines, field("Points", fmt.Sprintf("%d", todo.Points)))
+	}
+	if todo.Deadline != nil && todo.ReminderBefore > 0 {
+		lines = append(lines, field("Remind", formatLeadTime(todo.ReminderBefore)+" before"))
+	}
+	if todo.Waiting {
+		waiting := "yes"
+		if todo.WaitingOn != "" {
+			waiting = todo.WaitingOn
+		}
+		lines = append(lines, field("Waiting on", waiting))
+	}
+	if todo.Recurrence != models.RecurNone {
+		lines = append(lines, field("Repeats", string(todo.Recurrence)))
+	}
+	if todo.Project != "" {
+		lines = append(lines, field("Project", todo.Project))
+	}
+	if len(todo.Tags) > 0 {
+		lines = append(lines, field("Tags", strings.Join(todo.Tags, ", ")))
+	}
+	lines = append(lines, field("Created", formatTime(todo.CreatedAt)))
+	if !todo.UpdatedAt.IsZero() {
+		lines = append(lines, field("Updated", formatTime(todo.UpdatedAt)))
+	}
+
+	if todo.Description != "" {
+		lines = append(lines, "", headingStyle.Render("Description"))
+		description := todo.Description
+		if m.list.options.Markdown {
+			description = RenderMarkdown(description)
+		}
+		lines = append(lines, strings.Split(bodyStyle.Render(description), "\n")...)
+	}
+
+	if len(todo.Subtasks) > 0 {
+		done, total := todo.SubtaskProgress()
+		lines = append(lines, "", headingStyle.Render(fmt.Sprintf("Subtasks (%d/%d)", done, total)))
+		for _, subtask := range todo.Subtasks {
+			box := "[ ]"
+			if subtask.Done {
+				box = "[✔]"
+			}
+			lines = append(lines, "  "+box+" "+subtask.Title)
+		}
+	}
+
+	if len(todo.Attachments) > 0 {
+		lines = append(lines, "", headingStyle.Render("Attachments"))
+		for _, attachment := range todo.Attachments {
+			lines = append(lines, "  📎 "+attachment)
+		}
+	}
+
+	return lines
+}
+
+// formatLeadTime formats a reminder lead time in the relative deadline units,
+// such as "1d 2h" or "30m"
+func formatLeadTime(d time.Duration) string {
+	units := []struct {
+		size   time.Duration
+		suffix string
+	}{
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+
+	var parts []string
+	for _, unit := range units {
+		if n := d / unit.size; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", n, unit.suffix))
+			d -= n * unit.size
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// View renders the visible part of the detail pane and its footer
+func (m *DetailModel) View() string {
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444"))
+
+	lines := m.contentLines()
+	offset := min(m.offset, m.maxOffset())
+	end := min(offset+m.pageHeight(), len(lines))
+
+	var s strings.Builder
+	s.WriteString(strings.Join(lines[offset:end], "\n"))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.confirmingDelete:
+		s.WriteString(errorStyle.Render(" Delete this todo? [y] Yes • any other key cancels"))
+	case m.toast.View() != "":
+		s.WriteString(m.toast.View())
+	default:
+		s.WriteString(helpStyle.Render("↑/↓: Scroll • e: Edit • c: Complete/reopen • d: Delete • Esc: Back"))
+	}
+
+	return s.String()
+}
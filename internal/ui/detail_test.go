@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func detailTodo() *models.Todo {
+	created := time.Date(2025, 11, 10, 9, 0, 0, 0, time.Local)
+	completed := created.Add(48 * time.Hour)
+	deadline := created.Add(72 * time.Hour)
+	return &models.Todo{
+		ID:          "1",
+		Title:       "Prepare quarterly report",
+		Description: "Collect numbers from finance and draft the summary",
+		Deadline:    &deadline,
+		Tags:        []string{"work", "urgent"},
+		Subtasks:    []models.Subtask{{Title: "Collect", Done: true}, {Title: "Draft"}},
+		Attachments: []string{"/docs/q3.xlsx"},
+		Recurrence:  models.RecurMonthly,
+		Completed:   true,
+		CompletedAt: &completed,
+		CreatedAt:   created,
+		UpdatedAt:   completed,
+	}
+}
+
+func TestDetailModel_RendersAllFields(t *testing.T) {
+	list := NewListModel(&mockStorage{}, ListOptions{})
+	detail := NewDetailModel(&mockStorage{}, detailTodo(), list)
+	detail.Update(tea.WindowSizeMsg{Width: 100, Height: 60})
+
+	view := detail.View()
+	for _, want := range []string{
+		"Prepare quarterly report",
+		"Collect numbers from finance and draft the summary",
+		"Completed Nov 12, 9:00 AM",
+		"Deadline",
+		"Nov 13, 9:00 AM",
+		"Repeats    monthly",
+		"Tags       work, urgent",
+		"Created    Nov 10, 9:00 AM",
+		"Updated    Nov 12, 9:00 AM",
+		"Subtasks (1/2)",
+		"[✔] Collect",
+		"[ ] Draft",
+		"📎 /docs/q3.xlsx",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected the detail view to contain %q, got:\n%s", want, view)
+		}
+	}
+}
+
+func TestDetailModel_Scroll(t *testing.T) {
+	list := NewListModel(&mockStorage{}, ListOptions{})
+	detail := NewDetailModel(&mockStorage{}, detailTodo(), list)
+	detail.Update(tea.WindowSizeMsg{Width: 100, Height: 8})
+
+	if strings.Contains(detail.View(), "q3.xlsx") {
+		t.Fatal("Expected the attachments to be below the fold")
+	}
+
+	for range 50 {
+		detail.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	if detail.offset != detail.maxOffset() {
+		t.Errorf("offset = %d, want it clamped to %d", detail.offset, detail.maxOffset())
+	}
+	if !strings.Contains(detail.View(), "q3.xlsx") {
+		t.Error("Expected scrolling down to reveal the attachments")
+	}
+}
+
+func TestListModel_EnterOpensDetailAndEscReturns(t *testing.T) {
+	todo := &models.Todo{ID: "1", Title: "Water plants"}
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+
+	next, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	detail, ok := next.(*DetailModel)
+	if !ok {
+		t.Fatalf("Enter = %T, want *DetailModel", next)
+	}
+	if detail.todo != todo {
+		t.Error("Expected the detail view to show the selected todo")
+	}
+
+	back, cmd := detail.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if back != model {
+		t.Errorf("Esc = %T, want the list it was opened from", back)
+	}
+	if cmd == nil {
+		t.Error("Expected returning to the list to reload it")
+	}
+}
+
+func TestDetailModel_EditAndComplete(t *testing.T) {
+	store := &mockStorage{}
+	todo := &models.Todo{ID: "1", Title: "Call", Description: "Dentist"}
+	list := NewListModel(store, ListOptions{})
+	detail := NewDetailModel(store, todo, list)
+
+	next, _ := detail.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	form, ok := next.(*FormModel)
+	if !ok {
+		t.Fatalf("e = %T, want *FormModel", next)
+	}
+	if form.fields[titleField] != "Call" || form.fields[descriptionField] != "Dentist" {
+		t.Errorf("Expected the form to be filled in, got %q", form.fields)
+	}
+
+	form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'!'}})
+	form.currentField = deadlineField
+	back, _ := form.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if back != detail {
+		t.Fatalf("Submitting the edit = %T, want the detail view", back)
+	}
+	if len(store.updated) != 1 || store.updated[0].Title != "Call!" || store.updated[0].ID != "1" {
+		t.Errorf("Expected the todo to be updated in place, got %v", store.updated)
+	}
+	if len(store.saved) != 0 {
+		t.Error("Editing should not create a new todo")
+	}
+
+	detail.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if !detail.todo.Completed {
+		t.Error("Expected c to complete the todo")
+	}
+}
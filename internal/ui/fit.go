@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FitMode controls how titles wider than the terminal are displayed
+type FitMode int
+
+const (
+	// FitTruncate cuts long titles and appends an ellipsis
+	FitTruncate FitMode = iota
+	// FitWrap wraps long titles onto multiple lines
+	FitWrap
+)
+
+const ellipsis = "…"
+
+// FitTitle fits a title into the given width, returning one line when
+// truncating and one or more lines when wrapping. A width of zero or less
+// leaves the title untouched.
+func FitTitle(title string, width int, mode FitMode) []string {
+	if width <= 0 || lipgloss.Width(title) <= width {
+		return []string{title}
+	}
+
+	if mode == FitWrap {
+		return wrapTitle(title, width)
+	}
+	return []string{truncateTitle(title, width)}
+}
+
+func truncateTitle(title string, width int) string {
+	limit := width - lipgloss.Width(ellipsis)
+
+	var b strings.Builder
+	used := 0
+	for _, r := range title {
+		w := lipgloss.Width(string(r))
+		if used+w > limit {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+
+	return strings.TrimRight(b.String(), " ") + ellipsis
+}
+
+func wrapTitle(title string, width int) []string {
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+
+	flush := func() {
+		lines = append(lines, line.String())
+		line.Reset()
+		lineWidth = 0
+	}
+
+	for _, word := range strings.Fields(title) {
+		wordWidth := lipgloss.Width(word)
+
+		if lineWidth > 0 && lineWidth+1+wordWidth > width {
+			flush()
+		}
+		if lineWidth > 0 {
+			line.WriteString(" ")
+			lineWidth++
+		}
+
+		// Hard split words that do not fit on a line of their own
+		for _, r := range word {
+			w := lipgloss.Width(string(r))
+			if lineWidth+w > width {
+				flush()
+			}
+			line.WriteRune(r)
+			lineWidth += w
+		}
+	}
+
+	if lineWidth > 0 {
+		flush()
+	}
+
+	return lines
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestFitTitle_Truncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		width    int
+		expected string
+	}{
+		{
+			name:     "fits",
+			title:    "Short title",
+			width:    20,
+			expected: "Short title",
+		},
+		{
+			name:     "exact width",
+			title:    "Exactly ten",
+			width:    11,
+			expected: "Exactly ten",
+		},
+		{
+			name:     "truncated with ellipsis",
+			title:    "Write the quarterly report",
+			width:    10,
+			expected: "Write the…",
+		},
+		{
+			name:     "no width known",
+			title:    "Write the quarterly report",
+			width:    0,
+			expected: "Write the quarterly report",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := FitTitle(tt.title, tt.width, FitTruncate)
+			if len(lines) != 1 {
+				t.Fatalf("FitTitle() returned %d lines, want 1", len(lines))
+			}
+			if lines[0] != tt.expected {
+				t.Errorf("FitTitle() = %q, want %q", lines[0], tt.expected)
+			}
+			if tt.width > 0 && lipgloss.Width(lines[0]) > tt.width {
+				t.Errorf("FitTitle() width = %d, exceeds %d", lipgloss.Width(lines[0]), tt.width)
+			}
+		})
+	}
+}
+
+func TestFitTitle_Wrap(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		width    int
+		expected []string
+	}{
+		{
+			name:     "fits",
+			title:    "Short title",
+			width:    20,
+			expected: []string{"Short title"},
+		},
+		{
+			name:     "wraps on words",
+			title:    "Write the quarterly report for finance",
+			width:    15,
+			expected: []string{"Write the", "quarterly", "report for", "finance"},
+		},
+		{
+			name:     "splits long words",
+			title:    "Supercalifragilistic day",
+			width:    8,
+			expected: []string{"Supercal", "ifragili", "stic day"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := FitTitle(tt.title, tt.width, FitWrap)
+			if strings.Join(lines, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("FitTitle() = %q, want %q", lines, tt.expected)
+			}
+			for _, line := range lines {
+				if lipgloss.Width(line) > tt.width {
+					t.Errorf("Line %q width %d exceeds %d", line, lipgloss.Width(line), tt.width)
+				}
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/akr411/doit/internal/models"
 	"github.com/akr411/doit/internal/storage"
@@ -20,11 +22,9 @@ const (
 	deadlineField
 )
 
-// Character limits
-const (
-	MaxTitleLength       = 100
-	MaxDescriptionLength = 500
-)
+// ErrDeadlineRequired is returned when a todo without a deadline is submitted
+// in strict mode
+var ErrDeadlineRequired = errors.New("deadline is required in strict mode")
 
 // FormModel represents the form input model
 type FormModel struct {
@@ -33,8 +33,17 @@ type FormModel struct {
 	currentField formField
 	cursor       int
 	done         bool
-	err          error
+	toast        toast
 	submitted    bool
+	dirty        bool
+	confirmQuit  bool
+	// editing is the todo being edited, nil when creating a new one
+	editing *models.Todo
+	// back is shown again when the form is submitted or cancelled, instead
+	// of quitting
+	back tea.Model
+	// requireDeadline rejects todos without a deadline
+	requireDeadline bool
 }
 
 // NewFormModel creates a new form model
@@ -46,19 +55,71 @@ func NewFormModel(storage storage.Storage) *FormModel {
 	}
 }
 
+// NewEditFormModel creates a form editing the title, description and
+// deadline of an existing todo. The back model is shown again once the form
+// is submitted or cancelled.
+func NewEditFormModel(storage storage.Storage, todo *models.Todo, back tea.Model) *FormModel {
+	m := NewFormModel(storage)
+	m.editing = todo
+	m.back = back
+	m.fields[titleField] = todo.Title
+	m.fields[descriptionField] = todo.Description
+	m.fields[deadlineField] = prefilledDeadline(todo)
+	m.cursor = len(m.fields[titleField])
+	return m
+}
+
+// prefilledDeadline is the deadline field's text when editing todo, empty if
+// it has no deadline
+func prefilledDeadline(todo *models.Todo) string {
+	if todo.Deadline == nil {
+		return ""
+	}
+	return todo.Deadline.Local().Format("2006-01-02 15:04")
+}
+
+// SetRequireDeadline makes submitting without a deadline an error
+func (m *FormModel) SetRequireDeadline(require bool) {
+	m.requireDeadline = require
+}
+
 // Init initializes the form model
 func (m *FormModel) Init() tea.Cmd {
 	return nil
 }
 
+// Update handles a message and schedules clearing any toast it showed
 func (m *FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	if expire := m.toast.expireCmd(); expire != nil {
+		cmd = tea.Batch(cmd, expire)
+	}
+	return model, cmd
+}
+
+func (m *FormModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case toastExpiredMsg:
+		m.toast.expire(msg)
+
 	case tea.KeyMsg:
+		if m.confirmQuit {
+			return m.handleQuitConfirm(msg)
+		}
+
 		switch msg.String() {
-		case "ctrl+c", "esc":
+		case "ctrl+c":
 			m.done = true
 			return m, tea.Quit
 
+		case "esc":
+			if m.dirty {
+				m.confirmQuit = true
+				return m, nil
+			}
+			m.done = true
+			return m.leave()
+
 		case "tab", "down":
 			if m.currentField < deadlineField {
 				m.currentField++
@@ -77,28 +138,32 @@ func (m *FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cursor = 0
 			} else {
 				if err := m.submitForm(); err != nil {
-					m.err = err
+					m.toast.show(toastError, err.Error())
 				} else {
 					m.submitted = true
-					return m, tea.Quit
+					return m.leave()
 				}
 			}
 
 		case "backspace":
 			if m.cursor > 0 {
 				field := m.fields[m.currentField]
-				m.fields[m.currentField] = field[:m.cursor-1] + field[m.cursor:]
-				m.cursor--
+				_, size := utf8.DecodeLastRuneInString(field[:m.cursor])
+				m.fields[m.currentField] = field[:m.cursor-size] + field[m.cursor:]
+				m.cursor -= size
+				m.dirty = true
 			}
 
 		case "left":
 			if m.cursor > 0 {
-				m.cursor--
+				_, size := utf8.DecodeLastRuneInString(m.fields[m.currentField][:m.cursor])
+				m.cursor -= size
 			}
 
 		case "right":
 			if m.cursor < len(m.fields[m.currentField]) {
-				m.cursor++
+				_, size := utf8.DecodeRuneInString(m.fields[m.currentField][m.cursor:])
+				m.cursor += size
 			}
 
 		case "home":
@@ -108,18 +173,19 @@ func (m *FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.cursor = len(m.fields[m.currentField])
 
 		default:
-			if len(msg.String()) == 1 {
+			if char := typedChar(msg); char != "" {
 				canAddChar := true
 				switch m.currentField {
 				case titleField:
-					canAddChar = len(m.fields[titleField]) < MaxTitleLength
+					canAddChar = utf8.RuneCountInString(m.fields[titleField]) < models.MaxTitleLength
 				case descriptionField:
-					canAddChar = len(m.fields[descriptionField]) < MaxDescriptionLength
+					canAddChar = utf8.RuneCountInString(m.fields[descriptionField]) < models.MaxDescriptionLength
 				}
 				if canAddChar {
 					field := m.fields[m.currentField]
-					m.fields[m.currentField] = field[:m.cursor] + msg.String() + field[m.cursor:]
-					m.cursor++
+					m.fields[m.currentField] = field[:m.cursor] + char + field[m.cursor:]
+					m.cursor += len(char)
+					m.dirty = true
 				}
 			}
 		}
@@ -128,6 +194,48 @@ func (m *FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleQuitConfirm handles the save/discard/cancel prompt shown when
+// quitting with unsaved changes
+func (m *FormModel) handleQuitConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "s":
+		m.confirmQuit = false
+		if err := m.submitForm(); err != nil {
+			m.toast.show(toastError, err.Error())
+			return m, nil
+		}
+		m.submitted = true
+		return m.leave()
+
+	case "d":
+		m.done = true
+		return m.leave()
+
+	case "ctrl+c":
+		m.done = true
+		return m, tea.Quit
+
+	case "c", "esc":
+		m.confirmQuit = false
+	}
+
+	return m, nil
+}
+
+// leave returns to the back model, or quits when the form was opened on its
+// own
+func (m *FormModel) leave() (tea.Model, tea.Cmd) {
+	if m.back != nil {
+		return m.back, m.back.Init()
+	}
+	return m, tea.Quit
+}
+
+// Dirty reports whether the form has unsaved changes
+func (m *FormModel) Dirty() bool {
+	return m.dirty
+}
+
 // View renders the form
 func (m *FormModel) View() string {
 	if m.submitted {
@@ -174,12 +282,36 @@ func (m *FormModel) View() string {
 		Foreground(lipgloss.Color("#9CA3AF")).
 		PaddingLeft(2)
 
+	limitStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#EF4444")).
+		PaddingLeft(2)
+
+	// limitLabel renders a field label with its character count, red once
+	// the field is full
+	limitLabel := func(name, value string, limit int) string {
+		label := fmt.Sprintf("%s (%d/%d)", name, utf8.RuneCountInString(value), limit)
+		if utf8.RuneCountInString(value) >= limit {
+			return labelStyle.Foreground(lipgloss.Color("#EF4444")).Render(label)
+		}
+		return labelStyle.Render(label)
+	}
+	// limitHint tells that a full field takes no more characters
+	limitHint := func(value string, limit int) string {
+		if utf8.RuneCountInString(value) < limit {
+			return ""
+		}
+		return "\n" + limitStyle.Render(fmt.Sprintf("Max reached: %d characters", limit))
+	}
+
 	var s strings.Builder
-	s.WriteString(titleStyle.Render("Create New Todo"))
+	heading := "Create New Todo"
+	if m.editing != nil {
+		heading = "Edit Todo"
+	}
+	s.WriteString(titleStyle.Render(heading))
 	s.WriteString("\n\n")
 
-	titleLabel := fmt.Sprintf("Title * (%d/%d)", len(m.fields[titleField]), MaxTitleLength)
-	s.WriteString(labelStyle.Render(titleLabel))
+	s.WriteString(limitLabel("Title *", m.fields[titleField], models.MaxTitleLength))
 	s.WriteString("\n")
 	titleContent := m.fields[titleField]
 	if m.currentField == titleField {
@@ -191,10 +323,10 @@ func (m *FormModel) View() string {
 		}
 		s.WriteString(inactiveStyle.Render(titleContent))
 	}
+	s.WriteString(limitHint(m.fields[titleField], models.MaxTitleLength))
 	s.WriteString("\n\n")
 
-	descLabel := fmt.Sprintf("Description * (%d/%d)", len(m.fields[descriptionField]), MaxDescriptionLength)
-	s.WriteString(labelStyle.Render(descLabel))
+	s.WriteString(limitLabel("Description *", m.fields[descriptionField], models.MaxDescriptionLength))
 	s.WriteString("\n")
 	descContent := m.fields[descriptionField]
 	if m.currentField == descriptionField {
@@ -206,6 +338,7 @@ func (m *FormModel) View() string {
 		}
 		s.WriteString(inactiveStyle.Render(descContent))
 	}
+	s.WriteString(limitHint(m.fields[descriptionField], models.MaxDescriptionLength))
 	s.WriteString("\n\n")
 
 	s.WriteString(labelStyle.Render("Deadline"))
@@ -224,9 +357,14 @@ func (m *FormModel) View() string {
 		s.WriteString(inactiveStyle.Render(deadlineContent))
 	}
 
-	if m.err != nil {
+	if toast := m.toast.View(); toast != "" {
+		s.WriteString("\n\n")
+		s.WriteString(toast)
+	}
+
+	if m.confirmQuit {
 		s.WriteString("\n")
-		s.WriteString(errorStyle.Render("Error: " + m.err.Error()))
+		s.WriteString(errorStyle.Render("Unsaved changes: [s] Save • [d] Discard • [c] Cancel"))
 	}
 
 	s.WriteString("\n")
@@ -235,6 +373,18 @@ func (m *FormModel) View() string {
 	return s.String()
 }
 
+// typedChar returns the single character typed with a key, or "" for named
+// keys and pastes. Multibyte characters are returned whole.
+func typedChar(msg tea.KeyMsg) string {
+	switch {
+	case msg.Type == tea.KeySpace:
+		return " "
+	case msg.Type == tea.KeyRunes && len(msg.Runes) == 1 && !msg.Paste:
+		return string(msg.Runes)
+	}
+	return ""
+}
+
 func (m *FormModel) addCursor(text string) string {
 	if m.cursor >= len(text) {
 		return text + "█"
@@ -250,25 +400,43 @@ func (m *FormModel) submitForm() error {
 		return fmt.Errorf("description is required")
 	}
 
-	if len(m.fields[titleField]) > MaxTitleLength {
-		return fmt.Errorf("title exceeds maximum length of %d characters", MaxTitleLength)
+	if utf8.RuneCountInString(m.fields[titleField]) > models.MaxTitleLength {
+		return fmt.Errorf("title exceeds maximum length of %d characters", models.MaxTitleLength)
 	}
-	if len(m.fields[descriptionField]) > MaxDescriptionLength {
-		return fmt.Errorf("description exceeds maximum length of %d characters", MaxDescriptionLength)
+	if utf8.RuneCountInString(m.fields[descriptionField]) > models.MaxDescriptionLength {
+		return fmt.Errorf("description exceeds maximum length of %d characters", models.MaxDescriptionLength)
 	}
 
 	var deadline *time.Time
-	if strings.TrimSpace(m.fields[deadlineField]) != "" {
+	if m.editing != nil && m.fields[deadlineField] == prefilledDeadline(m.editing) {
+		// Left as prefilled, so keep the deadline's zone and seconds
+		deadline = m.editing.Deadline
+	} else if strings.TrimSpace(m.fields[deadlineField]) != "" {
 		parsed, err := utils.ParseDeadline(strings.TrimSpace(m.fields[deadlineField]))
 		if err != nil {
 			return err
 		}
 		deadline = parsed
 	}
+	if deadline == nil && m.requireDeadline {
+		return ErrDeadlineRequired
+	}
+
+	if m.editing != nil {
+		todo := *m.editing
+		todo.Title = strings.TrimSpace(m.fields[titleField])
+		todo.Description = strings.TrimSpace(m.fields[descriptionField])
+		todo.Deadline = deadline
+		if err := m.storage.UpdateTodo(&todo); err != nil {
+			return err
+		}
+		*m.editing = todo
+		return nil
+	}
 
 	now := time.Now()
 	todo := models.Todo{
-		ID:          fmt.Sprintf("%d", now.UnixNano()),
+		ID:          models.NewID(),
 		Title:       strings.TrimSpace(m.fields[titleField]),
 		Description: strings.TrimSpace(m.fields[descriptionField]),
 		Deadline:    deadline,
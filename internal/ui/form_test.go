@@ -1,18 +1,31 @@
 package ui
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/akr411/doit/internal/models"
 	"github.com/akr411/doit/internal/storage"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-type mockStorage struct{}
+type mockStorage struct {
+	saved   []*models.Todo
+	updated []*models.Todo
+	deleted []string
+}
 
 func (m *mockStorage) SaveTodo(todo *models.Todo) error {
+	m.saved = append(m.saved, todo)
+	return nil
+}
+
+func (m *mockStorage) SaveTodos(todos []*models.Todo) error {
+	m.saved = append(m.saved, todos...)
 	return nil
 }
 
@@ -25,10 +38,37 @@ func (m *mockStorage) GetAllTodos() ([]*models.Todo, error) {
 }
 
 func (m *mockStorage) UpdateTodo(todo *models.Todo) error {
+	m.updated = append(m.updated, todo)
+	return nil
+}
+
+func (m *mockStorage) UpdateTodos(todos []*models.Todo) error {
+	m.updated = append(m.updated, todos...)
 	return nil
 }
 
 func (m *mockStorage) DeleteTodo(id string) error {
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func (m *mockStorage) ArchiveTodo(id string) error {
+	return nil
+}
+
+func (m *mockStorage) CompleteTodos(todos []*models.Todo, at time.Time) error {
+	for _, todo := range todos {
+		todo.MarkCompleteAt(at)
+	}
+	m.updated = append(m.updated, todos...)
+	return nil
+}
+
+func (m *mockStorage) RescheduleTodos(todos []*models.Todo, deadline time.Time) error {
+	for _, todo := range todos {
+		todo.Deadline = &deadline
+	}
+	m.updated = append(m.updated, todos...)
 	return nil
 }
 
@@ -63,42 +103,42 @@ func TestFormModel_CharacterLimit(t *testing.T) {
 			fieldType:    titleField,
 			inputLength:  50,
 			expectAccept: true,
-			maxLength:    MaxTitleLength,
+			maxLength:    models.MaxTitleLength,
 		},
 		{
 			name:         "Title at exact limit",
 			fieldType:    titleField,
-			inputLength:  MaxTitleLength,
+			inputLength:  models.MaxTitleLength,
 			expectAccept: true,
-			maxLength:    MaxTitleLength,
+			maxLength:    models.MaxTitleLength,
 		},
 		{
 			name:         "Title exceeds limit",
 			fieldType:    titleField,
-			inputLength:  MaxTitleLength + 1,
+			inputLength:  models.MaxTitleLength + 1,
 			expectAccept: false,
-			maxLength:    MaxTitleLength,
+			maxLength:    models.MaxTitleLength,
 		},
 		{
 			name:         "Description within limit",
 			fieldType:    descriptionField,
 			inputLength:  250,
 			expectAccept: true,
-			maxLength:    MaxDescriptionLength,
+			maxLength:    models.MaxDescriptionLength,
 		},
 		{
 			name:         "Description at exact limit",
 			fieldType:    descriptionField,
-			inputLength:  MaxDescriptionLength,
+			inputLength:  models.MaxDescriptionLength,
 			expectAccept: true,
-			maxLength:    MaxDescriptionLength,
+			maxLength:    models.MaxDescriptionLength,
 		},
 		{
 			name:         "Description exceeds limit",
 			fieldType:    descriptionField,
-			inputLength:  MaxDescriptionLength + 1,
+			inputLength:  models.MaxDescriptionLength + 1,
 			expectAccept: false,
-			maxLength:    MaxDescriptionLength,
+			maxLength:    models.MaxDescriptionLength,
 		},
 	}
 
@@ -145,6 +185,13 @@ func TestFormModel_ValidateSubmission(t *testing.T) {
 			expectError: true,
 			errorMsg:    "title is required",
 		},
+		{
+			name:        "Whitespace-only title",
+			title:       "   ",
+			description: "Test Description",
+			expectError: true,
+			errorMsg:    "title is required",
+		},
 		{
 			name:        "Empty description",
 			title:       "Test Todo",
@@ -154,7 +201,7 @@ func TestFormModel_ValidateSubmission(t *testing.T) {
 		},
 		{
 			name:        "Title exceeds limit",
-			title:       strings.Repeat("a", MaxTitleLength+1),
+			title:       strings.Repeat("a", models.MaxTitleLength+1),
 			description: "Test Description",
 			expectError: true,
 			errorMsg:    "title exceeds maximum",
@@ -162,7 +209,7 @@ func TestFormModel_ValidateSubmission(t *testing.T) {
 		{
 			name:        "Description exceeds limit",
 			title:       "Test Todo",
-			description: strings.Repeat("a", MaxDescriptionLength+1),
+			description: strings.Repeat("a", models.MaxDescriptionLength+1),
 			expectError: true,
 			errorMsg:    "description exceeds maximum",
 		},
@@ -186,6 +233,93 @@ func TestFormModel_ValidateSubmission(t *testing.T) {
 	}
 }
 
+func TestFormModel_TrimsTitle(t *testing.T) {
+	mockStore := &mockStorage{}
+	model := NewFormModel(mockStore)
+	model.fields[titleField] = "  hi there  "
+	model.fields[descriptionField] = " Test Description\t"
+
+	if err := model.submitForm(); err != nil {
+		t.Fatalf("submitForm() error = %v", err)
+	}
+	if len(mockStore.saved) != 1 {
+		t.Fatalf("submitForm() saved %d todos, want 1", len(mockStore.saved))
+	}
+	saved := mockStore.saved[0]
+	if saved.Title != "hi there" || saved.Description != "Test Description" {
+		t.Errorf("submitForm() saved %q / %q, want %q / %q", saved.Title, saved.Description, "hi there", "Test Description")
+	}
+}
+
+func TestFormModel_EditKeepsUnchangedDeadline(t *testing.T) {
+	zone := time.FixedZone("UTC+9", 9*60*60)
+	deadline := time.Date(2025, 11, 20, 14, 0, 30, 0, zone)
+
+	tests := []struct {
+		name     string
+		deadline string
+		want     time.Time
+	}{
+		{name: "unchanged", want: deadline},
+		{name: "edited", deadline: "2025-11-21 09:00", want: time.Date(2025, 11, 21, 9, 0, 0, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := &mockStorage{}
+			todo := &models.Todo{ID: "1", Title: "Call bank", Description: "Card", Deadline: &deadline}
+			model := NewEditFormModel(mockStore, todo, nil)
+			if tt.deadline != "" {
+				model.fields[deadlineField] = tt.deadline
+			}
+
+			if err := model.submitForm(); err != nil {
+				t.Fatalf("submitForm() error = %v", err)
+			}
+			if len(mockStore.updated) != 1 {
+				t.Fatalf("submitForm() updated %d todos, want 1", len(mockStore.updated))
+			}
+			got := mockStore.updated[0].Deadline
+			if got == nil || !got.Equal(tt.want) || got.Location().String() != tt.want.Location().String() {
+				t.Errorf("submitForm() deadline = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormModel_RequireDeadline(t *testing.T) {
+	tests := []struct {
+		name      string
+		deadline  string
+		strict    bool
+		wantError error
+	}{
+		{name: "permissive without deadline", strict: false},
+		{name: "strict without deadline", strict: true, wantError: ErrDeadlineRequired},
+		{name: "strict with deadline", deadline: "2h", strict: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := &mockStorage{}
+			model := NewFormModel(mockStore)
+			model.SetRequireDeadline(tt.strict)
+			model.fields[titleField] = "Test Todo"
+			model.fields[descriptionField] = "Test Description"
+			model.fields[deadlineField] = tt.deadline
+
+			err := model.submitForm()
+			if !errors.Is(err, tt.wantError) {
+				t.Errorf("submitForm() error = %v, want %v", err, tt.wantError)
+			}
+			wantSaved := tt.wantError == nil
+			if saved := len(mockStore.saved) == 1; saved != wantSaved {
+				t.Errorf("submitForm() saved = %v, want %v", saved, wantSaved)
+			}
+		})
+	}
+}
+
 func TestFormModel_CharacterCountDisplay(t *testing.T) {
 	mockStore := &mockStorage{}
 	model := NewFormModel(mockStore)
@@ -206,3 +340,112 @@ func TestFormModel_CharacterCountDisplay(t *testing.T) {
 		t.Errorf("Expected to see description character count '%s' in view", expectedDescCount)
 	}
 }
+
+func TestFormModel_CharacterLimitWarning(t *testing.T) {
+	model := NewFormModel(&mockStorage{})
+
+	model.fields[titleField] = strings.Repeat("a", models.MaxTitleLength-1)
+	model.fields[descriptionField] = strings.Repeat("é", models.MaxDescriptionLength-1)
+	if view := model.View(); strings.Contains(view, "Max reached") {
+		t.Error("View should not warn below the limits")
+	}
+
+	model.fields[titleField] += "a"
+	view := model.View()
+	if !strings.Contains(view, fmt.Sprintf("Max reached: %d characters", models.MaxTitleLength)) {
+		t.Error("View should warn when the title is at its limit")
+	}
+	if strings.Contains(view, fmt.Sprintf("Max reached: %d characters", models.MaxDescriptionLength)) {
+		t.Error("View should not warn about the description below its limit")
+	}
+
+	model.fields[descriptionField] += "é"
+	if !strings.Contains(model.View(), fmt.Sprintf("Max reached: %d characters", models.MaxDescriptionLength)) {
+		t.Error("View should warn when the description is at its limit")
+	}
+}
+
+func TestFormModel_QuitWhileDirty(t *testing.T) {
+	mockStore := &mockStorage{}
+	model := NewFormModel(mockStore)
+
+	if model.Dirty() {
+		t.Fatal("New form should not be dirty")
+	}
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	model = updated.(*FormModel)
+
+	if !model.Dirty() {
+		t.Error("Expected form to be dirty after typing")
+	}
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(*FormModel)
+
+	if cmd != nil {
+		t.Error("Expected quitting a dirty form not to return tea.Quit")
+	}
+	if !model.confirmQuit {
+		t.Error("Expected quit confirmation prompt to be shown")
+	}
+	if !strings.Contains(model.View(), "Unsaved changes") {
+		t.Error("Expected view to contain the unsaved changes prompt")
+	}
+
+	updated, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	model = updated.(*FormModel)
+
+	if cmd != nil || model.confirmQuit {
+		t.Error("Expected cancel to dismiss the prompt without quitting")
+	}
+	if model.fields[titleField] != "a" {
+		t.Errorf("Expected cancel to keep the field contents, got %q", model.fields[titleField])
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if cmd == nil {
+		t.Error("Expected discard to quit")
+	}
+}
+
+func TestFormModel_BackspaceMultibyte(t *testing.T) {
+	model := NewFormModel(&mockStorage{})
+
+	for _, r := range "日é" {
+		model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if got := model.fields[titleField]; got != "日é" {
+		t.Fatalf("Expected title %q after typing, got %q", "日é", got)
+	}
+	if !strings.Contains(model.View(), "(2/100)") {
+		t.Error("Expected the character count to count runes")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if got := model.fields[titleField]; got != "日" || !utf8.ValidString(got) {
+		t.Errorf("Expected title %q after one backspace, got %q", "日", got)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if got := model.fields[titleField]; got != "" {
+		t.Errorf("Expected empty title after backspacing everything, got %q", got)
+	}
+}
+
+func TestFormModel_LeftRightMultibyte(t *testing.T) {
+	model := NewFormModel(&mockStorage{})
+	for _, r := range "aé" {
+		model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'ü'}})
+	model.Update(tea.KeyMsg{Type: tea.KeyRight})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'!'}})
+
+	if got := model.fields[titleField]; got != "aüé!" {
+		t.Errorf("Expected title %q, got %q", "aüé!", got)
+	}
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type keyBinding struct {
+	keys        string
+	description string
+}
+
+type bindingGroup struct {
+	title    string
+	bindings []keyBinding
+}
+
+// listBindings are the list view key bindings shown in the help overlay
+var listBindings = []bindingGroup{
+	{"Navigation", []keyBinding{
+		{"↑/↓, j/k", "Move"},
+		{"b/f", "Previous/next page"},
+		{"Space", "Expand"},
+		{"Enter", "Show details"},
+		{"p", "Pick a todo for me"},
+	}},
+	{"Todos", []keyBinding{
+		{"n", "New todo"},
+		{"N", "Quick add"},
+		{"c", "Complete (with a note)/reopen"},
+		{"@", "Complete at an earlier time"},
+		{"w", "Waiting on someone"},
+		{"d", "Delete"},
+		{"+/-", "Reschedule"},
+		{"S", "Snooze until tomorrow morning"},
+		{"t", "Plan for today/unplan"},
+		{"#", "Add or remove tags"},
+		{"Y", "Copy to clipboard"},
+		{"o", "Open attachment"},
+		{"Tab", "Next attachment"},
+		{"s", "Next subtask"},
+		{"P", "Promote subtask to a todo"},
+		{"u", "Undo complete/reopen/reschedule/snooze"},
+		{".", "Repeat last complete/snooze/delete"},
+	}},
+	{"Bulk", []keyBinding{
+		{"x", "Mark"},
+		{"C", "Complete marked"},
+		{"D", "Delete marked"},
+		{"O", "Complete all overdue"},
+		{"Z", "Snooze all overdue to tomorrow"},
+	}},
+	{"Filter", []keyBinding{
+		{"1-9", "Toggle tag filter"},
+		{"0", "Clear tag and project filter"},
+		{"W/M", "Due this week/month"},
+		{"U", "Fold/unfold upcoming"},
+	}},
+	{"General", []keyBinding{
+		{"r", "Refresh"},
+		{"?", "Toggle help"},
+		{"q", "Quit"},
+	}},
+}
+
+// renderHelp renders the key bindings grouped by category as a dialog
+func renderHelp() string {
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#7C3AED")).
+		Padding(1, 2)
+
+	headingStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#7C3AED")).
+		Bold(true)
+
+	keyStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#F59E0B")).
+		Width(10)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280"))
+
+	var groups []string
+	for _, group := range listBindings {
+		var b strings.Builder
+		b.WriteString(headingStyle.Render(group.title))
+		for _, binding := range group.bindings {
+			b.WriteString("\n")
+			b.WriteString(keyStyle.Render(binding.keys))
+			b.WriteString(binding.description)
+		}
+		groups = append(groups, b.String())
+	}
+
+	content := strings.Join(groups, "\n\n") + "\n\n" + hintStyle.Render("Press any key to close")
+	return dialogStyle.Render(content)
+}
@@ -1,49 +1,203 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"math/rand"
+	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/akr411/doit/internal/models"
 	"github.com/akr411/doit/internal/storage"
+	"github.com/akr411/doit/internal/utils"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 const pageSize = 10
 
+// upcomingLimit is how many deadlines the upcoming section lists
+const upcomingLimit = 10
+
+// ListOptions configures the list view
+type ListOptions struct {
+	// FitMode controls how titles wider than the terminal are displayed
+	FitMode FitMode
+	// DeadlineMode controls whether deadlines show the date, the days left
+	// or both
+	DeadlineMode DeadlineMode
+	// Markdown renders expanded descriptions as basic markdown
+	Markdown bool
+	// ShowZone shows deadlines in the zone they were set in instead of local time
+	ShowZone bool
+	// Table lines up checkbox, title, tags and a right-aligned due in column
+	Table bool
+	// AutoExpand shows the selected todo expanded until the cursor moves
+	// away, on top of the todos expanded with Space
+	AutoExpand bool
+	// Clipboard receives copied todos, SystemClipboard when nil
+	Clipboard Clipboard
+	// Opener opens attachments, SystemOpener when nil
+	Opener Opener
+	// FoldUpcoming starts with the upcoming deadlines folded into a count,
+	// which U opens and folds again
+	FoldUpcoming bool
+	// StaleAfter marks incomplete todos without a deadline that haven't been
+	// updated for this long as stale, 0 disables it
+	StaleAfter time.Duration
+	// StaleNudge reports the stale todos when the list opens
+	StaleNudge bool
+	// EmptyToForm opens the new todo form instead of the empty list when
+	// the first load finds no todos
+	EmptyToForm bool
+	// RequireDeadline rejects new and edited todos without a deadline
+	RequireDeadline bool
+	// WarnDays colors todos with at most this many days left orange, 0 uses
+	// DefaultWarnDays
+	WarnDays int
+	// Timer logs how long rendering takes, nil disables it
+	Timer *utils.Timer
+	// CompletedFirst lists the completed todos above the incomplete ones, for
+	// reviewing what was done
+	CompletedFirst bool
+	// Focus shows only the todos of this project until the filter is cleared
+	Focus string
+	// SoonDays colors todos with fewer than this many days left red, 0 uses
+	// DefaultSoonDays
+	SoonDays int
+	// MorningHour is the hour S snoozes todos to tomorrow, 0 uses
+	// utils.DefaultMorningHour
+	MorningHour int
+	// Day shows only the incomplete todos due on this local calendar day
+	// until the filter is cleared, the zero time shows every day
+	Day time.Time
+	// WeekStart is the day the week of the weekly goal starts on
+	WeekStart time.Weekday
+	// ConfirmOver is how many marked todos D deletes with a single y;
+	// more have to be confirmed by typing their count. 0 uses
+	// DefaultConfirmThreshold.
+	ConfirmOver int
+	// Paged holds only one page of the incomplete todos with deadlines,
+	// closest first, and fetches other pages from the storage as they are
+	// shown instead of loading every todo. Filters and tag counts cover the
+	// loaded page. Ignored for storages that can't page.
+	Paged bool
+}
+
 // ListModel represents the list view model
 type ListModel struct {
 	storage          storage.Storage
 	todos            []*models.Todo
-	topUpcoming      []*models.Todo
+	withDeadline     []*models.Todo
 	todosNoDeadline  []*models.Todo
+	completedTodos   []*models.Todo
 	streak           *storage.Streak
 	cursor           int
 	expanded         map[int]bool
 	currentPage      int
+	pageTotal        int
 	showHelp         bool
 	err              error
 	loading          bool
+	loaded           bool
 	confirmingDelete bool
 	todoToDelete     *models.Todo
+	bulkToDelete     []*models.Todo
+	confirmInput     string
+	marked           map[string]bool
+	toast            toast
+	banner           string
+	loadWarning      string
+	stale            map[string]bool
+	upcomingFolded   bool
+	quietStart       string
+	quietEnd         string
+	dateFormat       utils.FormatOpts
+	capturing        bool
+	nudging          bool
+	nudgeTodo        *models.Todo
+	nudgeDeadline    time.Time
+	nudgeFrom        time.Time
+	lastAction       repeatAction
+	backdating       bool
+	backdateTodo     *models.Todo
+	backdateInput    string
+	noting           bool
+	tagging          bool
+	tagInput         string
+	noteTodo         *models.Todo
+	noteInput        string
+	overdueBatch     []*models.Todo
+	snoozeBatch      []*models.Todo
+	undoStack        []undoEntry
+	rng              *rand.Rand
+	attachmentCursor int
+	subtaskCursor    int
+	captureInput     string
+	tagCounts        map[string]int
+	tagFilters       map[string]bool
+	projectFilter    string
+	periodFilter     utils.Period
+	dayFilter        time.Time
+	options          ListOptions
+	width            int
+	height           int
 }
 
 type dataLoadedMsg struct {
 	todos  []*models.Todo
 	streak *storage.Streak
+	// warning is set when only some of the todos could be read
+	warning error
+	// external is set when another process wrote to the storage since the
+	// last load
+	external bool
+	// total is how many todos a paged list pages through
+	total int
 }
 
 type errMsg struct{ error }
 
 // NewListModel creates a new list model
-func NewListModel(storage storage.Storage) *ListModel {
+func NewListModel(store storage.Storage, options ListOptions) *ListModel {
 	m := &ListModel{
-		storage:          storage,
+		storage:          store,
+		options:          options,
 		expanded:         make(map[int]bool),
+		marked:           make(map[string]bool),
+		tagFilters:       make(map[string]bool),
 		loading:          true,
 		confirmingDelete: false,
 		todoToDelete:     nil,
+		dateFormat:       utils.FormatOptsFromEnv(),
+		projectFilter:    options.Focus,
+		dayFilter:        options.Day,
+		upcomingFolded:   options.FoldUpcoming,
+		rng:              rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	m.quietStart, m.quietEnd = utils.QuietHoursFromEnv()
+	if m.options.Clipboard == nil {
+		m.options.Clipboard = SystemClipboard{}
+	}
+	if m.options.Opener == nil {
+		m.options.Opener = SystemOpener{}
+	}
+	if m.options.WarnDays == 0 {
+		m.options.WarnDays = DefaultWarnDays
+	}
+	if m.options.SoonDays == 0 {
+		m.options.SoonDays = DefaultSoonDays
+	}
+	if m.options.MorningHour == 0 {
+		m.options.MorningHour = utils.DefaultMorningHour
+	}
+	if m.options.ConfirmOver == 0 {
+		m.options.ConfirmOver = DefaultConfirmThreshold
+	}
+	if _, ok := store.(storage.Pager); !ok {
+		m.options.Paged = false
 	}
 	return m
 }
@@ -54,11 +208,28 @@ func (m *ListModel) Init() tea.Cmd {
 }
 
 func (m *ListModel) loadData() tea.Msg {
-	todos, err := m.storage.GetAllTodos()
-	if err != nil {
+	var todos []*models.Todo
+	var total int
+	var err error
+	if m.options.Paged {
+		todos, total, err = m.storage.(storage.Pager).GetTodosPage(m.currentPage*pageSize, pageSize, storage.SortDeadline)
+	} else {
+		todos, err = m.storage.GetAllTodos()
+	}
+	var warning error
+	if errors.Is(err, storage.ErrUnreadableTodo) {
+		// Show the todos that could be read rather than only the error
+		warning = err
+	} else if err != nil {
 		return errMsg{err}
 	}
 
+	external := false
+	if versioned, ok := m.storage.(storage.Versioned); ok {
+		// Failing to check only loses the indicator, the todos are loaded
+		external, _ = versioned.ChangedExternally()
+	}
+
 	streak, err := m.storage.GetStreak()
 	if err != nil {
 		streak = &storage.Streak{
@@ -70,21 +241,68 @@ func (m *ListModel) loadData() tea.Msg {
 	}
 
 	return dataLoadedMsg{
-		todos:  todos,
-		streak: streak,
+		todos:    todos,
+		streak:   streak,
+		warning:  warning,
+		external: external,
+		total:    total,
 	}
 }
 
+// Update handles a message and schedules clearing any toast it showed
 func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	if expire := m.toast.expireCmd(); expire != nil {
+		cmd = tea.Batch(cmd, expire)
+	}
+	return model, cmd
+}
+
+func (m *ListModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case toastExpiredMsg:
+		m.toast.expire(msg)
+		return m, nil
+
 	case dataLoadedMsg:
+		firstLoad := !m.loaded
 		m.loading = false
+		m.loaded = true
+		if m.options.Paged {
+			m.pageTotal = msg.total
+			if len(msg.todos) == 0 && m.currentPage > 0 {
+				// The page emptied, such as by completing its last todo
+				m.currentPage = max(m.pageCount()-1, 0)
+				m.cursor = 0
+				return m, m.loadData
+			}
+		}
+		if firstLoad && m.options.EmptyToForm && len(msg.todos) == 0 {
+			return m.newForm(), nil
+		}
+		if !firstLoad && msg.external {
+			m.toast.show(toastInfo, "↻ Updated externally")
+		}
+		if m.streak != nil && msg.streak.CurrentStreak > m.streak.CurrentStreak {
+			m.toast.show(toastSuccess, fmt.Sprintf("Streak +1 (%d days)", msg.streak.CurrentStreak))
+		}
 		m.todos = msg.todos
 		m.streak = msg.streak
+		m.loadWarning = ""
+		if msg.warning != nil {
+			m.loadWarning = msg.warning.Error()
+		}
 
-		m.topUpcoming = storage.GetTopUpcomingTodos(m.todos, 10)
+		m.withDeadline, m.todosNoDeadline, m.completedTodos = storage.PartitionTodos(m.todos, len(m.todos))
+		m.banner = m.dueSoonBanner(time.Now())
+		m.markStale(firstLoad && m.options.StaleNudge)
 
-		m.todosNoDeadline = storage.GetTodosWithoutDeadline(m.todos)
+		m.tagCounts = storage.TagCounts(m.todos)
+		for tag := range m.tagFilters {
+			if m.tagCounts[tag] == 0 {
+				delete(m.tagFilters, tag)
+			}
+		}
 		return m, nil
 
 	case errMsg:
@@ -92,7 +310,45 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		return m, nil
 
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.capturing {
+			return m.handleCapture(msg)
+		}
+		if m.nudging {
+			return m.handleNudge(msg)
+		}
+		if m.backdating {
+			return m.handleBackdate(msg)
+		}
+		if m.noting {
+			return m.handleNote(msg)
+		}
+		if m.tagging {
+			return m.handleTagEdit(msg)
+		}
+		if len(m.overdueBatch) > 0 {
+			return m.handleCompleteOverdue(msg)
+		}
+		if len(m.snoozeBatch) > 0 {
+			return m.handleSnoozeOverdue(msg)
+		}
+		if m.needsTypedConfirm() {
+			return m.handleTypedConfirm(msg)
+		}
+		if m.showHelp && msg.String() != "ctrl+c" {
+			// Any key closes the help overlay
+			m.showHelp = false
+			return m, nil
+		}
+
+		// A key press dismisses the toast early
+		m.toast.text = ""
+
 		switch msg.String() {
 		case "q", "ctrl+c", "esc":
 			return m, tea.Quit
@@ -100,24 +356,42 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "up", "k":
 			if m.cursor > 0 {
 				m.cursor--
+				m.attachmentCursor = 0
+				m.subtaskCursor = 0
 				m.ensureCursorVisible()
 			}
 
 		case "down", "j":
 			if m.cursor < len(m.getVisibleTodos())-1 {
 				m.cursor++
+				m.attachmentCursor = 0
+				m.subtaskCursor = 0
 				m.ensureCursorVisible()
 			}
 
-		case "Space":
+		case " ":
 			m.expanded[m.cursor] = !m.expanded[m.cursor]
 
+		case "enter":
+			if todo := m.getCurrentTodo(); todo != nil && !m.confirmingDelete {
+				return NewDetailModel(m.storage, todo, m), nil
+			}
+
 		case "c":
 			if err := m.toggleComplete(); err != nil {
-				m.err = err
+				m.toast.show(toastError, err.Error())
 			}
 			return m, m.loadData
 
+		case "@":
+			m.startBackdate()
+			return m, nil
+
+		case "w":
+			m.toggleWaiting()
+			m.banner = m.dueSoonBanner(time.Now())
+			return m, nil
+
 		case "d":
 			if !m.confirmingDelete {
 				todo := m.getCurrentTodo()
@@ -128,18 +402,113 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "x":
+			if todo := m.getCurrentTodo(); todo != nil {
+				if m.marked[todo.ID] {
+					delete(m.marked, todo.ID)
+				} else {
+					m.marked[todo.ID] = true
+				}
+			}
+
+		case "C":
+			if marked := m.markedTodos(); len(marked) > 0 {
+				m.showBulkResult(m.bulkComplete(marked))
+				m.marked = make(map[string]bool)
+				return m, m.loadData
+			}
+
+		case "O":
+			m.startCompleteOverdue()
+			return m, nil
+
+		case "Z":
+			m.startSnoozeOverdue()
+			return m, nil
+
+		case "u":
+			m.undo()
+			return m, m.loadData
+
+		case "S":
+			m.snoozeTomorrow()
+			return m, m.loadData
+
+		case "t":
+			m.togglePlannedToday()
+			return m, m.loadData
+
+		case "#":
+			m.startTagEdit()
+			return m, nil
+
+		case "D":
+			if marked := m.markedTodos(); len(marked) > 0 && !m.confirmingDelete {
+				m.confirmingDelete = true
+				m.bulkToDelete = marked
+			}
+			return m, nil
+
 		case "n":
 			if m.confirmingDelete {
 				m.confirmingDelete = false
 				m.todoToDelete = nil
+				m.bulkToDelete = nil
 				return m, nil
 			}
-			return NewFormModel(m.storage), nil
+			return m.newForm(), nil
+
+		case "Y":
+			m.toast.show(toastInfo, m.copyCurrentTodo())
+			return m, nil
+
+		case "tab":
+			if todo := m.getCurrentTodo(); todo != nil && len(todo.Attachments) > 0 {
+				m.attachmentCursor = (m.attachmentCursor + 1) % len(todo.Attachments)
+			}
+			return m, nil
+
+		case "s":
+			if todo := m.getCurrentTodo(); todo != nil && m.isExpanded(m.cursor) && len(todo.Subtasks) > 0 {
+				m.subtaskCursor = (m.subtaskCursor + 1) % len(todo.Subtasks)
+			}
+			return m, nil
+
+		case "P":
+			m.promoteSubtask()
+			return m, m.loadData
+
+		case "o":
+			m.toast.show(toastInfo, m.openAttachment())
+			return m, nil
+
+		case "p":
+			m.pickRandom()
+			return m, nil
+
+		case "+":
+			m.startNudge(nudgeStep)
+			return m, nil
+
+		case "-":
+			m.startNudge(-nudgeStep)
+			return m, nil
+
+		case "N":
+			m.capturing = true
+			m.captureInput = ""
+			return m, nil
 
 		case "y":
+			if m.confirmingDelete && len(m.bulkToDelete) > 0 {
+				return m, m.confirmBulkDelete()
+			}
 			if m.confirmingDelete && m.todoToDelete != nil {
 				if err := m.storage.DeleteTodo(m.todoToDelete.ID); err != nil {
-					m.err = err
+					m.toast.show(toastError, err.Error())
+				} else {
+					m.toast.show(toastSuccess, "Deleted")
+					m.lastAction = repeatAction{kind: repeatDelete}
 				}
 				m.confirmingDelete = false
 				m.todoToDelete = nil
@@ -147,25 +516,106 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case ".":
+			return m, m.repeatLast()
+
 		case "r":
 			m.loading = true
 			return m, m.loadData
 
 		case "?", "h":
-			m.showHelp = !m.showHelp
+			m.showHelp = true
 
 		case "pgup", "b":
 			if m.currentPage > 0 {
 				m.currentPage--
 				m.cursor = 0
+				if m.options.Paged {
+					return m, m.loadData
+				}
 			}
 
 		case "pgdown", "f":
-			visibleTodos := m.getVisibleTodos()
-			if (m.currentPage+1)*pageSize < len(visibleTodos) {
+			if m.currentPage+1 < m.pageCount() {
 				m.currentPage++
 				m.cursor = 0
+				if m.options.Paged {
+					return m, m.loadData
+				}
 			}
+
+		case "U":
+			anchor := m.selectedID()
+			m.upcomingFolded = !m.upcomingFolded
+			m.anchorCursor(anchor)
+			m.expanded = make(map[int]bool)
+
+		case "W":
+			m.togglePeriodFilter(utils.PeriodWeek)
+
+		case "M":
+			m.togglePeriodFilter(utils.PeriodMonth)
+
+		case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			m.selectTagFilter(msg.String())
+		}
+	}
+
+	return m, nil
+}
+
+// newForm creates the new todo form with the list's options
+func (m *ListModel) newForm() *FormModel {
+	form := NewFormModel(m.storage)
+	form.SetRequireDeadline(m.options.RequireDeadline)
+	return form
+}
+
+// handleCapture handles input for the quick-capture title prompt
+func (m *ListModel) handleCapture(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		m.capturing = false
+		m.captureInput = ""
+
+	case tea.KeyEnter:
+		title := strings.TrimSpace(m.captureInput)
+		m.capturing = false
+		m.captureInput = ""
+		if title == "" {
+			return m, nil
+		}
+		if m.options.RequireDeadline {
+			m.toast.show(toastError, ErrDeadlineRequired.Error())
+			return m, nil
+		}
+
+		now := time.Now()
+		todo := models.Todo{
+			ID:        models.NewID(),
+			Title:     title,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := m.storage.SaveTodo(&todo); err != nil {
+			m.toast.show(toastError, err.Error())
+			return m, nil
+		}
+		m.toast.show(toastSuccess, "Saved")
+		return m, m.loadData
+
+	case tea.KeyBackspace:
+		if input := []rune(m.captureInput); len(input) > 0 {
+			m.captureInput = string(input[:len(input)-1])
+		}
+
+	case tea.KeySpace, tea.KeyRunes:
+		input := m.captureInput + msg.String()
+		if utf8.RuneCountInString(input) <= models.MaxTitleLength {
+			m.captureInput = input
 		}
 	}
 
@@ -174,6 +624,12 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the list
 func (m *ListModel) View() string {
+	defer m.options.Timer.Stage("render")()
+
+	if tooSmall := tooSmallView(m.width, m.height); tooSmall != "" {
+		return tooSmall
+	}
+
 	if m.loading {
 		return lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#9333EA")).
@@ -233,22 +689,52 @@ func (m *ListModel) View() string {
 
 	var s strings.Builder
 
-	s.WriteString(titleStyle.Render(" Todo List"))
+	if m.projectFilter != "" {
+		s.WriteString(titleStyle.Render(" Todo List — " + m.projectFilter))
+	} else {
+		s.WriteString(titleStyle.Render(" Todo List"))
+	}
+
+	if m.streak != nil && (m.streak.CurrentStreak > 0 || m.streak.WeeklyGoal > 0) {
+		var parts []string
+		if m.streak.CurrentStreak > 0 {
+			var week []int
+			for _, day := range storage.WeekTrend(m.streak, time.Now()) {
+				week = append(week, day.Count)
+			}
+			parts = append(parts, fmt.Sprintf(" Streak: %d days | Max: %d days | Total: %d completed | Week: %s",
+				m.streak.CurrentStreak, m.streak.MaxStreak, m.streak.TotalCompleted, utils.Sparkline(week)))
+		}
+		if done, goal := storage.WeeklyProgress(m.streak, time.Now(), m.options.WeekStart); goal > 0 {
+			parts = append(parts, fmt.Sprintf("Goal: %d/%d this week", done, goal))
+		}
+		s.WriteString(streakStyle.Render(strings.Join(parts, " | ")))
+		s.WriteString("\n")
+	}
 
-	if m.streak != nil && m.streak.CurrentStreak > 0 {
-		streakText := fmt.Sprintf(" Streak: %d days | Max: %d days | Total: %d completed",
-			m.streak.CurrentStreak, m.streak.MaxStreak, m.streak.TotalCompleted)
-		s.WriteString(streakStyle.Render(streakText))
+	if legend := m.renderTagLegend(selectedStyle, helpStyle); legend != "" {
+		s.WriteString(legend)
 		s.WriteString("\n")
 	}
 
-	if len(m.topUpcoming) > 0 {
-		s.WriteString(sectionStyle.Render(" Upcoming Deadlines (Top 10)"))
+	if m.loadWarning != "" {
+		s.WriteString(overdueStyle.Render(" ⚠ " + m.loadWarning))
 		s.WriteString("\n")
 	}
 
+	if m.banner != "" {
+		s.WriteString(upcomingStyle.Render(" " + m.banner))
+		s.WriteString("\n")
+	}
+
+	planned, upcoming, noDeadline, completed := m.sections()
+
 	visibleTodos := m.getVisibleTodos()
 	start := m.currentPage * pageSize
+	if m.options.Paged {
+		// Only the current page is loaded
+		start = 0
+	}
 	end := start + pageSize
 	if end > len(visibleTodos) {
 		end = len(visibleTodos)
@@ -256,39 +742,82 @@ func (m *ListModel) View() string {
 
 	currentIndex := 0
 
-	// Render top upcoming todos
-	for _, todo := range m.topUpcoming {
-		if currentIndex >= start && currentIndex < end {
-			s.WriteString(m.renderTodo(todo, currentIndex, currentIndex == m.cursor,
-				selectedStyle, normalStyle, completeStyle, overdueStyle, upcomingStyle, descriptionStyle))
+	// Todos planned for today, whatever their deadline
+	renderPlanned := func() {
+		if len(planned) > 0 {
+			if currentIndex > 0 {
+				s.WriteString("\n")
+			}
+			s.WriteString(sectionStyle.Render(" Planned for Today"))
 			s.WriteString("\n")
 		}
-		currentIndex++
+
+		for _, todo := range planned {
+			if currentIndex >= start && currentIndex < end {
+				s.WriteString(m.renderTodo(todo, currentIndex, currentIndex == m.cursor,
+					selectedStyle, normalStyle, completeStyle, overdueStyle, upcomingStyle, descriptionStyle))
+				s.WriteString("\n")
+			}
+			currentIndex++
+		}
 	}
 
-	// Todos without deadline section
-	if len(m.todosNoDeadline) > 0 {
-		if currentIndex > 0 {
+	// Render top upcoming todos
+	renderUpcoming := func() {
+		due := m.dueFilterLabel()
+		if (due != "" || len(upcoming) > 0) && currentIndex > 0 {
 			s.WriteString("\n")
 		}
-		s.WriteString(sectionStyle.Render(" No Deadline"))
-		s.WriteString("\n")
+		if due != "" {
+			s.WriteString(sectionStyle.Render(" Due " + due))
+			s.WriteString("\n")
+			if len(upcoming) == 0 {
+				s.WriteString(helpStyle.Render(" Nothing due " + due))
+				s.WriteString("\n")
+			}
+		} else if len(upcoming) > 0 && m.foldsUpcoming() {
+			s.WriteString(sectionStyle.Render(fmt.Sprintf(" %d upcoming — press U to expand", len(upcoming))))
+			s.WriteString("\n")
+			return
+		} else if len(upcoming) > 0 {
+			s.WriteString(sectionStyle.Render(" Upcoming Deadlines (Top 10)"))
+			s.WriteString("\n")
+		}
+
+		for _, todo := range upcoming {
+			if currentIndex >= start && currentIndex < end {
+				s.WriteString(m.renderTodo(todo, currentIndex, currentIndex == m.cursor,
+					selectedStyle, normalStyle, completeStyle, overdueStyle, upcomingStyle, descriptionStyle))
+				s.WriteString("\n")
+			}
+			currentIndex++
+		}
 	}
 
-	for _, todo := range m.todosNoDeadline {
-		if currentIndex >= start && currentIndex < end {
-			s.WriteString(m.renderTodo(todo, currentIndex, currentIndex == m.cursor,
-				sectionStyle, normalStyle, completeStyle, overdueStyle, upcomingStyle, descriptionStyle))
+	// Todos without deadline section
+	renderNoDeadline := func() {
+		if len(noDeadline) > 0 {
+			if currentIndex > 0 {
+				s.WriteString("\n")
+			}
+			s.WriteString(sectionStyle.Render(" No Deadline"))
 			s.WriteString("\n")
 		}
-		currentIndex++
+
+		for _, todo := range noDeadline {
+			if currentIndex >= start && currentIndex < end {
+				s.WriteString(m.renderTodo(todo, currentIndex, currentIndex == m.cursor,
+					sectionStyle, normalStyle, completeStyle, overdueStyle, upcomingStyle, descriptionStyle))
+				s.WriteString("\n")
+			}
+			currentIndex++
+		}
 	}
 
-	// Completed todos section
-	completedCount := 0
-	for _, todo := range m.todos {
-		if todo.Completed {
-			if completedCount == 0 && currentIndex > 0 {
+	// Completed todos section, headed even when it comes first
+	renderCompleted := func() {
+		for i, todo := range completed {
+			if i == 0 && (currentIndex > 0 || m.options.CompletedFirst) {
 				s.WriteString("\n")
 				s.WriteString(sectionStyle.Render("🗹 Completed"))
 				s.WriteString("\n")
@@ -299,25 +828,78 @@ func (m *ListModel) View() string {
 				s.WriteString("\n")
 			}
 			currentIndex++
-			completedCount++
 		}
 	}
 
-	if len(visibleTodos) > pageSize {
-		pageInfo := fmt.Sprintf("\n Page %d/%d", m.currentPage+1, (len(visibleTodos)+pageSize-1)/pageSize)
+	if m.options.CompletedFirst {
+		renderCompleted()
+		renderPlanned()
+		renderUpcoming()
+		renderNoDeadline()
+	} else {
+		renderPlanned()
+		renderUpcoming()
+		renderNoDeadline()
+		renderCompleted()
+	}
+
+	if pages := m.pageCount(); pages > 1 {
+		pageInfo := fmt.Sprintf("\n Page %d/%d", m.currentPage+1, pages)
 		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF")).Render(pageInfo))
 	}
 
-	if m.showHelp {
+	if toast := m.toast.View(); toast != "" {
+		s.WriteString("\n")
+		s.WriteString(toast)
+		s.WriteString("\n")
+	}
+
+	if m.capturing {
+		s.WriteString("\n")
+		s.WriteString(sectionStyle.Render(fmt.Sprintf(" Quick add (%d/%d): ", utf8.RuneCountInString(m.captureInput), models.MaxTitleLength)))
+		s.WriteString(m.captureInput + "█")
+		s.WriteString("\n")
+		s.WriteString(helpStyle.Render("Enter: Save • Esc: Cancel"))
+	} else if m.nudging {
+		s.WriteString("\n")
+		s.WriteString(sectionStyle.Render(" New deadline: "))
+		s.WriteString(m.formatDeadline(m.nudgeDeadline))
+		s.WriteString("\n")
+		s.WriteString(helpStyle.Render("↑/↓: ±1 day • Shift+↑/↓: ±1 hour • Enter: Save • Esc: Cancel"))
+	} else if m.backdating {
+		s.WriteString("\n")
+		s.WriteString(sectionStyle.Render(" Completed at: "))
+		s.WriteString(m.backdateInput + "█")
+		s.WriteString("\n")
+		s.WriteString(helpStyle.Render("e.g. 2025-11-15 20:00 or today 9am • Enter: Save • Esc: Cancel"))
+	} else if m.noting {
 		s.WriteString("\n")
-		s.WriteString(helpStyle.Render("Commands:\n"))
-		s.WriteString(helpStyle.Render("↑/↓/j/k: Navigate • Space: Expand • c: Complete • d: Delete • n: New • r: Refresh • q: Quit"))
+		s.WriteString(sectionStyle.Render(" Note (optional): "))
+		s.WriteString(m.noteInput + "█")
+		s.WriteString("\n")
+		s.WriteString(helpStyle.Render("What was done or the outcome • Enter: Save • Esc: Skip"))
+	} else if m.tagging {
+		s.WriteString("\n")
+		s.WriteString(sectionStyle.Render(" Tags: "))
+		s.WriteString(m.tagInput + "█")
+		s.WriteString("\n")
+		s.WriteString(helpStyle.Render("work, home to add • -work to remove • Enter: Save • Esc: Cancel"))
+	} else if len(m.overdueBatch) > 0 {
+		s.WriteString("\n")
+		s.WriteString(sectionStyle.Render(fmt.Sprintf(" Complete %d overdue todos? ", len(m.overdueBatch))))
+		s.WriteString("\n")
+		s.WriteString(helpStyle.Render("[y] Yes • any other key cancels"))
+	} else if len(m.snoozeBatch) > 0 {
+		s.WriteString("\n")
+		s.WriteString(sectionStyle.Render(fmt.Sprintf(" Snooze %d overdue todos until tomorrow morning? ", len(m.snoozeBatch))))
+		s.WriteString("\n")
+		s.WriteString(helpStyle.Render("[y] Yes • any other key cancels"))
 	} else {
 		s.WriteString("\n")
 		s.WriteString(helpStyle.Render("Press ? for help"))
 	}
 
-	if m.confirmingDelete && m.todoToDelete != nil {
+	if m.confirmingDelete && (m.todoToDelete != nil || len(m.bulkToDelete) > 0) {
 		dialogStyle := lipgloss.NewStyle().
 			Border(lipgloss.NormalBorder()).
 			BorderForeground(lipgloss.Color("#FF6B6B")).
@@ -336,46 +918,35 @@ func (m *ListModel) View() string {
 		var dialog strings.Builder
 		dialog.WriteString(warningStyle.Render("⚠  Delete Confirmation"))
 		dialog.WriteString("\n\n")
-		dialog.WriteString("Are you sure you want to delete this todo?\n\n")
-		dialog.WriteString(titleStyle.Render("Title: "))
-		dialog.WriteString(m.todoToDelete.Title)
-		dialog.WriteString("\n\n")
+		if m.needsTypedConfirm() {
+			dialog.WriteString(fmt.Sprintf("Are you sure you want to delete %d marked todos?\n\n", len(m.bulkToDelete)))
+			dialog.WriteString(fmt.Sprintf("Type %d or yes and press Enter: ", len(m.bulkToDelete)))
+			dialog.WriteString(m.confirmInput + "█")
+			dialog.WriteString("\n\n")
+			dialog.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF")).Render("[esc] Cancel"))
+
+			width, height := m.viewSize()
+			return placeOverlay(s.String(), dialogStyle.Render(dialog.String()), width, height)
+		}
+		if len(m.bulkToDelete) > 0 {
+			dialog.WriteString(fmt.Sprintf("Are you sure you want to delete %d marked todos?\n\n", len(m.bulkToDelete)))
+		} else {
+			dialog.WriteString("Are you sure you want to delete this todo?\n\n")
+			dialog.WriteString(titleStyle.Render("Title: "))
+			dialog.WriteString(m.todoToDelete.Title)
+			dialog.WriteString("\n\n")
+		}
 		dialog.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#4CAF50")).Render("[y] Yes  "))
 		dialog.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#FF6B6B")).Render("[n] No  "))
 		dialog.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF")).Render("[esc] Cancel"))
 
-		dialogContent := dialogStyle.Render(dialog.String())
-
-		width := lipgloss.Width(dialogContent)
-		height := lipgloss.Height(dialogContent)
-		viewWidth := 80
-		viewHeight := 24
-
-		leftPadding := (viewWidth - width) / 2
-		topPadding := (viewHeight - height) / 2
-
-		var finalView strings.Builder
-		lines := strings.Split(s.String(), "\n")
-
-		for i, line := range lines {
-			if i >= topPadding && i < topPadding+height {
-				relativeLineIndex := i - topPadding
-				dialogLines := strings.Split(dialogContent, "\n")
-				if relativeLineIndex < len(dialogLines) {
-					finalView.WriteString(strings.Repeat(" ", leftPadding))
-					finalView.WriteString(dialogLines[relativeLineIndex])
-				} else {
-					finalView.WriteString(line)
-				}
-			} else {
-				finalView.WriteString(line)
-			}
-			if i < len(lines)-1 {
-				finalView.WriteString("\n")
-			}
-		}
+		width, height := m.viewSize()
+		return placeOverlay(s.String(), dialogStyle.Render(dialog.String()), width, height)
+	}
 
-		return finalView.String()
+	if m.showHelp {
+		width, height := m.viewSize()
+		return placeOverlay(s.String(), renderHelp(), width, height)
 	}
 
 	return s.String()
@@ -390,65 +961,419 @@ func (m *ListModel) renderTodo(todo *models.Todo, index int, isSelected bool,
 	if todo.Completed {
 		checkbox = "[✔]"
 	}
+	if m.marked[todo.ID] {
+		checkbox += "*"
+	}
+
+	if todo.Waiting {
+		// Blocked on someone else, so the deadline is not urgent
+		overdueStyle, upcomingStyle = lipgloss.NewStyle(), lipgloss.NewStyle()
+	}
+
+	escalated := storage.OverdueTier(todo, time.Now()) == storage.OverdueVery
+	if escalated {
+		overdueStyle = overdueStyle.Foreground(lipgloss.Color("#FF1F1F")).Blink(true)
+	}
 
 	deadlineInfo := ""
 	if todo.Deadline != nil && !todo.Completed {
+		left := time.Until(*todo.Deadline)
 		days := todo.DaysUntilDeadline()
-		if days < 0 {
-			deadlineInfo = overdueStyle.Render(fmt.Sprintf(" (Overdue by %d days)", -days))
-		} else if days == 0 {
-			deadlineInfo = overdueStyle.Render(" (Due today!)")
-		} else if days <= 3 {
-			deadlineInfo = upcomingStyle.Render(fmt.Sprintf(" (%d days left)", days))
-		} else {
-			deadlineInfo = fmt.Sprintf(" (%s)", todo.Deadline.Format("Jan 2, 3:04 PM"))
+		if left < 0 && days == 0 {
+			// Passed earlier today, which is overdue rather than due today
+			days = -1
+		}
+		band := m.urgencyBand(days)
+		label := " " + FormatDeadlineLabel(m.formatDeadline(*todo.Deadline), days, left, band, m.options.DeadlineMode)
+		switch {
+		case escalated:
+			deadlineInfo = overdueStyle.Render(fmt.Sprintf(" ⚠ %d days overdue", -days))
+		case band == BandOverdue || band == BandSoon:
+			deadlineInfo = overdueStyle.Render(label)
+		case band == BandWarn:
+			deadlineInfo = upcomingStyle.Render(label)
+		default:
+			deadlineInfo = label
 		}
 	}
 
-	line := fmt.Sprintf("%s %s%s", checkbox, todo.Title, deadlineInfo)
+	progressInfo := subtaskLabel(todo) + waitingLabel(todo)
+	if m.stale[todo.ID] {
+		progressInfo += " 💤"
+	}
+	doneToday := completedToday(todo, time.Now())
+	if doneToday {
+		progressInfo += " ✨"
+	}
+
+	// Leave room for the checkbox, the labels and the row padding
+	titleWidth := 0
+	if m.width > 0 {
+		titleWidth = max(m.width-lipgloss.Width(checkbox)-lipgloss.Width(progressInfo)-lipgloss.Width(deadlineInfo)-3, 10)
+	}
+	titleLines := FitTitle(todo.Title, titleWidth, m.options.FitMode)
+
+	if todo.Recurrence != models.RecurNone {
+		progressInfo += " ↻"
+	}
+
+	var line string
+	if m.options.Table {
+		line = m.renderTableRow(todo, checkbox, progressInfo, overdueStyle, upcomingStyle)
+	} else {
+		line = fmt.Sprintf("%s %s%s%s", checkbox, titleLines[0], progressInfo, deadlineInfo)
+		indent := strings.Repeat(" ", lipgloss.Width(checkbox)+1)
+		for _, titleLine := range titleLines[1:] {
+			line += "\n" + indent + titleLine
+		}
+	}
 
 	if isSelected {
 		s.WriteString(selectedStyle.Render(line))
+	} else if doneToday {
+		s.WriteString(completedStyle.Foreground(lipgloss.Color("#4CAF50")).Render(line))
 	} else if todo.Completed {
 		s.WriteString(completedStyle.Render(line))
+	} else if todo.Waiting {
+		s.WriteString(normalStyle.Foreground(lipgloss.Color("#9CA3AF")).Render(line))
 	} else {
 		s.WriteString(normalStyle.Render(line))
 	}
 
-	if m.expanded[index] && todo.Description != "" {
+	if m.isExpanded(index) && todo.Description != "" {
+		description := todo.Description
+		if m.options.Markdown {
+			description = RenderMarkdown(description)
+		}
 		s.WriteString("\n")
-		s.WriteString(descriptionStyle.Render(todo.Description))
+		s.WriteString(descriptionStyle.Render(description))
+	}
+
+	if m.isExpanded(index) {
+		for i, subtask := range todo.Subtasks {
+			marker := "  "
+			if isSelected && i == m.subtaskCursor%len(todo.Subtasks) {
+				marker = "› "
+			}
+			mark := "☐"
+			if subtask.Done {
+				mark = "☑"
+			}
+			s.WriteString("\n")
+			s.WriteString(descriptionStyle.Render(marker + mark + " " + subtask.Title))
+		}
+	}
+
+	if m.isExpanded(index) {
+		for i, attachment := range todo.Attachments {
+			marker := "  "
+			if isSelected && i == m.attachmentCursor {
+				marker = "› "
+			}
+			s.WriteString("\n")
+			s.WriteString(descriptionStyle.Render(marker + "📎 " + attachment))
+		}
+	}
+
+	if m.isExpanded(index) && len(todo.Tags) > 0 {
+		s.WriteString("\n")
+		s.WriteString(descriptionStyle.Render("Tags: " + strings.Join(todo.Tags, ", ")))
 	}
 
 	return s.String()
 }
 
-func (m *ListModel) getVisibleTodos() []*models.Todo {
-	var visible []*models.Todo
+// renderTableRow renders a todo as a table row, with the subtask progress
+// kept inside the title column
+func (m *ListModel) renderTableRow(todo *models.Todo, checkbox, progressInfo string, overdueStyle, upcomingStyle lipgloss.Style) string {
+	cols := ComputeColumns(m.width)
+	titleLines := FitTitle(todo.Title, cols.Title-lipgloss.Width(progressInfo), m.options.FitMode)
+	titleLines[0] += progressInfo
+
+	due := dueInLabel(todo)
+	if due != "" {
+		switch m.urgencyBand(todo.DaysUntilDeadline()) {
+		case BandOverdue, BandSoon:
+			due = overdueStyle.Render(due)
+		case BandWarn:
+			due = upcomingStyle.Render(due)
+		}
+	}
+	return cols.Row(checkbox, titleLines, TagsLabel(todo.Tags, cols.Tags), due)
+}
+
+// isExpanded reports whether the todo at index shows its details, either
+// pinned open with Space or selected with AutoExpand on
+func (m *ListModel) isExpanded(index int) bool {
+	return m.expanded[index] || (m.options.AutoExpand && index == m.cursor)
+}
+
+// urgencyBand picks the band for days left with the list's thresholds
+func (m *ListModel) urgencyBand(days int) Band {
+	return UrgencyBand(days, m.options.WarnDays, m.options.SoonDays)
+}
+
+// sections returns the upcoming, no deadline and completed todos that pass
+// the active filters, in display order
+func (m *ListModel) sections() (planned, upcoming, noDeadline, completed []*models.Todo) {
+	if !m.dayFilter.IsZero() {
+		for _, todo := range storage.TodosOnDay(m.todos, m.dayFilter) {
+			if !todo.Completed && m.matchesFilter(todo) {
+				upcoming = append(upcoming, todo)
+			}
+		}
+		return nil, floatEscalated(upcoming, time.Now()), nil, nil
+	}
+
+	if m.periodFilter != utils.PeriodNone {
+		// Every incomplete todo due in the period, not just the top upcoming
+		now := time.Now()
+		for _, todo := range m.todos {
+			if !todo.Completed && todo.Deadline != nil &&
+				utils.InPeriod(*todo.Deadline, m.periodFilter, now) && m.matchesFilter(todo) {
+				upcoming = append(upcoming, todo)
+			}
+		}
+		return nil, floatEscalated(upcoming, now), nil, nil
+	}
+
+	// Todos planned for today are listed once, in their own section
+	isPlanned := make(map[string]bool)
+	for _, todo := range storage.PlannedToday(m.todos, time.Now()) {
+		if m.matchesFilter(todo) {
+			planned = append(planned, todo)
+			isPlanned[todo.ID] = true
+		}
+	}
+	planned = floatEscalated(planned, time.Now())
 
-	visible = append(visible, m.topUpcoming...)
+	// Filtered before taking the closest, so sooner todos that don't match
+	// can't push matching ones out
+	for _, todo := range m.withDeadline {
+		if len(upcoming) == upcomingLimit {
+			break
+		}
+		if m.matchesFilter(todo) && !isPlanned[todo.ID] {
+			upcoming = append(upcoming, todo)
+		}
+	}
+	upcoming = floatEscalated(upcoming, time.Now())
 
-	visible = append(visible, m.todosNoDeadline...)
+	for _, todo := range m.todosNoDeadline {
+		if m.matchesFilter(todo) && !isPlanned[todo.ID] {
+			noDeadline = append(noDeadline, todo)
+		}
+	}
 
-	for _, todo := range m.todos {
-		if todo.Completed {
-			visible = append(visible, todo)
+	for _, todo := range m.completedTodos {
+		if m.matchesFilter(todo) {
+			completed = append(completed, todo)
 		}
 	}
 
+	return planned, upcoming, noDeadline, completed
+}
+
+// foldsUpcoming reports whether the upcoming deadlines are shown as a count
+// only. The day and period filters always list their todos.
+func (m *ListModel) foldsUpcoming() bool {
+	return m.upcomingFolded && m.dayFilter.IsZero() && m.periodFilter == utils.PeriodNone
+}
+
+// floatEscalated moves very overdue todos to the front, keeping the order
+// within both groups. Waiting todos never escalate, so this lifts very
+// overdue todos past waiting ones with older deadlines, and past todos
+// without a deadline in the planned section.
+func floatEscalated(todos []*models.Todo, now time.Time) []*models.Todo {
+	floated := make([]*models.Todo, 0, len(todos))
+	var rest []*models.Todo
+	for _, todo := range todos {
+		if storage.OverdueTier(todo, now) == storage.OverdueVery {
+			floated = append(floated, todo)
+		} else {
+			rest = append(rest, todo)
+		}
+	}
+	return append(floated, rest...)
+}
+
+func (m *ListModel) matchesFilter(todo *models.Todo) bool {
+	if !m.matchesTagFilters(todo) {
+		return false
+	}
+	if m.projectFilter != "" && !strings.EqualFold(todo.Project, m.projectFilter) {
+		return false
+	}
+	return true
+}
+
+// formatDeadline formats a deadline in local time, or in its original zone
+// when ShowZone is set
+func (m *ListModel) formatDeadline(deadline time.Time) string {
+	if m.options.ShowZone {
+		return utils.FormatTime(deadline, m.dateFormat) + " " + deadline.Format("MST")
+	}
+	return utils.FormatTime(deadline.Local(), m.dateFormat)
+}
+
+// waitingLabel returns the " ⏳" marker for todos waiting on someone, followed
+// by who when known, or "" for other todos
+func waitingLabel(todo *models.Todo) string {
+	switch {
+	case !todo.Waiting || todo.Completed:
+		return ""
+	case todo.WaitingOn != "":
+		return " ⏳ " + todo.WaitingOn
+	default:
+		return " ⏳"
+	}
+}
+
+// toggleWaiting marks the selected todo as waiting on someone, or clears it
+func (m *ListModel) toggleWaiting() {
+	todo := m.getCurrentTodo()
+	if todo == nil || todo.Completed {
+		return
+	}
+
+	todo.Waiting = !todo.Waiting
+	if err := m.storage.UpdateTodo(todo); err != nil {
+		todo.Waiting = !todo.Waiting
+		m.toast.show(toastError, err.Error())
+		return
+	}
+	if todo.Waiting {
+		m.toast.show(toastInfo, "Waiting on someone else")
+	} else {
+		m.toast.show(toastInfo, "No longer waiting")
+	}
+}
+
+// completedToday checks if the todo was completed on the same local calendar
+// day as now
+func completedToday(todo *models.Todo, now time.Time) bool {
+	if !todo.Completed || todo.CompletedAt == nil {
+		return false
+	}
+	y, m, d := todo.CompletedAt.Local().Date()
+	ny, nm, nd := now.Local().Date()
+	return y == ny && m == nm && d == nd
+}
+
+// subtaskLabel returns the " (done/total)" subtask progress label, colored
+// green when every subtask is done, or "" for todos without subtasks
+func subtaskLabel(todo *models.Todo) string {
+	done, total := todo.SubtaskProgress()
+	if total == 0 {
+		return ""
+	}
+
+	label := fmt.Sprintf(" (%d/%d)", done, total)
+	if done == total {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#4CAF50")).Render(label)
+	}
+	return label
+}
+
+func (m *ListModel) getVisibleTodos() []*models.Todo {
+	planned, upcoming, noDeadline, completed := m.sections()
+
+	var visible []*models.Todo
+	if m.options.CompletedFirst {
+		visible = append(visible, completed...)
+	}
+	visible = append(visible, planned...)
+	if !m.foldsUpcoming() {
+		visible = append(visible, upcoming...)
+	}
+	visible = append(visible, noDeadline...)
+	if !m.options.CompletedFirst {
+		visible = append(visible, completed...)
+	}
+
 	return visible
 }
 
-func (m *ListModel) ensureCursorVisible() {
-	visibleCount := len(m.getVisibleTodos())
-	pageCount := (visibleCount + pageSize - 1) / pageSize
+// sortedTags returns the tags with incomplete todos in alphabetical order
+func (m *ListModel) sortedTags() []string {
+	tags := make([]string, 0, len(m.tagCounts))
+	for tag := range m.tagCounts {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// dueFilterLabel describes the active period or day filter, such as "this
+// week" or "on Sunday, Nov 16", or returns "" when neither is active
+func (m *ListModel) dueFilterLabel() string {
+	if !m.dayFilter.IsZero() {
+		return "on " + utils.FormatDate(m.dayFilter, m.dateFormat)
+	}
+	return m.periodFilter.String()
+}
 
+// togglePeriodFilter shows only incomplete todos due in the period, or turns
+// the filter off when it is already active. It replaces a day filter.
+func (m *ListModel) togglePeriodFilter(period utils.Period) {
+	anchor := m.selectedID()
+	m.dayFilter = time.Time{}
+	if m.periodFilter == period {
+		m.periodFilter = utils.PeriodNone
+	} else {
+		m.periodFilter = period
+	}
+
+	m.anchorCursor(anchor)
+	m.expanded = make(map[int]bool)
+}
+
+// selectedID returns the ID of the selected todo, or "" when there is none
+func (m *ListModel) selectedID() string {
+	if todo := m.getCurrentTodo(); todo != nil {
+		return todo.ID
+	}
+	return ""
+}
+
+// anchorCursor puts the cursor back on the todo with id after the visible
+// todos were filtered or reordered, so the user keeps their place. It goes
+// to the top when that todo is no longer visible.
+func (m *ListModel) anchorCursor(id string) {
+	m.cursor = 0
+	if !m.options.Paged {
+		m.currentPage = 0
+	}
+	for i, todo := range m.getVisibleTodos() {
+		if todo.ID == id {
+			m.cursor = i
+			break
+		}
+	}
+	m.ensureCursorVisible()
+}
+
+func (m *ListModel) ensureCursorVisible() {
+	if m.options.Paged {
+		// The cursor can't leave the loaded page
+		return
+	}
 	targetPage := m.cursor / pageSize
-	if targetPage != m.currentPage && targetPage < pageCount {
+	if targetPage != m.currentPage && targetPage < m.pageCount() {
 		m.currentPage = targetPage
 	}
 }
 
+// pageCount returns how many pages the visible todos fill. A paged list
+// counts the todos it pages through rather than the page it holds.
+func (m *ListModel) pageCount() int {
+	count := len(m.getVisibleTodos())
+	if m.options.Paged {
+		count = m.pageTotal
+	}
+	return (count + pageSize - 1) / pageSize
+}
+
 func (m *ListModel) getCurrentTodo() *models.Todo {
 	visible := m.getVisibleTodos()
 	if m.cursor >= 0 && m.cursor < len(visible) {
@@ -457,6 +1382,66 @@ func (m *ListModel) getCurrentTodo() *models.Todo {
 	return nil
 }
 
+// pickRandom moves the cursor to a random visible todo suggested as the next
+// task
+func (m *ListModel) pickRandom() {
+	visible := m.getVisibleTodos()
+	todo := storage.SuggestNext(visible, m.rng)
+	if todo == nil {
+		m.toast.show(toastInfo, "Nothing left to pick")
+		return
+	}
+
+	for i, candidate := range visible {
+		if candidate == todo {
+			m.cursor = i
+			break
+		}
+	}
+	m.ensureCursorVisible()
+	m.toast.show(toastInfo, "Next up: "+todo.Title)
+}
+
+// openAttachment opens the highlighted attachment of the selected todo and
+// returns a status message
+func (m *ListModel) openAttachment() string {
+	todo := m.getCurrentTodo()
+	if todo == nil || len(todo.Attachments) == 0 {
+		return "No attachment to open"
+	}
+
+	attachment := todo.Attachments[m.attachmentCursor%len(todo.Attachments)]
+	if err := m.options.Opener.Open(attachment); err != nil {
+		return "Open failed: " + err.Error()
+	}
+	return "Opened " + attachment
+}
+
+// copyCurrentTodo copies the selected todo to the clipboard and returns a
+// status message
+func (m *ListModel) copyCurrentTodo() string {
+	todo := m.getCurrentTodo()
+	if todo == nil {
+		return "Nothing to copy"
+	}
+	if err := m.options.Clipboard.Copy(m.clipboardText(todo)); err != nil {
+		return "Copy failed: " + err.Error()
+	}
+	return "Copied \"" + todo.Title + "\""
+}
+
+// clipboardText formats a todo as plain text for the clipboard
+func (m *ListModel) clipboardText(todo *models.Todo) string {
+	lines := []string{todo.Title}
+	if todo.Description != "" {
+		lines = append(lines, todo.Description)
+	}
+	if todo.Deadline != nil {
+		lines = append(lines, "Due: "+m.formatDeadline(*todo.Deadline))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m *ListModel) toggleComplete() error {
 	todo := m.getCurrentTodo()
 	if todo == nil {
@@ -464,10 +1449,57 @@ func (m *ListModel) toggleComplete() error {
 	}
 
 	if todo.Completed {
+		m.pushUndo("reopening", todo)
 		todo.MarkIncomplete()
-	} else {
-		todo.MarkComplete()
+		if err := m.storage.UpdateTodo(todo); err != nil {
+			m.popUndo()
+			return err
+		}
+		m.toast.show(toastInfo, "Reopened")
+		return nil
 	}
 
-	return m.storage.UpdateTodo(todo)
+	// Shown first so a note about skipped occurrences replaces it
+	m.toast.show(toastSuccess, "Completed")
+	if err := m.completeTodo(todo); err != nil {
+		return err
+	}
+	m.lastAction = repeatAction{kind: repeatComplete}
+	m.startNote(todo)
+	return nil
+}
+
+// completeTodo marks a todo complete and schedules its next occurrence if it
+// recurs. An overdue todo skips the occurrences that already passed, which is
+// reported in the status line.
+func (m *ListModel) completeTodo(todo *models.Todo) error {
+	return m.completeTodoAt(todo, time.Now())
+}
+
+// completeTodoAt works like completeTodo but records the completion at the
+// given time, which may be in the past
+func (m *ListModel) completeTodoAt(todo *models.Todo, at time.Time) error {
+	m.pushUndo("completion", todo)
+	todo.MarkCompleteAt(at)
+	if err := m.storage.UpdateTodo(todo); err != nil {
+		todo.MarkIncomplete()
+		m.popUndo()
+		return err
+	}
+
+	now := time.Now()
+	next := models.NextFutureOccurrence(todo, now)
+	if next == nil {
+		return nil
+	}
+
+	if naive := todo.NextOccurrence(); !naive.Deadline.Equal(*next.Deadline) {
+		m.toast.show(toastInfo, "Skipped missed occurrences, next one is due "+m.formatDeadline(*next.Deadline))
+	}
+	next.ID = models.NewID()
+	if err := m.storage.SaveTodo(next); err != nil {
+		return err
+	}
+	m.undoStack[len(m.undoStack)-1].spawnedID = next.ID
+	return nil
 }
@@ -0,0 +1,1432 @@
+package ui
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/storage"
+	"github.com/akr411/doit/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestListModel_QuickCapture(t *testing.T) {
+	mockStore := &mockStorage{}
+	model := NewListModel(mockStore, ListOptions{})
+	model.loading = false
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+	model = updated.(*ListModel)
+
+	if !model.capturing {
+		t.Fatal("Expected N to open the quick-capture prompt")
+	}
+
+	for _, r := range "Buy milk" {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		if r == ' ' {
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}}
+		}
+		updated, cmd := model.Update(msg)
+		model = updated.(*ListModel)
+		if cmd != nil {
+			t.Fatalf("Expected typing %q not to return a command", r)
+		}
+	}
+
+	if !strings.Contains(model.View(), "Buy milk") {
+		t.Error("Expected view to show the captured input")
+	}
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(*ListModel)
+
+	if model.capturing {
+		t.Error("Expected Enter to close the quick-capture prompt")
+	}
+	if cmd == nil {
+		t.Error("Expected Enter to reload the list")
+	}
+	if len(mockStore.saved) != 1 {
+		t.Fatalf("Expected 1 saved todo, got %d", len(mockStore.saved))
+	}
+
+	saved := mockStore.saved[0]
+	if saved.Title != "Buy milk" {
+		t.Errorf("Saved title = %q, want %q", saved.Title, "Buy milk")
+	}
+	if saved.Deadline != nil {
+		t.Error("Expected quick-captured todo to have no deadline")
+	}
+}
+
+func TestListModel_QuickCaptureTitleLimit(t *testing.T) {
+	mockStore := &mockStorage{}
+	model := NewListModel(mockStore, ListOptions{})
+	model.capturing = true
+
+	for i := 0; i < models.MaxTitleLength+10; i++ {
+		model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	}
+
+	if len(model.captureInput) != models.MaxTitleLength {
+		t.Errorf("Expected capture input limited to %d characters, got %d", models.MaxTitleLength, len(model.captureInput))
+	}
+}
+
+func TestListModel_QuickCaptureMultibyte(t *testing.T) {
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{streak: &storage.Streak{}})
+	model.capturing = true
+
+	for i := 0; i < models.MaxTitleLength+10; i++ {
+		model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'日'}})
+	}
+	if got := utf8.RuneCountInString(model.captureInput); got != models.MaxTitleLength {
+		t.Errorf("Expected capture input limited to %d characters, got %d", models.MaxTitleLength, got)
+	}
+	if want := fmt.Sprintf("Quick add (%d/%d)", models.MaxTitleLength, models.MaxTitleLength); !strings.Contains(model.View(), want) {
+		t.Errorf("Expected the counter to count characters, want %q", want)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if !utf8.ValidString(model.captureInput) || utf8.RuneCountInString(model.captureInput) != models.MaxTitleLength-1 {
+		t.Errorf("Backspace should remove one whole character, got %q", model.captureInput)
+	}
+}
+
+func TestListModel_AutoExpand(t *testing.T) {
+	todos := []*models.Todo{
+		{ID: "1", Title: "Report", Description: "Quarterly numbers"},
+		{ID: "2", Title: "Groceries", Description: "Milk and eggs"},
+		{ID: "3", Title: "Standup", Description: "Share the demo"},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{AutoExpand: true})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	view := model.View()
+	if !strings.Contains(view, "Quarterly numbers") || strings.Contains(view, "Milk and eggs") {
+		t.Fatal("Expected only the selected todo to be expanded")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	view = model.View()
+	if strings.Contains(view, "Quarterly numbers") || !strings.Contains(view, "Milk and eggs") {
+		t.Error("Expected moving down to expand the newly selected todo and collapse the previous one")
+	}
+
+	// Space pins the todo open after the cursor moves away
+	model.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	view = model.View()
+	if !strings.Contains(view, "Milk and eggs") || !strings.Contains(view, "Share the demo") {
+		t.Error("Expected the pinned todo to stay expanded next to the selected one")
+	}
+}
+
+func TestListModel_DueTodayTimeLeft(t *testing.T) {
+	later := time.Now().Add(4*time.Hour + 30*time.Minute)
+	earlier := time.Now().Add(-2*time.Hour - 30*time.Minute)
+	todos := []*models.Todo{
+		{ID: "1", Title: "Later today", Deadline: &later},
+		{ID: "2", Title: "Earlier today", Deadline: &earlier},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	view := model.View()
+	if !strings.Contains(view, "(Due today — 4h left)") {
+		t.Error("Expected the time left on the todo due later today")
+	}
+	if !strings.Contains(view, "(Overdue by 2h)") {
+		t.Error("Expected the todo due earlier today to be overdue")
+	}
+}
+
+// partialStorage reads only some of the todos, like a database with a
+// corrupt record
+type partialStorage struct {
+	mockStorage
+	todos []*models.Todo
+}
+
+func (p *partialStorage) GetAllTodos() ([]*models.Todo, error) {
+	return p.todos, fmt.Errorf("%w: todo 3: bad record", storage.ErrUnreadableTodo)
+}
+
+// versionedStorage reports external changes like a database another process
+// wrote to
+type versionedStorage struct {
+	mockStorage
+	changed bool
+}
+
+func (v *versionedStorage) ChangedExternally() (bool, error) {
+	changed := v.changed
+	v.changed = false
+	return changed, nil
+}
+
+func TestListModel_ReloadShowsExternalChange(t *testing.T) {
+	store := &versionedStorage{}
+	model := NewListModel(store, ListOptions{})
+	model.Update(model.loadData())
+
+	model.Update(model.loadData())
+	if model.toast.text != "" {
+		t.Errorf("Expected no indicator without external changes, got %q", model.toast.text)
+	}
+
+	store.changed = true
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	model.Update(model.loadData())
+	if want := "↻ Updated externally"; model.toast.text != want {
+		t.Errorf("Toast after an external change = %q, want %q", model.toast.text, want)
+	}
+}
+
+// pagedStorage serves pages of todos that are already in deadline order
+type pagedStorage struct {
+	mockStorage
+	todos []*models.Todo
+}
+
+func (p *pagedStorage) GetTodosPage(offset, limit int, order storage.SortOrder) ([]*models.Todo, int, error) {
+	start := min(offset, len(p.todos))
+	end := min(start+limit, len(p.todos))
+	return p.todos[start:end], len(p.todos), nil
+}
+
+func TestListModel_PagedLoadsOnePage(t *testing.T) {
+	store := &pagedStorage{}
+	for i := 1; i <= 25; i++ {
+		store.todos = append(store.todos, &models.Todo{
+			ID:       strconv.Itoa(i),
+			Title:    fmt.Sprintf("Todo %d", i),
+			Deadline: timePtr(time.Now().Add(time.Duration(i) * 24 * time.Hour)),
+		})
+	}
+
+	model := NewListModel(store, ListOptions{Paged: true})
+	model.Update(model.loadData())
+	if got := len(model.todos); got != pageSize {
+		t.Fatalf("Paged list holds %d todos, want one page of %d", got, pageSize)
+	}
+	if !strings.Contains(model.View(), "Page 1/3") {
+		t.Error("Expected the page info to count every todo, not just the loaded page")
+	}
+
+	for _, want := range []string{"11", "21"} {
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+		if cmd == nil {
+			t.Fatal("Expected the next page to be loaded")
+		}
+		model.Update(cmd())
+		if got := model.getCurrentTodo(); got == nil || got.ID != want {
+			t.Errorf("Next page starts with %v, want todo %s", got, want)
+		}
+	}
+	if got := len(model.getVisibleTodos()); got != 5 {
+		t.Errorf("Last page has %d todos, want 5", got)
+	}
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyPgDown}); cmd != nil {
+		t.Error("Expected no load past the last page")
+	}
+
+	// Emptying the last page falls back to the new last page
+	store.todos = store.todos[:20]
+	_, cmd := model.Update(model.loadData())
+	if cmd == nil {
+		t.Fatal("Expected the new last page to be loaded")
+	}
+	model.Update(cmd())
+	if got := model.getCurrentTodo(); model.currentPage != 1 || got == nil || got.ID != "11" {
+		t.Errorf("After the last page emptied, page %d starts with %v, want page 1 from todo 11", model.currentPage, got)
+	}
+}
+
+func TestListModel_PagedNeedsPager(t *testing.T) {
+	model := NewListModel(&mockStorage{}, ListOptions{Paged: true})
+	if model.options.Paged {
+		t.Error("Expected a storage that can't page to load every todo")
+	}
+}
+
+func TestListModel_StaleBacklog(t *testing.T) {
+	old := time.Now().Add(-45 * 24 * time.Hour)
+	todos := []*models.Todo{
+		{ID: "1", Title: "Learn Rust", UpdatedAt: old},
+		{ID: "2", Title: "Clean garage", UpdatedAt: time.Now()},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{StaleAfter: 30 * 24 * time.Hour, StaleNudge: true})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	if want := "💤 1 backlog items untouched for 30+ days"; model.toast.text != want {
+		t.Errorf("Startup nudge = %q, want %q", model.toast.text, want)
+	}
+	for _, line := range strings.Split(model.View(), "\n") {
+		if strings.Contains(line, "Learn Rust") && !strings.Contains(line, "💤") {
+			t.Error("Expected the stale todo to be marked")
+		}
+		if strings.Contains(line, "Clean garage") && strings.Contains(line, "💤") {
+			t.Error("Expected the recently updated todo not to be marked")
+		}
+	}
+
+	// Reloading doesn't nudge again
+	model.toast.text = ""
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+	if model.toast.text != "" {
+		t.Errorf("Expected no nudge after reloading, got %q", model.toast.text)
+	}
+}
+
+func TestListModel_LoadShowsReadableTodos(t *testing.T) {
+	store := &partialStorage{todos: []*models.Todo{
+		{ID: "1", Title: "Report"},
+		{ID: "2", Title: "Groceries"},
+	}}
+	model := NewListModel(store, ListOptions{})
+	model.Update(model.loadData())
+
+	if model.err != nil {
+		t.Fatalf("Expected the readable todos to load, got error %v", model.err)
+	}
+	view := model.View()
+	for _, want := range []string{"Report", "Groceries", "some todos could not be read"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View should contain %q", want)
+		}
+	}
+}
+
+func TestListModel_FoldUpcoming(t *testing.T) {
+	soon, later := time.Now().Add(48*time.Hour), time.Now().Add(72*time.Hour)
+	today, _ := utils.ParseDay("today", time.Now())
+	todos := []*models.Todo{
+		{ID: "1", Title: "Standup", Deadline: &soon},
+		{ID: "2", Title: "Review", Deadline: &later},
+		{ID: "3", Title: "Slides", PlannedFor: &today},
+		{ID: "4", Title: "Someday"},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{FoldUpcoming: true})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	view := model.View()
+	if got := strings.Count(view, "2 upcoming — press U to expand"); got != 1 {
+		t.Errorf("Expected one summary line for the folded section, got %d", got)
+	}
+	if strings.Contains(view, "Standup") || strings.Contains(view, "Review") {
+		t.Error("Folded upcoming todos should not be listed")
+	}
+
+	visible := model.getVisibleTodos()
+	if len(visible) != 2 || visible[0].ID != "3" || visible[1].ID != "4" {
+		t.Fatalf("Expected navigation to skip the folded todos, got %d visible", len(visible))
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := model.getCurrentTodo(); got.ID != "4" {
+		t.Errorf("Moving down selected todo %s, want 4 past the folded section", got.ID)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'U'}})
+	if got := len(model.getVisibleTodos()); got != 4 {
+		t.Errorf("Expected U to unfold the upcoming todos, got %d visible", got)
+	}
+	if got := model.getCurrentTodo(); got.ID != "4" {
+		t.Errorf("Unfolding moved the cursor to todo %s, want it kept on 4", got.ID)
+	}
+}
+
+func TestListModel_TagFilterBeyondTopUpcoming(t *testing.T) {
+	// Ten untagged todos are due before the tagged one, in list order
+	var todos []*models.Todo
+	for i := range upcomingLimit + 1 {
+		deadline := time.Now().Add(time.Duration(i+1) * 24 * time.Hour)
+		todos = append(todos, &models.Todo{ID: fmt.Sprint(i), Title: fmt.Sprintf("Todo %d", i), Deadline: &deadline})
+	}
+	todos[upcomingLimit].Tags = []string{"work"}
+
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+	if got := len(model.getVisibleTodos()); got != upcomingLimit {
+		t.Fatalf("Expected the top %d deadlines without a filter, got %d", upcomingLimit, got)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	visible := model.getVisibleTodos()
+	if len(visible) != 1 || visible[0].ID != fmt.Sprint(upcomingLimit) {
+		t.Errorf("Expected the work todo to be listed under the filter, got %d todos", len(visible))
+	}
+}
+
+func TestListModel_TagFilter(t *testing.T) {
+	todos := []*models.Todo{
+		{ID: "1", Title: "Report", Tags: []string{"work"}},
+		{ID: "2", Title: "Groceries", Tags: []string{"home"}},
+		{ID: "3", Title: "Standup", Tags: []string{"work"}},
+		{ID: "4", Title: "Done", Tags: []string{"work"}, Completed: true},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	if got := len(model.getVisibleTodos()); got != 4 {
+		t.Fatalf("Expected 4 visible todos without a filter, got %d", got)
+	}
+
+	// Tags are numbered alphabetically: 1 home, 2 work
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+
+	if !model.tagFilters["work"] || len(model.tagFilters) != 1 {
+		t.Fatalf("Expected tag filter {work}, got %v", model.tagFilters)
+	}
+
+	visible := model.getVisibleTodos()
+	if len(visible) != 3 {
+		t.Errorf("Expected 3 visible todos tagged work, got %d", len(visible))
+	}
+	for _, todo := range visible {
+		if !todo.HasTag("work") {
+			t.Errorf("Todo %s should not be visible under the work filter", todo.Title)
+		}
+	}
+
+	if !strings.Contains(model.View(), "2 work (2)") {
+		t.Error("Expected view to list the work tag with its incomplete count")
+	}
+
+	// Selecting another tag adds it to the filter set
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	if !model.tagFilters["home"] || !model.tagFilters["work"] {
+		t.Fatalf("Expected tag filter {home, work}, got %v", model.tagFilters)
+	}
+	if got := len(model.getVisibleTodos()); got != 4 {
+		t.Errorf("Expected 4 visible todos tagged home or work, got %d", got)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	if model.tagFilters["work"] || !model.tagFilters["home"] {
+		t.Errorf("Expected selecting an active tag again to remove it, got %v", model.tagFilters)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'0'}})
+	if len(model.tagFilters) != 0 {
+		t.Errorf("Expected 0 to clear the filter, got %v", model.tagFilters)
+	}
+}
+
+func TestListModel_FilterKeepsSelectedTodo(t *testing.T) {
+	soon := time.Now().Add(48 * time.Hour)
+	todos := []*models.Todo{
+		{ID: "1", Title: "Report", Tags: []string{"work"}},
+		{ID: "2", Title: "Groceries", Tags: []string{"home"}},
+		{ID: "3", Title: "Standup", Tags: []string{"work"}, Deadline: &soon},
+		{ID: "4", Title: "Invoice", Tags: []string{"work"}},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	selectTodo := func(id string) {
+		for i, todo := range model.getVisibleTodos() {
+			if todo.ID == id {
+				model.cursor = i
+				return
+			}
+		}
+		t.Fatalf("todo %s is not visible", id)
+	}
+	key := func(r rune) {
+		model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	// Tags are numbered alphabetically: 1 home, 2 work
+	selectTodo("4")
+	for _, r := range []rune{'2', '1', '0'} {
+		key(r)
+		if got := model.getCurrentTodo(); got == nil || got.ID != "4" {
+			t.Fatalf("After %q the cursor moved off todo 4", r)
+		}
+	}
+
+	// Once a filter hides it, the cursor goes to the top
+	selectTodo("2")
+	key('2')
+	if model.cursor != 0 {
+		t.Errorf("Cursor = %d, want the top of the filtered list", model.cursor)
+	}
+
+	key('0')
+	selectTodo("3")
+	key('W')
+	if got := model.getCurrentTodo(); got == nil || got.ID != "3" {
+		t.Error("Expected the week filter to keep todo 3 selected")
+	}
+}
+
+func TestListModel_NestedTagFilter(t *testing.T) {
+	todos := []*models.Todo{
+		{ID: "1", Title: "Invoice A", Tags: []string{"work/clientA"}},
+		{ID: "2", Title: "Invoice B", Tags: []string{"work/clientB"}},
+		{ID: "3", Title: "Workshop", Tags: []string{"workshop"}},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	// Tags are numbered alphabetically: 1 work, 2 work/clientA, ...
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	if !model.tagFilters["work"] {
+		t.Fatalf("Expected tag filter {work}, got %v", model.tagFilters)
+	}
+
+	visible := model.getVisibleTodos()
+	if len(visible) != 2 || visible[0].ID == "3" || visible[1].ID == "3" {
+		t.Errorf("Expected both work/ todos under the work filter, got %d todos", len(visible))
+	}
+}
+
+func TestListModel_RenderSubtaskProgress(t *testing.T) {
+	model := NewListModel(&mockStorage{}, ListOptions{})
+
+	withSubtasks := &models.Todo{
+		ID:    "1",
+		Title: "Release",
+		Subtasks: []models.Subtask{
+			{Title: "Tag", Done: true},
+			{Title: "Build", Done: true},
+			{Title: "Announce"},
+			{Title: "Docs"},
+			{Title: "Blog"},
+		},
+	}
+	withoutSubtasks := &models.Todo{ID: "2", Title: "Groceries"}
+	plain := lipgloss.NewStyle()
+
+	row := model.renderTodo(withSubtasks, 0, false, plain, plain, plain, plain, plain, plain)
+	if !strings.Contains(row, "Release (2/5)") {
+		t.Errorf("Expected row to contain subtask progress, got %q", row)
+	}
+
+	row = model.renderTodo(withoutSubtasks, 1, false, plain, plain, plain, plain, plain, plain)
+	if strings.Contains(row, "/") {
+		t.Errorf("Expected no subtask progress for a todo without subtasks, got %q", row)
+	}
+}
+
+func TestListModel_CompleteRecurringSchedulesNext(t *testing.T) {
+	// A Friday next week, so the todo is not overdue
+	now := time.Now()
+	friday := time.Date(now.Year(), now.Month(), now.Day()+7+int(time.Friday-now.Weekday()+7)%7, 9, 0, 0, 0, time.Local)
+	todo := &models.Todo{ID: "1", Title: "Standup", Deadline: &friday, Recurrence: models.RecurWeekdays}
+
+	mockStore := &mockStorage{}
+	model := NewListModel(mockStore, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+
+	if !todo.Completed {
+		t.Error("Expected the todo to be completed")
+	}
+	if len(mockStore.saved) != 1 {
+		t.Fatalf("Expected the next occurrence to be saved, got %d saves", len(mockStore.saved))
+	}
+
+	next := mockStore.saved[0]
+	monday := friday.AddDate(0, 0, 3)
+	if !next.Deadline.Equal(monday) {
+		t.Errorf("Next occurrence deadline = %v, want %v", next.Deadline, monday)
+	}
+	if next.ID == "" || next.ID == todo.ID {
+		t.Errorf("Next occurrence should get a new ID, got %q", next.ID)
+	}
+}
+
+type fakeClipboard struct {
+	text string
+	err  error
+}
+
+func (c *fakeClipboard) Copy(text string) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.text = text
+	return nil
+}
+
+func TestListModel_CopyToClipboard(t *testing.T) {
+	deadline := time.Date(2025, 11, 21, 9, 0, 0, 0, time.Local)
+	todos := []*models.Todo{
+		{ID: "1", Title: "Send report", Description: "To the whole team", Deadline: &deadline},
+	}
+
+	tests := []struct {
+		name       string
+		clipboard  *fakeClipboard
+		wantText   string
+		wantStatus string
+	}{
+		{
+			name:       "copies title, description and deadline",
+			clipboard:  &fakeClipboard{},
+			wantText:   "Send report\nTo the whole team\nDue: Nov 21, 9:00 AM",
+			wantStatus: `Copied "Send report"`,
+		},
+		{
+			name:       "no clipboard tool",
+			clipboard:  &fakeClipboard{err: ErrNoClipboard},
+			wantText:   "",
+			wantStatus: "Copy failed: " + ErrNoClipboard.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewListModel(&mockStorage{}, ListOptions{Clipboard: tt.clipboard})
+			model.dateFormat = utils.FormatOpts{}
+			model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+			model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Y'}})
+
+			if tt.clipboard.text != tt.wantText {
+				t.Errorf("Copied text = %q, want %q", tt.clipboard.text, tt.wantText)
+			}
+			if model.toast.text != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", model.toast.text, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestListModel_DueSoonBannerRespectsQuietHours(t *testing.T) {
+	now := time.Date(2025, 11, 16, 23, 0, 0, 0, time.Local)
+	todos := []*models.Todo{
+		{ID: "1", Title: "Tonight", Deadline: timePtr(now.Add(time.Hour))},
+		{ID: "2", Title: "Tomorrow", Deadline: timePtr(now.Add(20 * time.Hour))},
+		{ID: "3", Title: "Next week", Deadline: timePtr(now.AddDate(0, 0, 7))},
+		{ID: "4", Title: "Done", Deadline: timePtr(now.Add(time.Hour)), Completed: true},
+	}
+
+	tests := []struct {
+		name                 string
+		quietStart, quietEnd string
+		want                 string
+	}{
+		{"no quiet hours", "", "", "⏰ 2 todos due in the next 24 hours"},
+		{"inside quiet hours", "22:00", "07:00", ""},
+		{"outside quiet hours", "01:00", "07:00", "⏰ 2 todos due in the next 24 hours"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewListModel(&mockStorage{}, ListOptions{})
+			model.quietStart, model.quietEnd = tt.quietStart, tt.quietEnd
+			model.todos = todos
+
+			if got := model.dueSoonBanner(now); got != tt.want {
+				t.Errorf("dueSoonBanner() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestListModel_NudgeDeadline(t *testing.T) {
+	deadline := time.Now().Add(72 * time.Hour).Truncate(time.Minute)
+	key := func(s string) tea.KeyMsg {
+		switch s {
+		case "shift+up":
+			return tea.KeyMsg{Type: tea.KeyShiftUp}
+		case "down":
+			return tea.KeyMsg{Type: tea.KeyDown}
+		case "enter":
+			return tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			return tea.KeyMsg{Type: tea.KeyEsc}
+		}
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	tests := []struct {
+		name string
+		keys []string
+		want *time.Time
+	}{
+		{"later by a day and an hour", []string{"+", "shift+up", "enter"}, timePtr(deadline.Add(25 * time.Hour))},
+		{"earlier by two days", []string{"-", "down", "enter"}, timePtr(deadline.Add(-48 * time.Hour))},
+		{"cancelled", []string{"+", "+", "esc"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := &models.Todo{ID: "1", Title: "Report", Deadline: timePtr(deadline)}
+			mockStore := &mockStorage{}
+			model := NewListModel(mockStore, ListOptions{})
+			model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+
+			for _, k := range tt.keys {
+				model.Update(key(k))
+			}
+
+			if model.nudging {
+				t.Error("Expected nudge mode to end")
+			}
+			if tt.want == nil {
+				if len(mockStore.updated) != 0 || !todo.Deadline.Equal(deadline) {
+					t.Errorf("Expected no update, got deadline %v", todo.Deadline)
+				}
+				return
+			}
+			if len(mockStore.updated) != 1 {
+				t.Fatalf("Expected 1 update, got %d", len(mockStore.updated))
+			}
+			if !todo.Deadline.Equal(*tt.want) {
+				t.Errorf("Deadline = %v, want %v", todo.Deadline, *tt.want)
+			}
+		})
+	}
+}
+
+func TestListModel_PickRandomMovesCursor(t *testing.T) {
+	var todos []*models.Todo
+	for i := range 25 {
+		todos = append(todos, &models.Todo{ID: strconv.Itoa(i), Title: "Todo " + strconv.Itoa(i), Completed: i%2 == 0})
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.rng = rand.New(rand.NewSource(5))
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+
+	picked := model.getCurrentTodo()
+	if picked == nil || picked.Completed {
+		t.Fatalf("Expected the cursor on an incomplete todo, got %v", picked)
+	}
+	if model.toast.text != "Next up: "+picked.Title {
+		t.Errorf("Status = %q, want %q", model.toast.text, "Next up: "+picked.Title)
+	}
+	if model.currentPage != model.cursor/pageSize {
+		t.Errorf("Page = %d, want %d", model.currentPage, model.cursor/pageSize)
+	}
+}
+
+func TestListModel_CompleteOverdueRecurringRollsForward(t *testing.T) {
+	overdue := time.Now().AddDate(0, 0, -5)
+	todo := &models.Todo{ID: "1", Title: "Water plants", Deadline: &overdue, Recurrence: models.RecurDaily}
+
+	mockStore := &mockStorage{}
+	model := NewListModel(mockStore, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+
+	if len(mockStore.saved) != 1 {
+		t.Fatalf("Expected the next occurrence to be saved, got %d saves", len(mockStore.saved))
+	}
+	next := mockStore.saved[0].Deadline
+	if !next.After(time.Now()) || next.After(time.Now().AddDate(0, 0, 1)) {
+		t.Errorf("Next occurrence deadline = %v, want within the next day", next)
+	}
+	if !strings.HasPrefix(model.toast.text, "Skipped missed occurrences") {
+		t.Errorf("Status = %q, want a note about skipped occurrences", model.toast.text)
+	}
+}
+
+func TestListModel_CompleteWithNote(t *testing.T) {
+	todo := &models.Todo{ID: "1", Title: "Call the bank"}
+	mockStore := &mockStorage{}
+	model := NewListModel(mockStore, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if !model.noting {
+		t.Fatal("Expected completing a todo to prompt for a note")
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Fee refunded")})
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if model.noting {
+		t.Error("Expected Enter to close the note prompt")
+	}
+	if len(mockStore.updated) != 2 || mockStore.updated[1].CompletionNote != "Fee refunded" {
+		t.Fatalf("Expected the note to be saved, got %d updates", len(mockStore.updated))
+	}
+
+	detail := NewDetailModel(mockStore, todo, model)
+	if view := detail.View(); !strings.Contains(view, "Fee refunded") {
+		t.Error("Detail view should show the completion note")
+	}
+
+	// Reopening drops the note, and completing again without one keeps it empty
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if todo.CompletionNote != "" {
+		t.Errorf("CompletionNote after reopening = %q, want empty", todo.CompletionNote)
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.noting || todo.CompletionNote != "" {
+		t.Error("An empty note should be skipped")
+	}
+}
+
+func TestListModel_CompleteAllOverdue(t *testing.T) {
+	past, future := time.Now().AddDate(0, 0, -3), time.Now().AddDate(0, 0, 3)
+	overdue := &models.Todo{ID: "1", Title: "Overdue", Deadline: &past}
+	upcoming := &models.Todo{ID: "2", Title: "Upcoming", Deadline: &future}
+
+	mockStore := &mockStorage{}
+	model := NewListModel(mockStore, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{overdue, upcoming}, streak: &storage.Streak{}})
+
+	// Any key other than y cancels
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'O'}})
+	if !strings.Contains(model.View(), "Complete 1 overdue todos?") {
+		t.Fatal("Expected O to ask for confirmation")
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if overdue.Completed {
+		t.Fatal("Cancelling should not complete anything")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'O'}})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if !overdue.Completed || upcoming.Completed {
+		t.Errorf("Completed = %v, %v, want only the overdue todo", overdue.Completed, upcoming.Completed)
+	}
+	if model.toast.text != "Completed 1 overdue todos" {
+		t.Errorf("Status = %q, want the count", model.toast.text)
+	}
+}
+
+func TestListModel_SnoozeAllOverdue(t *testing.T) {
+	past, future := time.Now().AddDate(0, 0, -3), time.Now().AddDate(0, 0, 3)
+	overdue := &models.Todo{ID: "1", Title: "Overdue", Deadline: &past}
+	upcoming := &models.Todo{ID: "2", Title: "Upcoming", Deadline: &future}
+
+	mockStore := &mockStorage{}
+	model := NewListModel(mockStore, ListOptions{MorningHour: 8})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{overdue, upcoming}, streak: &storage.Streak{}})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Z'}})
+	if !strings.Contains(model.View(), "Snooze 1 overdue todos until tomorrow morning?") {
+		t.Fatal("Expected Z to ask for confirmation")
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+
+	want := utils.TomorrowMorning(time.Now(), 8)
+	if !overdue.Deadline.Equal(want) {
+		t.Errorf("Overdue deadline = %v, want %v", overdue.Deadline, want)
+	}
+	if !upcoming.Deadline.Equal(future) {
+		t.Errorf("Upcoming deadline = %v, want it unchanged", upcoming.Deadline)
+	}
+	if len(mockStore.updated) != 1 {
+		t.Errorf("Expected 1 update, got %d", len(mockStore.updated))
+	}
+}
+
+func TestListModel_Undo(t *testing.T) {
+	todo := &models.Todo{ID: "1", Title: "Pay rent"}
+	mockStore := &mockStorage{}
+	model := NewListModel(mockStore, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+
+	if len(mockStore.updated) != 2 {
+		t.Fatalf("Expected u to save the todo, got %d updates", len(mockStore.updated))
+	}
+	if reverted := mockStore.updated[1]; reverted.ID != "1" || reverted.Completed || reverted.CompletedAt != nil {
+		t.Errorf("Undo saved %+v, want the todo incomplete again", reverted)
+	}
+
+	// The stack is empty now
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if len(mockStore.updated) != 2 {
+		t.Errorf("Expected a second u to do nothing, got %d updates", len(mockStore.updated))
+	}
+	if model.toast.text != "Nothing to undo" {
+		t.Errorf("Status = %q, want %q", model.toast.text, "Nothing to undo")
+	}
+}
+
+func TestListModel_RepeatLastAction(t *testing.T) {
+	first, second := time.Now().AddDate(0, 0, 5), time.Now().AddDate(0, 0, 10)
+	todos := []*models.Todo{
+		{ID: "1", Title: "Invoice Acme", Deadline: &first},
+		{ID: "2", Title: "Invoice Globex", Deadline: &second},
+	}
+	mockStore := &mockStorage{}
+	model := NewListModel(mockStore, ListOptions{})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'.'}})
+	if len(mockStore.updated) != 0 {
+		t.Fatal("Expected . to do nothing before any action")
+	}
+
+	// Snooze the first todo by two days, then repeat on the second
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'.'}})
+
+	if want := first.Add(2 * nudgeStep); !todos[0].Deadline.Equal(want) {
+		t.Errorf("First deadline = %v, want %v", todos[0].Deadline, want)
+	}
+	if want := second.Add(2 * nudgeStep); !todos[1].Deadline.Equal(want) {
+		t.Errorf("Repeated deadline = %v, want %v", todos[1].Deadline, want)
+	}
+
+	// A repeated delete still asks for confirmation
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model.Update(tea.KeyMsg{Type: tea.KeyUp})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'.'}})
+	if len(mockStore.deleted) != 1 || !model.confirmingDelete {
+		t.Fatalf("Expected . to ask before deleting, got %d deletes", len(mockStore.deleted))
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if len(mockStore.deleted) != 2 || mockStore.deleted[1] != "1" {
+		t.Errorf("Expected the repeated delete to remove todo 1, got %v", mockStore.deleted)
+	}
+}
+
+func TestListModel_SnoozeTomorrow(t *testing.T) {
+	todo := &models.Todo{ID: "1", Title: "Call mum"}
+	mockStore := &mockStorage{}
+	model := NewListModel(mockStore, ListOptions{MorningHour: 8})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+
+	if len(mockStore.updated) != 1 {
+		t.Fatalf("Expected S to save the todo, got %d updates", len(mockStore.updated))
+	}
+	want := utils.TomorrowMorning(time.Now(), 8)
+	if todo.Deadline == nil || !todo.Deadline.Equal(want) {
+		t.Errorf("Deadline = %v, want %v", todo.Deadline, want)
+	}
+}
+
+func TestListModel_PromoteSubtask(t *testing.T) {
+	todo := &models.Todo{ID: "1", Title: "Release", Tags: []string{"work"}, Subtasks: []models.Subtask{
+		{Title: "Tag"},
+		{Title: "Build"},
+	}}
+	mockStore := &mockStorage{}
+	model := NewListModel(mockStore, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	if len(mockStore.saved) != 0 {
+		t.Fatal("P should do nothing while the todo is collapsed")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+
+	if len(mockStore.saved) != 1 || mockStore.saved[0].Title != "Build" {
+		t.Fatalf("Expected the highlighted subtask to be saved as a todo, got %v", mockStore.saved)
+	}
+	if !mockStore.saved[0].HasTag("work") || mockStore.saved[0].ID == "" {
+		t.Errorf("Promoted todo = %+v, want an ID and the parent's tags", mockStore.saved[0])
+	}
+	if len(mockStore.updated) != 1 || len(todo.Subtasks) != 1 || todo.Subtasks[0].Title != "Tag" {
+		t.Errorf("Parent subtasks = %v, want [Tag] saved", todo.Subtasks)
+	}
+}
+
+func TestListModel_PlannedForToday(t *testing.T) {
+	nextWeek := time.Now().AddDate(0, 0, 7)
+	todo := &models.Todo{ID: "1", Title: "Draft slides", Deadline: &nextWeek}
+	mockStore := &mockStorage{}
+	model := NewListModel(mockStore, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+
+	if strings.Contains(model.View(), "Planned for Today") {
+		t.Error("View should not show the planned section when nothing is planned")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if todo.PlannedFor == nil || len(mockStore.updated) != 1 {
+		t.Fatal("t should plan the todo for today and save it")
+	}
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+	view := model.View()
+	if !strings.Contains(view, "Planned for Today") {
+		t.Error("View should list the todo under Planned for Today")
+	}
+	if strings.Count(view, "Draft slides") != 1 {
+		t.Error("A planned todo should not also be listed under its deadline")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if todo.PlannedFor != nil {
+		t.Error("t on a planned todo should take it off the plan")
+	}
+}
+
+func TestListModel_BulkDeleteConfirmation(t *testing.T) {
+	markAndDelete := func(count int) (*ListModel, *mockStorage) {
+		var todos []*models.Todo
+		for i := range count {
+			todos = append(todos, &models.Todo{ID: fmt.Sprintf("%d", i), Title: fmt.Sprintf("Todo %d", i)})
+		}
+		mockStore := &mockStorage{}
+		model := NewListModel(mockStore, ListOptions{})
+		model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+		for range count {
+			model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+			model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		}
+		model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+		return model, mockStore
+	}
+
+	model, mockStore := markAndDelete(3)
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if len(mockStore.deleted) != 3 {
+		t.Errorf("y should delete 3 marked todos, deleted %d", len(mockStore.deleted))
+	}
+
+	model, mockStore = markAndDelete(10)
+	if !strings.Contains(model.View(), "Type 10 or yes") {
+		t.Error("Deleting 10 todos should ask for the count to be typed")
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(mockStore.deleted) != 0 {
+		t.Fatalf("y should not delete 10 marked todos, deleted %d", len(mockStore.deleted))
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("10")})
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(mockStore.deleted) != 10 {
+		t.Errorf("Typing the count should delete 10 marked todos, deleted %d", len(mockStore.deleted))
+	}
+}
+
+func TestListModel_TagEdit(t *testing.T) {
+	todo := &models.Todo{ID: "1", Title: "Gym", Tags: []string{"health"}}
+	mockStore := &mockStorage{}
+	model := NewListModel(mockStore, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+
+	typeTags := func(input string) {
+		model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'#'}})
+		model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(input)})
+		model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+
+	typeTags("routine,health")
+	if !slices.Equal(todo.Tags, []string{"health", "routine"}) {
+		t.Errorf("Tags after adding = %v, want [health routine]", todo.Tags)
+	}
+
+	typeTags("-health")
+	if !slices.Equal(todo.Tags, []string{"routine"}) {
+		t.Errorf("Tags after removing = %v, want [routine]", todo.Tags)
+	}
+
+	typeTags("-missing")
+	if len(mockStore.updated) != 2 {
+		t.Errorf("Removing an absent tag should not save, got %d updates", len(mockStore.updated))
+	}
+}
+
+func TestListModel_WeeklyGoal(t *testing.T) {
+	now := time.Now()
+	streak := &storage.Streak{
+		WeeklyGoal:       15,
+		DailyCompletions: map[string]int{now.Format("2006-01-02"): 4},
+	}
+	model := NewListModel(&mockStorage{}, ListOptions{WeekStart: now.Weekday()})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{{ID: "1", Title: "Gym"}}, streak: streak})
+
+	if !strings.Contains(model.View(), "Goal: 4/15 this week") {
+		t.Error("View should show the progress towards the weekly goal")
+	}
+}
+
+func TestListModel_DayFilter(t *testing.T) {
+	tomorrow := utils.TomorrowMorning(time.Now(), 10)
+	later := tomorrow.AddDate(0, 0, 1)
+	due := &models.Todo{ID: "1", Title: "Dentist", Deadline: &tomorrow}
+	other := &models.Todo{ID: "2", Title: "Groceries", Deadline: &later}
+
+	day, _ := utils.ParseDay("tomorrow", time.Now())
+	model := NewListModel(&mockStorage{}, ListOptions{Day: day})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{due, other}, streak: &storage.Streak{}})
+
+	view := model.View()
+	if !strings.Contains(view, "Due on "+day.Format("Monday, Jan 2")) {
+		t.Error("View should name the day being shown")
+	}
+	if !strings.Contains(view, "Dentist") || strings.Contains(view, "Groceries") {
+		t.Error("View should only list the todos due that day")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'0'}})
+	if !strings.Contains(model.View(), "Groceries") {
+		t.Error("0 should clear the day filter")
+	}
+}
+
+func TestListModel_HelpOverlay(t *testing.T) {
+	todo := &models.Todo{ID: "1", Title: "Report"}
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	if !model.showHelp {
+		t.Fatal("Expected ? to open the help overlay")
+	}
+
+	view := model.View()
+	for _, want := range []string{"Navigation", "Todos", "Bulk", "Filter", "General", "Complete marked", "Pick a todo for me"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Help overlay should contain %q", want)
+		}
+	}
+
+	// Any key closes the overlay without acting on it
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if model.showHelp {
+		t.Error("Expected any key to close the help overlay")
+	}
+	if todo.Completed {
+		t.Error("The key closing the help overlay should not complete the todo")
+	}
+	if strings.Contains(model.View(), "Navigation") {
+		t.Error("View should not show the help overlay after closing it")
+	}
+}
+
+type fakeOpener struct {
+	opened []string
+}
+
+func (o *fakeOpener) Open(target string) error {
+	o.opened = append(o.opened, target)
+	return nil
+}
+
+func TestListModel_OpenAttachment(t *testing.T) {
+	todo := &models.Todo{ID: "1", Title: "Review", Attachments: []string{"/docs/a.pdf", "/docs/b.pdf"}}
+	opener := &fakeOpener{}
+	model := NewListModel(&mockStorage{}, ListOptions{Opener: opener})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+
+	model.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	view := model.View()
+	if !strings.Contains(view, "› 📎 /docs/a.pdf") || !strings.Contains(view, "📎 /docs/b.pdf") {
+		t.Errorf("Expanded view should list the attachments with the first highlighted, got:\n%s", view)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+
+	if want := []string{"/docs/a.pdf", "/docs/b.pdf"}; !slices.Equal(opener.opened, want) {
+		t.Errorf("Opened %v, want %v", opener.opened, want)
+	}
+	if model.toast.text != "Opened /docs/b.pdf" {
+		t.Errorf("Status = %q, want %q", model.toast.text, "Opened /docs/b.pdf")
+	}
+}
+
+func TestListModel_EmptyToForm(t *testing.T) {
+	empty := dataLoadedMsg{todos: []*models.Todo{}, streak: &storage.Streak{}}
+
+	model := NewListModel(&mockStorage{}, ListOptions{EmptyToForm: true})
+	next, _ := model.Update(empty)
+	if _, ok := next.(*FormModel); !ok {
+		t.Errorf("Update() with no todos and EmptyToForm = %T, want *FormModel", next)
+	}
+
+	model = NewListModel(&mockStorage{}, ListOptions{})
+	next, _ = model.Update(empty)
+	if _, ok := next.(*ListModel); !ok {
+		t.Errorf("Update() with no todos = %T, want *ListModel", next)
+	}
+
+	model = NewListModel(&mockStorage{}, ListOptions{EmptyToForm: true})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{{ID: "1", Title: "Last"}}, streak: &storage.Streak{}})
+	next, _ = model.Update(empty)
+	if _, ok := next.(*ListModel); !ok {
+		t.Errorf("Update() emptied by a reload = %T, want *ListModel", next)
+	}
+}
+
+func TestListModel_PeriodFilter(t *testing.T) {
+	now := time.Now()
+	weekStart, weekEnd := utils.PeriodBounds(utils.PeriodWeek, now)
+	inWeek := weekStart.Add(time.Hour)
+	nextWeek := weekEnd.Add(time.Hour)
+
+	todos := []*models.Todo{
+		{ID: "1", Title: "This week", Deadline: &inWeek, Tags: []string{"work"}},
+		{ID: "2", Title: "Next week", Deadline: &nextWeek},
+		{ID: "3", Title: "No deadline"},
+		{ID: "4", Title: "Done this week", Deadline: &inWeek, Completed: true},
+		{ID: "5", Title: "Home this week", Deadline: &inWeek, Tags: []string{"home"}},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'W'}})
+	visible := model.getVisibleTodos()
+	if len(visible) != 2 || visible[0].ID != "1" || visible[1].ID != "5" {
+		t.Errorf("Week filter shows %d todos, want the two incomplete todos due this week", len(visible))
+	}
+
+	model.tagFilters["work"] = true
+	if visible := model.getVisibleTodos(); len(visible) != 1 || visible[0].ID != "1" {
+		t.Errorf("Week and tag filter should combine, got %d todos", len(visible))
+	}
+	delete(model.tagFilters, "work")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'W'}})
+	if got := len(model.getVisibleTodos()); got != len(todos) {
+		t.Errorf("Pressing W again should clear the filter, got %d todos", got)
+	}
+}
+
+func TestListModel_CompletedTodayHighlight(t *testing.T) {
+	now := time.Now()
+	lastWeek := now.AddDate(0, 0, -7)
+	today := &models.Todo{ID: "1", Title: "Shipped release", Completed: true, CompletedAt: &now}
+	older := &models.Todo{ID: "2", Title: "Old chore", Completed: true, CompletedAt: &lastWeek}
+
+	if !completedToday(today, now) {
+		t.Error("completedToday() = false for a todo completed today, want true")
+	}
+	if completedToday(older, now) {
+		t.Error("completedToday() = true for a todo completed last week, want false")
+	}
+	if completedToday(&models.Todo{ID: "3", CompletedAt: &now}, now) {
+		t.Error("completedToday() = true for an incomplete todo, want false")
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{today, older}, streak: &storage.Streak{}})
+
+	for _, line := range strings.Split(model.View(), "\n") {
+		switch {
+		case strings.Contains(line, "Shipped release") && !strings.Contains(line, "✨"):
+			t.Errorf("Expected the todo completed today to be marked, got %q", line)
+		case strings.Contains(line, "Old chore") && strings.Contains(line, "✨"):
+			t.Errorf("Expected the todo completed last week not to be marked, got %q", line)
+		}
+	}
+}
+
+func TestWaitingLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		todo *models.Todo
+		want string
+	}{
+		{"Not waiting", &models.Todo{}, ""},
+		{"Waiting", &models.Todo{Waiting: true}, " ⏳"},
+		{"Waiting on someone", &models.Todo{Waiting: true, WaitingOn: "Alex"}, " ⏳ Alex"},
+		{"Completed", &models.Todo{Waiting: true, Completed: true}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := waitingLabel(tt.todo); got != tt.want {
+				t.Errorf("waitingLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListModel_WaitingExcludedFromDueSoon(t *testing.T) {
+	now := time.Date(2025, 11, 17, 9, 0, 0, 0, time.Local)
+	soon := now.Add(2 * time.Hour)
+	todos := []*models.Todo{
+		{ID: "1", Title: "Ship", Deadline: &soon},
+		{ID: "2", Title: "Contract", Deadline: &soon, Waiting: true, WaitingOn: "Legal"},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.todos = todos
+	if got := model.dueSoonBanner(now); got != "⏰ 1 todo due in the next 24 hours" {
+		t.Errorf("dueSoonBanner() = %q, want the waiting todo left out", got)
+	}
+
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+	model.cursor = 0
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	if !todos[0].Waiting {
+		t.Error("Expected w to mark the todo as waiting")
+	}
+	if !strings.Contains(model.View(), "Ship ⏳") {
+		t.Error("Expected the waiting marker in the view")
+	}
+}
+
+func TestListModel_VeryOverdueEscalated(t *testing.T) {
+	now := time.Now()
+	neglected := now.AddDate(0, 0, -10)
+	waitingLong := now.AddDate(0, 0, -20)
+	yesterday := now.AddDate(0, 0, -1)
+	todos := []*models.Todo{
+		{ID: "1", Title: "Waiting on legal", Deadline: &waitingLong, Waiting: true},
+		{ID: "2", Title: "Yesterday's chore", Deadline: &yesterday},
+		{ID: "3", Title: "Neglected taxes", Deadline: &neglected},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	visible := model.getVisibleTodos()
+	if len(visible) != 3 || visible[0].ID != "3" {
+		t.Fatalf("getVisibleTodos()[0] = %v, want the very overdue todo first", visible[0].ID)
+	}
+
+	// Planned todos escalate the same way
+	today, _ := utils.ParseDay("today", now)
+	for _, todo := range todos {
+		todo.PlannedFor = &today
+	}
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+	planned, _, _, _ := model.sections()
+	if len(planned) != 3 || planned[0].ID != "3" {
+		t.Fatalf("Planned section = %v, want the very overdue todo first", planned)
+	}
+
+	view := model.View()
+	if !strings.Contains(view, "⚠ 10 days overdue") {
+		t.Error("Expected a very overdue badge in the view")
+	}
+	if strings.Contains(view, "⚠ 20 days overdue") {
+		t.Error("Expected the waiting todo not to be escalated")
+	}
+	if !strings.Contains(view, "(Overdue by 1 days)") {
+		t.Error("Expected a recently overdue todo to keep the plain label")
+	}
+}
+
+func TestListModel_Focus(t *testing.T) {
+	todos := []*models.Todo{
+		{ID: "1", Title: "Write launch post", Project: "Launch"},
+		{ID: "2", Title: "Groceries"},
+		{ID: "3", Title: "Fix signup bug", Project: "launch"},
+		{ID: "4", Title: "Plan offsite", Project: "Team"},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{Focus: "Launch"})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	visible := model.getVisibleTodos()
+	if len(visible) != 2 {
+		t.Fatalf("getVisibleTodos() = %d todos, want the 2 in the focused project", len(visible))
+	}
+	for _, todo := range visible {
+		if !strings.EqualFold(todo.Project, "Launch") {
+			t.Errorf("getVisibleTodos() contains %q from project %q", todo.Title, todo.Project)
+		}
+	}
+	if !strings.Contains(model.View(), "Todo List — Launch") {
+		t.Error("Expected the focused project in the title")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'0'}})
+	if got := len(model.getVisibleTodos()); got != len(todos) {
+		t.Errorf("getVisibleTodos() after clearing = %d todos, want %d", got, len(todos))
+	}
+}
+
+func TestListModel_ReminderLeadInBanner(t *testing.T) {
+	now := time.Date(2025, 11, 17, 9, 0, 0, 0, time.Local)
+	inTwoDays := now.Add(48 * time.Hour)
+	inTwoHours := now.Add(2 * time.Hour)
+
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.todos = []*models.Todo{
+		{ID: "1", Title: "Passport", Deadline: &inTwoDays, ReminderBefore: 72 * time.Hour},
+		{ID: "2", Title: "Call", Deadline: &inTwoHours, ReminderBefore: time.Hour},
+	}
+	if got := model.dueSoonBanner(now); got != "⏰ 1 todo due soon" {
+		t.Errorf("dueSoonBanner() = %q, want only the todo whose reminder lead was entered", got)
+	}
+}
+
+func TestListModel_WeekSparkline(t *testing.T) {
+	now := time.Now()
+	streak := &storage.Streak{
+		CurrentStreak: 2,
+		MaxStreak:     2,
+		DailyCompletions: map[string]int{
+			now.AddDate(0, 0, -1).Format("2006-01-02"): 2,
+			now.Format("2006-01-02"):                   4,
+		},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{streak: streak})
+
+	if !strings.Contains(model.View(), "Week: ▁▁▁▁▁▅█") {
+		t.Error("Expected the week's completions as a sparkline in the header")
+	}
+}
+
+func TestListModel_CompletedFirst(t *testing.T) {
+	tomorrow := time.Now().Add(24 * time.Hour)
+	todos := []*models.Todo{
+		{ID: "1", Title: "Upcoming", Deadline: &tomorrow},
+		{ID: "2", Title: "Someday"},
+		{ID: "3", Title: "Shipped", Completed: true},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{CompletedFirst: true})
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	var ids []string
+	for _, todo := range model.getVisibleTodos() {
+		ids = append(ids, todo.ID)
+	}
+	if got := strings.Join(ids, ","); got != "3,1,2" {
+		t.Errorf("getVisibleTodos() = %s, want completed first: 3,1,2", got)
+	}
+
+	view := model.View()
+	if !(strings.Index(view, "Shipped") < strings.Index(view, "Upcoming") &&
+		strings.Index(view, "Upcoming") < strings.Index(view, "Someday")) {
+		t.Error("Expected the completed section to render above the others")
+	}
+	if !strings.Contains(view, "Completed") {
+		t.Error("Expected the completed section to keep its header at the top")
+	}
+
+	// The cursor follows the same order
+	model.cursor = 0
+	if got := model.getCurrentTodo(); got == nil || got.ID != "3" {
+		t.Errorf("getCurrentTodo() at the top = %v, want the completed todo", got)
+	}
+}
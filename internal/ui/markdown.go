@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	mdBulletRegex = regexp.MustCompile(`^(\s*)[-*]\s+(.*)$`)
+	mdLinkRegex   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBoldRegex   = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	mdItalicRegex = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_]+)_\b`)
+
+	mdBoldStyle   = lipgloss.NewStyle().Bold(true)
+	mdItalicStyle = lipgloss.NewStyle().Italic(true)
+	mdLinkStyle   = lipgloss.NewStyle().Underline(true)
+)
+
+// RenderMarkdown renders a small subset of markdown: **bold**, *italic* or
+// _italic_, "- " and "* " bullet lists and [text](url) links. Anything else
+// is left as is.
+func RenderMarkdown(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if match := mdBulletRegex.FindStringSubmatch(line); match != nil {
+			lines[i] = match[1] + "• " + renderInline(match[2])
+			continue
+		}
+		lines[i] = renderInline(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderInline(text string) string {
+	text = mdLinkRegex.ReplaceAllStringFunc(text, func(s string) string {
+		match := mdLinkRegex.FindStringSubmatch(s)
+		return mdLinkStyle.Render(match[1]) + " (" + match[2] + ")"
+	})
+
+	text = mdBoldRegex.ReplaceAllStringFunc(text, func(s string) string {
+		return mdBoldStyle.Render(mdBoldRegex.FindStringSubmatch(s)[1])
+	})
+
+	return mdItalicRegex.ReplaceAllStringFunc(text, func(s string) string {
+		match := mdItalicRegex.FindStringSubmatch(s)
+		if match[1] != "" {
+			return mdItalicStyle.Render(match[1])
+		}
+		return mdItalicStyle.Render(match[2])
+	})
+}
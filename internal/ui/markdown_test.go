@@ -0,0 +1,60 @@
+package ui
+
+import "testing"
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "plain text unchanged",
+			input:    "Buy milk, eggs and bread",
+			expected: "Buy milk, eggs and bread",
+		},
+		{
+			name:     "bold",
+			input:    "This is **important** today",
+			expected: "This is " + mdBoldStyle.Render("important") + " today",
+		},
+		{
+			name:     "italic",
+			input:    "Maybe *later* or _never_",
+			expected: "Maybe " + mdItalicStyle.Render("later") + " or " + mdItalicStyle.Render("never"),
+		},
+		{
+			name:     "bullet list",
+			input:    "Steps:\n- first\n* second\n  - nested",
+			expected: "Steps:\n• first\n• second\n  • nested",
+		},
+		{
+			name:     "bullet with bold",
+			input:    "- **ship** it",
+			expected: "• " + mdBoldStyle.Render("ship") + " it",
+		},
+		{
+			name:     "link",
+			input:    "See [docs](https://example.com)",
+			expected: "See " + mdLinkStyle.Render("docs") + " (https://example.com)",
+		},
+		{
+			name:     "unknown syntax left literal",
+			input:    "# heading with `code` and snake_case_name",
+			expected: "# heading with `code` and snake_case_name",
+		},
+		{
+			name:     "unterminated bold left literal",
+			input:    "2 ** 3",
+			expected: "2 ** 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderMarkdown(tt.input); got != tt.expected {
+				t.Errorf("RenderMarkdown(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
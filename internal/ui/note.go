@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/akr411/doit/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startNote prompts for an optional note on the todo that was just completed
+func (m *ListModel) startNote(todo *models.Todo) {
+	m.noting = true
+	m.noteTodo = todo
+	m.noteInput = ""
+}
+
+// handleNote reads the completion note until it is saved or skipped. An empty
+// note is skipped like Esc.
+func (m *ListModel) handleNote(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		m.noting = false
+		m.noteTodo = nil
+
+	case tea.KeyEnter:
+		todo := m.noteTodo
+		note := strings.TrimSpace(m.noteInput)
+		m.noting = false
+		m.noteTodo = nil
+		if note == "" {
+			return m, nil
+		}
+
+		todo.CompletionNote = note
+		if err := m.storage.UpdateTodo(todo); err != nil {
+			m.toast.show(toastError, err.Error())
+			return m, nil
+		}
+		m.toast.show(toastSuccess, "Note saved")
+		return m, m.loadData
+
+	case tea.KeyBackspace:
+		input := []rune(m.noteInput)
+		if len(input) > 0 {
+			m.noteInput = string(input[:len(input)-1])
+		}
+
+	case tea.KeySpace, tea.KeyRunes:
+		m.noteInput += msg.String()
+	}
+
+	return m, nil
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// nudgeStep is how far the arrow keys move a deadline
+	nudgeStep = 24 * time.Hour
+	// nudgeFineStep is how far shift and the arrow keys move a deadline
+	nudgeFineStep = time.Hour
+)
+
+// startNudge enters nudge mode for the selected todo and applies the first
+// step. Todos without a deadline start from the next full hour.
+func (m *ListModel) startNudge(delta time.Duration) {
+	todo := m.getCurrentTodo()
+	if todo == nil || todo.Completed {
+		return
+	}
+
+	m.nudging = true
+	m.nudgeTodo = todo
+	m.nudgeFrom = nudgeBase(todo)
+	m.nudgeDeadline = utils.NudgeDeadline(m.nudgeFrom, delta, false)
+}
+
+// nudgeBase is the deadline a nudge starts from: the todo's own, or the next
+// full hour for todos without one
+func nudgeBase(todo *models.Todo) time.Time {
+	if todo.Deadline != nil {
+		return *todo.Deadline
+	}
+	return time.Now().Truncate(time.Hour).Add(time.Hour)
+}
+
+// handleNudge moves the pending deadline until it is saved or cancelled
+func (m *ListModel) handleNudge(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.nudging = false
+		m.nudgeTodo = nil
+
+	case "up", "k", "+":
+		m.nudgeDeadline = utils.NudgeDeadline(m.nudgeDeadline, nudgeStep, false)
+
+	case "down", "j", "-":
+		m.nudgeDeadline = utils.NudgeDeadline(m.nudgeDeadline, -nudgeStep, false)
+
+	case "shift+up":
+		m.nudgeDeadline = utils.NudgeDeadline(m.nudgeDeadline, nudgeFineStep, false)
+
+	case "shift+down":
+		m.nudgeDeadline = utils.NudgeDeadline(m.nudgeDeadline, -nudgeFineStep, false)
+
+	case "enter":
+		todo := m.nudgeTodo
+		deadline := m.nudgeDeadline
+		m.nudging = false
+		m.nudgeTodo = nil
+
+		if !m.moveDeadline(todo, deadline) {
+			return m, nil
+		}
+		m.lastAction = repeatAction{kind: repeatShift, shift: deadline.Sub(m.nudgeFrom)}
+		return m, m.loadData
+	}
+
+	return m, nil
+}
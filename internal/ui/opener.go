@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// Opener opens a file or URL with the program the OS associates with it
+type Opener interface {
+	Open(target string) error
+}
+
+// SystemOpener opens targets with the platform's default opener
+type SystemOpener struct{}
+
+// Open starts the opener for target without waiting for it to exit
+func (SystemOpener) Open(target string) error {
+	name, args := openCommand(runtime.GOOS, target)
+	return exec.Command(name, args...).Start()
+}
+
+// openCommand returns the command and arguments that open target on the
+// given OS
+func openCommand(goos, target string) (string, []string) {
+	switch goos {
+	case "darwin":
+		return "open", []string{target}
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", target}
+	default:
+		return "xdg-open", []string{target}
+	}
+}
@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOpenCommand(t *testing.T) {
+	target := "/home/me/notes/plan final.pdf"
+
+	tests := []struct {
+		goos     string
+		wantName string
+		wantArgs []string
+	}{
+		{"darwin", "open", []string{target}},
+		{"windows", "rundll32", []string{"url.dll,FileProtocolHandler", target}},
+		{"linux", "xdg-open", []string{target}},
+		{"freebsd", "xdg-open", []string{target}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			name, args := openCommand(tt.goos, target)
+			if name != tt.wantName || !slices.Equal(args, tt.wantArgs) {
+				t.Errorf("openCommand(%q) = %v %v, want %v %v", tt.goos, name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startCompleteOverdue asks to confirm completing every overdue todo
+func (m *ListModel) startCompleteOverdue() {
+	overdue := storage.OverdueTodos(m.todos)
+	if len(overdue) == 0 {
+		m.toast.show(toastInfo, "No overdue todos")
+		return
+	}
+	m.overdueBatch = overdue
+}
+
+// handleCompleteOverdue completes the overdue todos on y, any other key
+// cancels
+func (m *ListModel) handleCompleteOverdue(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	overdue := m.overdueBatch
+	m.overdueBatch = nil
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "y":
+		if err := m.completeOverdue(overdue, time.Now()); err != nil {
+			m.toast.show(toastError, err.Error())
+			return m, nil
+		}
+		m.toast.show(toastSuccess, fmt.Sprintf("Completed %d overdue todos", len(overdue)))
+		return m, m.loadData
+	}
+
+	return m, nil
+}
+
+// completeOverdue completes the todos in one batch and schedules the next
+// occurrence of the repeating ones
+func (m *ListModel) completeOverdue(overdue []*models.Todo, now time.Time) error {
+	if err := m.storage.CompleteTodos(overdue, now); err != nil {
+		return err
+	}
+
+	for _, todo := range overdue {
+		next := models.NextFutureOccurrence(todo, now)
+		if next == nil {
+			continue
+		}
+		next.ID = models.NewID()
+		if err := m.storage.SaveTodo(next); err != nil {
+			return fmt.Errorf("failed to schedule the next occurrence: %w", err)
+		}
+	}
+	return nil
+}
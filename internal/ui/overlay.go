@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// defaultViewWidth and defaultViewHeight are used before the terminal
+	// size is known
+	defaultViewWidth  = 80
+	defaultViewHeight = 24
+
+	// minViewWidth and minViewHeight are the smallest terminal the views and
+	// their dialogs fit in
+	minViewWidth  = 40
+	minViewHeight = 10
+)
+
+var dimStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#4B5563"))
+
+// viewSize returns the terminal size, or 80x24 until it is known
+func (m *ListModel) viewSize() (width, height int) {
+	width, height = m.width, m.height
+	if width <= 0 {
+		width = defaultViewWidth
+	}
+	if height <= 0 {
+		height = defaultViewHeight
+	}
+	return width, height
+}
+
+// tooSmallView returns a message asking to resize the terminal when the known
+// size is below the minimum, or "" when the layout fits
+func tooSmallView(width, height int) string {
+	if (width <= 0 || width >= minViewWidth) && (height <= 0 || height >= minViewHeight) {
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#EF4444")).
+		Render(fmt.Sprintf("Terminal too small — resize to at least %dx%d", minViewWidth, minViewHeight))
+}
+
+// placeOverlay centers dialog in a view of the given size, drawn over the
+// background with the background dimmed
+func placeOverlay(background, dialog string, width, height int) string {
+	dialogLines := strings.Split(dialog, "\n")
+	dialogWidth := lipgloss.Width(dialog)
+
+	leftPadding := max((width-dialogWidth)/2, 0)
+	topPadding := max((height-len(dialogLines))/2, 0)
+
+	lines := strings.Split(background, "\n")
+	for len(lines) < topPadding+len(dialogLines) {
+		lines = append(lines, "")
+	}
+
+	for i, line := range lines {
+		if i >= topPadding && i < topPadding+len(dialogLines) {
+			lines[i] = strings.Repeat(" ", leftPadding) + dialogLines[i-topPadding]
+		} else {
+			lines[i] = dimStyle.Render(ansi.Strip(line))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestPlaceOverlay_CentersInViewSize(t *testing.T) {
+	background := strings.Repeat("row\n", 4) + "row"
+	dialog := "+----+\n| hi |\n+----+"
+
+	got := strings.Split(placeOverlay(background, dialog, 40, 11), "\n")
+
+	// Starting (11 - 3) / 2 rows down and (40 - 6) / 2 columns across
+	if len(got) != 7 {
+		t.Fatalf("placeOverlay() returned %d lines, want the background padded to 7", len(got))
+	}
+	if got[5] != strings.Repeat(" ", 17)+"| hi |" {
+		t.Errorf("placeOverlay() dialog line = %q", got[5])
+	}
+	if lipgloss.Width(got[0]) != len("row") {
+		t.Errorf("placeOverlay() background line = %q, want the dimmed row", got[0])
+	}
+}
+
+func TestListModel_TooSmallTerminal(t *testing.T) {
+	todo := &models.Todo{ID: "1", Title: "Report"}
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+
+	model.Update(tea.WindowSizeMsg{Width: 30, Height: 8})
+	view := model.View()
+	if !strings.Contains(view, "Terminal too small") || strings.Contains(view, "Report") {
+		t.Errorf("View() = %q, want only the too small message", view)
+	}
+
+	model.Update(tea.WindowSizeMsg{Width: minViewWidth, Height: minViewHeight})
+	if strings.Contains(model.View(), "Terminal too small") {
+		t.Error("View() at the minimum size should render the list")
+	}
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/akr411/doit/internal/utils"
+)
+
+// togglePlannedToday plans the selected todo for today, or takes it off
+// today's plan when it already is on it
+func (m *ListModel) togglePlannedToday() {
+	todo := m.getCurrentTodo()
+	if todo == nil || todo.Completed {
+		return
+	}
+
+	previous := todo.PlannedFor
+	today, _ := utils.ParseDay("today", time.Now())
+	message := "No longer planned for today"
+	if previous != nil && previous.Equal(today) {
+		todo.PlannedFor = nil
+	} else {
+		todo.PlannedFor = &today
+		message = "Planned for today"
+	}
+	if err := m.storage.UpdateTodo(todo); err != nil {
+		todo.PlannedFor = previous
+		m.toast.show(toastError, err.Error())
+		return
+	}
+	m.toast.show(toastSuccess, message)
+}
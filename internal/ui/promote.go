@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"slices"
+
+	"github.com/akr411/doit/internal/models"
+)
+
+// promoteSubtask turns the highlighted subtask of the expanded todo into a
+// standalone todo with the parent's deadline, tags and project
+func (m *ListModel) promoteSubtask() {
+	todo := m.getCurrentTodo()
+	if todo == nil || !m.isExpanded(m.cursor) || len(todo.Subtasks) == 0 {
+		return
+	}
+
+	subtasks := slices.Clone(todo.Subtasks)
+	updatedAt := todo.UpdatedAt
+	promoted, err := todo.PromoteSubtask(m.subtaskCursor % len(subtasks))
+	if err != nil {
+		m.toast.show(toastError, err.Error())
+		return
+	}
+	promoted.ID = models.NewID()
+
+	if err := m.storage.SaveTodo(promoted); err != nil {
+		todo.Subtasks = subtasks
+		todo.UpdatedAt = updatedAt
+		m.toast.show(toastError, err.Error())
+		return
+	}
+	if err := m.storage.UpdateTodo(todo); err != nil {
+		m.toast.show(toastError, err.Error())
+		return
+	}
+	m.subtaskCursor = 0
+	m.toast.show(toastSuccess, "Promoted "+promoted.Title+" to a todo")
+}
@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/storage"
+	"github.com/akr411/doit/internal/utils"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Renderer styles the parts of a static render of the todo list
+type Renderer interface {
+	// Heading renders the title of the list
+	Heading(text string) string
+	// Section renders the heading of a group of todos
+	Section(text string) string
+	// Todo renders a todo as a single line
+	Todo(todo *models.Todo, now time.Time) string
+}
+
+// NewRenderer returns the plain renderer when plain is set, for screen
+// readers and logs, and the colored one otherwise
+func NewRenderer(plain bool) Renderer {
+	if plain {
+		return plainRenderer{}
+	}
+	return styledRenderer{}
+}
+
+// RenderList renders todos in SortTodos order grouped like the list view:
+// the todos planned for today, the next 10 deadlines, the todos without a
+// deadline and the completed ones
+func RenderList(todos []*models.Todo, r Renderer, now time.Time) string {
+	planned := storage.PlannedToday(todos, now)
+	isPlanned := make(map[string]bool, len(planned))
+	for _, todo := range planned {
+		isPlanned[todo.ID] = true
+	}
+	without := func(todos []*models.Todo) []*models.Todo {
+		var rest []*models.Todo
+		for _, todo := range todos {
+			if !isPlanned[todo.ID] {
+				rest = append(rest, todo)
+			}
+		}
+		return rest
+	}
+	upcoming, noDeadline, completed := storage.PartitionTodos(todos, len(todos))
+
+	var s strings.Builder
+	s.WriteString(r.Heading("Todo List"))
+	s.WriteString("\n")
+	if len(todos) == 0 {
+		s.WriteString("\nNo todos.\n")
+		return s.String()
+	}
+
+	for _, section := range []struct {
+		title string
+		todos []*models.Todo
+	}{
+		{"Planned for Today", planned},
+		{"Upcoming Deadlines (Top 10)", firstN(without(upcoming), upcomingLimit)},
+		{"No Deadline", without(noDeadline)},
+		{"Completed", completed},
+	} {
+		if len(section.todos) == 0 {
+			continue
+		}
+		s.WriteString("\n")
+		s.WriteString(r.Section(section.title))
+		s.WriteString("\n")
+		for _, todo := range section.todos {
+			s.WriteString(r.Todo(todo, now))
+			s.WriteString("\n")
+		}
+	}
+	return s.String()
+}
+
+// renderedDeadline is the label of an incomplete todo's deadline and its
+// urgency band, or "" for todos without one
+func renderedDeadline(todo *models.Todo, now time.Time) (string, Band) {
+	if todo.Deadline == nil || todo.Completed {
+		return "", BandNone
+	}
+	days := int(todo.Deadline.Sub(now).Hours() / 24)
+	if todo.Deadline.Before(now) && days == 0 {
+		days = -1
+	}
+	date := utils.FormatTime(todo.Deadline.Local(), utils.FormatOptsFromEnv())
+	return fmt.Sprintf("%s (%s)", date, relativeDays(days)), UrgencyBand(days, DefaultWarnDays, DefaultSoonDays)
+}
+
+// styledRenderer renders with the list view's colors
+type styledRenderer struct{}
+
+func (styledRenderer) Heading(text string) string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#7C3AED")).Bold(true).Render(text)
+}
+
+func (styledRenderer) Section(text string) string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#9333EA")).Bold(true).Render(text)
+}
+
+func (styledRenderer) Todo(todo *models.Todo, now time.Time) string {
+	if todo.Completed {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF")).Strikethrough(true).
+			Render("[✔] " + todo.Title)
+	}
+
+	line := "[ ] " + todo.Title + subtaskLabel(todo) + waitingLabel(todo)
+	deadline, band := renderedDeadline(todo, now)
+	switch {
+	case deadline == "":
+	case band == BandOverdue || band == BandSoon:
+		line += " " + lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444")).Bold(true).Render(deadline)
+	case band == BandWarn:
+		line += " " + lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Render(deadline)
+	default:
+		line += " " + deadline
+	}
+	return line
+}
+
+// plainRenderer renders without colors, emoji or box drawing, spelling out
+// what the list view shows with them
+type plainRenderer struct{}
+
+func (plainRenderer) Heading(text string) string {
+	return strings.ToUpper(text)
+}
+
+func (plainRenderer) Section(text string) string {
+	return text + ":"
+}
+
+func (plainRenderer) Todo(todo *models.Todo, now time.Time) string {
+	if todo.Completed {
+		return "[DONE] " + todo.Title
+	}
+
+	deadline, band := renderedDeadline(todo, now)
+	var line strings.Builder
+	switch band {
+	case BandOverdue:
+		line.WriteString("OVERDUE: ")
+	case BandSoon:
+		line.WriteString("DUE SOON: ")
+	}
+	line.WriteString("[ ] " + todo.Title)
+	if done, total := todo.SubtaskProgress(); total > 0 {
+		fmt.Fprintf(&line, " (%d of %d subtasks done)", done, total)
+	}
+	if todo.Waiting {
+		line.WriteString(" (waiting")
+		if todo.WaitingOn != "" {
+			line.WriteString(" on " + todo.WaitingOn)
+		}
+		line.WriteString(")")
+	}
+	if deadline != "" {
+		line.WriteString(" - due " + deadline)
+	}
+	return line.String()
+}
+
+// firstN returns at most the first n todos
+func firstN(todos []*models.Todo, n int) []*models.Todo {
+	return todos[:min(n, len(todos))]
+}
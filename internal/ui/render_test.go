@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/storage"
+)
+
+func TestRenderList_Plain(t *testing.T) {
+	now := time.Now()
+	overdue := now.AddDate(0, 0, -3)
+	nextWeek := now.AddDate(0, 0, 7)
+	todos := []*models.Todo{
+		{ID: "1", Title: "File taxes", Deadline: &overdue},
+		{ID: "2", Title: "Sign contract", Deadline: &nextWeek, Waiting: true, WaitingOn: "Legal"},
+		{ID: "3", Title: "Release", Subtasks: []models.Subtask{{Title: "Tag", Done: true}, {Title: "Build"}}},
+		{ID: "4", Title: "Buy milk", Completed: true},
+	}
+	storage.SortTodos(todos)
+
+	got := RenderList(todos, NewRenderer(true), now)
+
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("RenderList() plain output contains ANSI escapes: %q", got)
+	}
+	for _, want := range []string{
+		"OVERDUE: [ ] File taxes - due",
+		"(overdue by 3 days)",
+		"[ ] Sign contract (waiting on Legal) - due",
+		"[ ] Release (1 of 2 subtasks done)",
+		"[DONE] Buy milk",
+		"No Deadline:",
+		"Completed:",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderList() plain output missing %q:\n%s", want, got)
+		}
+	}
+	for _, symbol := range []string{"✔", "⏳", "⚠", "─", "│"} {
+		if strings.Contains(got, symbol) {
+			t.Errorf("RenderList() plain output contains %q:\n%s", symbol, got)
+		}
+	}
+}
+
+func TestRenderList_Empty(t *testing.T) {
+	got := RenderList(nil, NewRenderer(true), time.Now())
+	if !strings.Contains(got, "No todos.") {
+		t.Errorf("RenderList() of no todos = %q, want it to say so", got)
+	}
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// repeatKind is a mutating action . can repeat on another todo
+type repeatKind int
+
+const (
+	repeatNone repeatKind = iota
+	repeatComplete
+	repeatSnooze
+	repeatShift
+	repeatDelete
+)
+
+// repeatAction is the last action . repeats, with how far a reschedule moved
+// the deadline
+type repeatAction struct {
+	kind  repeatKind
+	shift time.Duration
+}
+
+// repeatLast applies the last complete, snooze, reschedule or delete to the
+// selected todo. A repeated delete still asks for confirmation.
+func (m *ListModel) repeatLast() tea.Cmd {
+	todo := m.getCurrentTodo()
+	if todo == nil {
+		return nil
+	}
+
+	switch m.lastAction.kind {
+	case repeatNone:
+		m.toast.show(toastInfo, "Nothing to repeat")
+
+	case repeatComplete:
+		if todo.Completed {
+			m.toast.show(toastInfo, "Already completed")
+			return nil
+		}
+		if err := m.toggleComplete(); err != nil {
+			m.toast.show(toastError, err.Error())
+		}
+		return m.loadData
+
+	case repeatSnooze:
+		m.snoozeTomorrow()
+		return m.loadData
+
+	case repeatShift:
+		if todo.Completed {
+			return nil
+		}
+		m.moveDeadline(todo, utils.NudgeDeadline(nudgeBase(todo), m.lastAction.shift, false))
+		return m.loadData
+
+	case repeatDelete:
+		m.confirmingDelete = true
+		m.todoToDelete = todo
+	}
+	return nil
+}
+
+// moveDeadline saves a new deadline for todo, reporting whether it was saved
+func (m *ListModel) moveDeadline(todo *models.Todo, deadline time.Time) bool {
+	m.pushUndo("rescheduling", todo)
+	todo.Deadline = &deadline
+	if err := m.storage.UpdateTodo(todo); err != nil {
+		m.popUndo()
+		m.toast.show(toastError, err.Error())
+		return false
+	}
+	m.toast.show(toastSuccess, "Deadline moved to "+m.formatDeadline(deadline))
+	return true
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/akr411/doit/internal/storage"
+	"github.com/akr411/doit/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// snoozeTomorrow moves the selected todo's deadline to tomorrow morning at
+// the configured hour
+func (m *ListModel) snoozeTomorrow() {
+	todo := m.getCurrentTodo()
+	if todo == nil || todo.Completed {
+		return
+	}
+
+	deadline := utils.TomorrowMorning(time.Now(), m.options.MorningHour)
+	m.pushUndo("snooze", todo)
+	todo.Deadline = &deadline
+	if err := m.storage.UpdateTodo(todo); err != nil {
+		m.popUndo()
+		m.toast.show(toastError, err.Error())
+		return
+	}
+	m.toast.show(toastSuccess, "Snoozed until "+m.formatDeadline(deadline))
+	m.lastAction = repeatAction{kind: repeatSnooze}
+}
+
+// startSnoozeOverdue asks to confirm snoozing every overdue todo
+func (m *ListModel) startSnoozeOverdue() {
+	overdue := storage.OverdueTodos(m.todos)
+	if len(overdue) == 0 {
+		m.toast.show(toastInfo, "No overdue todos")
+		return
+	}
+	m.snoozeBatch = overdue
+}
+
+// handleSnoozeOverdue moves the overdue todos to tomorrow morning in one
+// batch on y, any other key cancels
+func (m *ListModel) handleSnoozeOverdue(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	overdue := m.snoozeBatch
+	m.snoozeBatch = nil
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "y":
+		deadline := utils.TomorrowMorning(time.Now(), m.options.MorningHour)
+		if err := m.storage.RescheduleTodos(overdue, deadline); err != nil {
+			m.toast.show(toastError, err.Error())
+			return m, nil
+		}
+		m.toast.show(toastSuccess, fmt.Sprintf("Snoozed %d overdue todos until %s", len(overdue), m.formatDeadline(deadline)))
+		return m, m.loadData
+	}
+
+	return m, nil
+}
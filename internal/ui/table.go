@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// checkboxColumnWidth fits a checkbox with its mark, e.g. "[✔]*"
+	checkboxColumnWidth = 4
+	// tagsColumnWidth fits a couple of short tags, longer ones are cut
+	tagsColumnWidth = 14
+	// dueColumnWidth fits the longest due in label, e.g. "overdue 365d"
+	dueColumnWidth = 12
+	// rowPadding leaves room for the row padding of the list styles
+	rowPadding = 3
+)
+
+// TableColumns holds the widths of the table layout columns
+type TableColumns struct {
+	Checkbox int
+	Title    int
+	Tags     int
+	Due      int
+}
+
+// ComputeColumns derives the table column widths from the terminal width,
+// giving the title whatever the checkbox, tags and due in columns leave over
+func ComputeColumns(width int) TableColumns {
+	if width <= 0 {
+		width = defaultViewWidth
+	}
+	// One space between each pair of columns
+	title := width - checkboxColumnWidth - tagsColumnWidth - dueColumnWidth - 3 - rowPadding
+	return TableColumns{
+		Checkbox: checkboxColumnWidth,
+		Title:    max(title, 10),
+		Tags:     tagsColumnWidth,
+		Due:      dueColumnWidth,
+	}
+}
+
+// Row lays out one table row with the tags and the due in label
+// right-aligned. Title lines after the first are indented to the title
+// column.
+func (c TableColumns) Row(checkbox string, titleLines []string, tags, due string) string {
+	cell := func(text string, width int) string {
+		return lipgloss.NewStyle().Width(width).Render(text)
+	}
+	rightCell := func(text string, width int) string {
+		return lipgloss.NewStyle().Width(width).Align(lipgloss.Right).Render(text)
+	}
+
+	rows := []string{cell(checkbox, c.Checkbox) + " " + cell(titleLines[0], c.Title) + " " +
+		rightCell(tags, c.Tags) + " " + rightCell(due, c.Due)}
+
+	indent := strings.Repeat(" ", c.Checkbox+1)
+	for _, line := range titleLines[1:] {
+		rows = append(rows, indent+line)
+	}
+	return strings.Join(rows, "\n")
+}
+
+// dueInLabel returns a short relative label for the due in column, or an
+// empty string for completed todos and todos without a deadline
+func dueInLabel(todo *models.Todo) string {
+	if todo.Deadline == nil || todo.Completed {
+		return ""
+	}
+	days := todo.DaysUntilDeadline()
+	switch {
+	case days < 0:
+		return fmt.Sprintf("overdue %dd", -days)
+	case days == 0:
+		return "today"
+	default:
+		return fmt.Sprintf("in %dd", days)
+	}
+}
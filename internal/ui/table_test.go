@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/storage"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestComputeColumns(t *testing.T) {
+	tests := []struct {
+		width     int
+		wantTitle int
+	}{
+		{width: 60, wantTitle: 60 - checkboxColumnWidth - tagsColumnWidth - dueColumnWidth - 3 - rowPadding},
+		{width: 0, wantTitle: defaultViewWidth - checkboxColumnWidth - tagsColumnWidth - dueColumnWidth - 3 - rowPadding},
+		{width: 20, wantTitle: 10},
+	}
+
+	for _, tt := range tests {
+		if got := ComputeColumns(tt.width).Title; got != tt.wantTitle {
+			t.Errorf("ComputeColumns(%d).Title = %v, want %v", tt.width, got, tt.wantTitle)
+		}
+	}
+}
+
+func TestListModel_TableRowsAlignDueColumn(t *testing.T) {
+	soon := time.Now().Add(50 * time.Hour)
+	later := time.Now().Add(10*24*time.Hour + time.Hour)
+	todos := []*models.Todo{
+		{ID: "1", Title: "Short", Deadline: &soon, Tags: []string{"work"}},
+		{ID: "2", Title: "A somewhat longer title", Deadline: &later, Tags: []string{"errands", "weekend", "family"}},
+		{ID: "3", Title: "A title that is far too long to fit into the title column at this width", Deadline: &later},
+	}
+
+	model := NewListModel(&mockStorage{}, ListOptions{Table: true})
+	model.width = 60
+	model.Update(dataLoadedMsg{todos: todos, streak: &storage.Streak{}})
+
+	plain := lipgloss.NewStyle()
+	var ends []int
+	for i, todo := range todos {
+		row := model.renderTodo(todo, i, false, plain, plain, plain, plain, plain, plain)
+		label := dueInLabel(todo)
+		if !strings.HasSuffix(row, label) {
+			t.Fatalf("Row %q should end with the due in label %q", row, label)
+		}
+		ends = append(ends, lipgloss.Width(row))
+	}
+
+	for i, end := range ends {
+		if end != ends[0] {
+			t.Errorf("Row %d due column ends at %d, want %d", i, end, ends[0])
+		}
+	}
+	if want := ComputeColumns(60); ends[0] != want.Checkbox+want.Title+want.Tags+want.Due+3 {
+		t.Errorf("Row width = %d, want %d", ends[0], want.Checkbox+want.Title+want.Tags+want.Due+3)
+	}
+}
+
+func TestTagsLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		tags  []string
+		width int
+		want  string
+	}{
+		{"no tags", nil, 14, ""},
+		{"fits", []string{"work", "home"}, 14, "work, home"},
+		{"exactly fits", []string{"errands", "car"}, 12, "errands, car"},
+		{"truncated", []string{"errands", "weekend", "family"}, 14, "errands, week…"},
+		{"wide runes", []string{"買い物", "仕事"}, 8, "買い物…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TagsLabel(tt.tags, tt.width)
+			if got != tt.want {
+				t.Errorf("TagsLabel(%v, %d) = %q, want %q", tt.tags, tt.width, got, tt.want)
+			}
+			if lipgloss.Width(got) > tt.width {
+				t.Errorf("TagsLabel(%v, %d) is %d wide, want at most %d", tt.tags, tt.width, lipgloss.Width(got), tt.width)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/akr411/doit/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startTagEdit prompts for tags to add to or remove from the selected todo
+func (m *ListModel) startTagEdit() {
+	if m.getCurrentTodo() == nil {
+		return
+	}
+	m.tagging = true
+	m.tagInput = ""
+}
+
+// handleTagEdit reads the tags until they are applied or cancelled. Tags are
+// comma separated and added, or removed when the input starts with -.
+func (m *ListModel) handleTagEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		m.tagging = false
+
+	case tea.KeyEnter:
+		m.tagging = false
+		todo := m.getCurrentTodo()
+		if todo == nil {
+			return m, nil
+		}
+
+		input := strings.TrimSpace(m.tagInput)
+		remove := strings.HasPrefix(input, "-")
+		tags := utils.ParseTags(strings.TrimLeft(input, "+-"))
+		if len(tags) == 0 {
+			return m, nil
+		}
+
+		previous := todo.Tags
+		todo.Tags = append([]string(nil), todo.Tags...)
+		changed := false
+		for _, tag := range tags {
+			if remove {
+				changed = todo.RemoveTag(tag) || changed
+			} else {
+				changed = todo.AddTag(tag) || changed
+			}
+		}
+		if !changed {
+			todo.Tags = previous
+			m.toast.show(toastInfo, "Tags unchanged")
+			return m, nil
+		}
+
+		if err := m.storage.UpdateTodo(todo); err != nil {
+			todo.Tags = previous
+			m.toast.show(toastError, err.Error())
+			return m, nil
+		}
+		verb := "Added "
+		if remove {
+			verb = "Removed "
+		}
+		m.toast.show(toastSuccess, verb+strings.Join(tags, ", "))
+		return m, m.loadData
+
+	case tea.KeyBackspace:
+		input := []rune(m.tagInput)
+		if len(input) > 0 {
+			m.tagInput = string(input[:len(input)-1])
+		}
+
+	case tea.KeySpace, tea.KeyRunes:
+		m.tagInput += msg.String()
+	}
+
+	return m, nil
+}
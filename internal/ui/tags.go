@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/utils"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TagsLabel joins tags for the tags column, cutting it with an ellipsis when
+// it is wider than width
+func TagsLabel(tags []string, width int) string {
+	label := strings.Join(tags, ", ")
+	if width <= 0 || lipgloss.Width(label) <= width {
+		return label
+	}
+	// Never end on a dangling separator
+	return strings.TrimRight(strings.TrimSuffix(truncateTitle(label, width), ellipsis), ", ") + ellipsis
+}
+
+// renderTagLegend numbers the tags for the 1-9 keys with their incomplete
+// counts, highlighting the ones filtered on, or returns "" without tags
+func (m *ListModel) renderTagLegend(selectedStyle, helpStyle lipgloss.Style) string {
+	tags := m.sortedTags()
+	if len(tags) == 0 {
+		return ""
+	}
+
+	var legend []string
+	for i, tag := range tags {
+		entry := fmt.Sprintf("%d %s (%d)", i+1, tag, m.tagCounts[tag])
+		if m.tagFilters[tag] {
+			entry = selectedStyle.Render(entry)
+		}
+		legend = append(legend, entry)
+	}
+	return helpStyle.Render("Tags: " + strings.Join(legend, " • "))
+}
+
+// selectTagFilter toggles the numbered legend entry in the tag filter set,
+// where 0 clears it along with the project focus
+func (m *ListModel) selectTagFilter(key string) {
+	n, err := strconv.Atoi(key)
+	if err != nil {
+		return
+	}
+
+	anchor := m.selectedID()
+	tags := m.sortedTags()
+	switch {
+	case n == 0:
+		clear(m.tagFilters)
+		m.projectFilter = ""
+		m.dayFilter = time.Time{}
+	case n <= len(tags) && m.tagFilters[tags[n-1]]:
+		delete(m.tagFilters, tags[n-1])
+	case n <= len(tags):
+		m.tagFilters[tags[n-1]] = true
+	default:
+		return
+	}
+
+	m.anchorCursor(anchor)
+	m.expanded = make(map[int]bool)
+}
+
+// matchesTagFilters reports whether todo has any of the filtered tags, or
+// one nested under them. Every todo matches when none are filtered on.
+func (m *ListModel) matchesTagFilters(todo *models.Todo) bool {
+	if len(m.tagFilters) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(todo.Tags, func(tag string) bool {
+		for filter := range m.tagFilters {
+			if utils.TagMatchesFilter(tag, filter) {
+				return true
+			}
+		}
+		return false
+	})
+}
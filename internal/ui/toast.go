@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// toastDuration is how long a toast is shown before it clears itself
+const toastDuration = 3 * time.Second
+
+type toastLevel int
+
+const (
+	toastInfo toastLevel = iota
+	toastSuccess
+	toastError
+)
+
+// toast is a transient message shown at the bottom of a view. Every message
+// gets a new id, so the tick of an earlier one cannot clear a later one.
+type toast struct {
+	text    string
+	level   toastLevel
+	id      int
+	pending bool
+}
+
+// toastExpiredMsg clears the toast with the same id
+type toastExpiredMsg struct{ id int }
+
+// show replaces the current toast. Its expiry is scheduled by expireCmd, so
+// helpers that don't return a tea.Cmd can show toasts too.
+func (t *toast) show(level toastLevel, text string) {
+	t.id++
+	t.text = text
+	t.level = level
+	t.pending = true
+}
+
+// expireCmd returns the tick clearing a toast shown since the last call, or
+// nil if there is none
+func (t *toast) expireCmd() tea.Cmd {
+	if !t.pending {
+		return nil
+	}
+	t.pending = false
+	id := t.id
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+// expire clears the toast if msg belongs to it
+func (t *toast) expire(msg toastExpiredMsg) {
+	if msg.id == t.id {
+		t.text = ""
+	}
+}
+
+// View renders the toast, or "" when there is none
+func (t toast) View() string {
+	if t.text == "" {
+		return ""
+	}
+
+	color := "#F59E0B"
+	switch t.level {
+	case toastSuccess:
+		color = "#4CAF50"
+	case toastError:
+		color = "#EF4444"
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(" " + t.text)
+}
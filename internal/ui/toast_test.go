@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akr411/doit/internal/models"
+	"github.com/akr411/doit/internal/storage"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestToast_ExpireClearsOnlyItsOwnMessage(t *testing.T) {
+	var note toast
+	note.show(toastInfo, "first")
+	first := note.id
+	if note.expireCmd() == nil {
+		t.Fatal("expireCmd() should schedule clearing a new toast")
+	}
+	if note.expireCmd() != nil {
+		t.Error("expireCmd() should schedule each toast once")
+	}
+
+	note.show(toastError, "second")
+	note.expire(toastExpiredMsg{id: first})
+	if note.text != "second" {
+		t.Errorf("Stale expiry cleared the toast, text = %q", note.text)
+	}
+
+	note.expire(toastExpiredMsg{id: note.id})
+	if note.text != "" || note.View() != "" {
+		t.Errorf("Expected the toast to be cleared, text = %q", note.text)
+	}
+}
+
+func TestListModel_ToastRendersAndTickClears(t *testing.T) {
+	todo := &models.Todo{ID: "1", Title: "Pay rent"}
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{todo}, streak: &storage.Streak{}})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if cmd == nil {
+		t.Fatal("Expected a reload and the toast expiry to be scheduled")
+	}
+	if !strings.Contains(model.View(), "Deleted") {
+		t.Error("Expected the Deleted toast in the view")
+	}
+
+	model.Update(toastExpiredMsg{id: model.toast.id})
+	if strings.Contains(model.View(), "Deleted") {
+		t.Error("Expected the tick to clear the toast")
+	}
+}
+
+func TestListModel_StreakToast(t *testing.T) {
+	model := NewListModel(&mockStorage{}, ListOptions{})
+	model.Update(dataLoadedMsg{todos: []*models.Todo{}, streak: &storage.Streak{CurrentStreak: 2}})
+	if model.toast.text != "" {
+		t.Errorf("Expected no toast on the first load, got %q", model.toast.text)
+	}
+
+	model.Update(dataLoadedMsg{todos: []*models.Todo{}, streak: &storage.Streak{CurrentStreak: 3}})
+	if model.toast.text != "Streak +1 (3 days)" {
+		t.Errorf("Toast = %q, want %q", model.toast.text, "Streak +1 (3 days)")
+	}
+}
+
+func TestFormModel_ErrorToast(t *testing.T) {
+	model := NewFormModel(&mockStorage{})
+	model.currentField = deadlineField
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(model.View(), "title is required") {
+		t.Error("Expected the validation error as a toast in the form")
+	}
+
+	model.Update(toastExpiredMsg{id: model.toast.id})
+	if strings.Contains(model.View(), "title is required") {
+		t.Error("Expected the tick to clear the error toast")
+	}
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"github.com/akr411/doit/internal/models"
+)
+
+// maxUndo caps how many actions u can revert
+const maxUndo = 10
+
+// undoEntry reverts one action: the todo as it was before it, and the next
+// occurrence the action scheduled, if any, to delete
+type undoEntry struct {
+	action    string
+	before    models.Todo
+	spawnedID string
+}
+
+// pushUndo records the todo before action changes it, dropping the oldest
+// entry when the stack is full
+func (m *ListModel) pushUndo(action string, todo *models.Todo) {
+	m.undoStack = append(m.undoStack, undoEntry{action: action, before: *todo})
+	if len(m.undoStack) > maxUndo {
+		m.undoStack = m.undoStack[1:]
+	}
+}
+
+// popUndo drops the last entry, for actions that failed after pushing it
+func (m *ListModel) popUndo() {
+	if len(m.undoStack) > 0 {
+		m.undoStack = m.undoStack[:len(m.undoStack)-1]
+	}
+}
+
+// undo reverts the last recorded action and reports what was reverted
+func (m *ListModel) undo() {
+	if len(m.undoStack) == 0 {
+		m.toast.show(toastInfo, "Nothing to undo")
+		return
+	}
+
+	entry := m.undoStack[len(m.undoStack)-1]
+	m.popUndo()
+
+	todo := entry.before
+	if err := m.storage.UpdateTodo(&todo); err != nil {
+		m.toast.show(toastError, err.Error())
+		return
+	}
+	if entry.spawnedID != "" {
+		if err := m.storage.DeleteTodo(entry.spawnedID); err != nil {
+			m.toast.show(toastError, err.Error())
+			return
+		}
+	}
+	m.toast.show(toastInfo, "Undid "+entry.action+" of "+todo.Title)
+}
@@ -0,0 +1,37 @@
+package ui
+
+// Band is how urgently a todo's deadline is colored in the list
+type Band int
+
+const (
+	// BandNone shows the deadline date without coloring
+	BandNone Band = iota
+	// BandWarn colors the days left orange
+	BandWarn
+	// BandSoon colors the days left red, like an overdue todo
+	BandSoon
+	// BandOverdue marks a todo whose deadline has passed
+	BandOverdue
+)
+
+// Default urgency thresholds, in days
+const (
+	DefaultWarnDays = 3
+	DefaultSoonDays = 1
+)
+
+// UrgencyBand picks the band for a todo with days whole days left until its
+// deadline. Todos with fewer than soonDays days left are soon, and those with
+// at most warnDays days left are a warning.
+func UrgencyBand(days int, warnDays, soonDays int) Band {
+	switch {
+	case days < 0:
+		return BandOverdue
+	case days < soonDays:
+		return BandSoon
+	case days <= warnDays:
+		return BandWarn
+	default:
+		return BandNone
+	}
+}
@@ -0,0 +1,31 @@
+package ui
+
+import "testing"
+
+func TestUrgencyBand(t *testing.T) {
+	tests := []struct {
+		name     string
+		days     int
+		warnDays int
+		soonDays int
+		want     Band
+	}{
+		{"Overdue", -1, 3, 1, BandOverdue},
+		{"Due today", 0, 3, 1, BandSoon},
+		{"Tomorrow with defaults", 1, 3, 1, BandWarn},
+		{"Last warning day", 3, 3, 1, BandWarn},
+		{"Past the warning", 4, 3, 1, BandNone},
+		{"Wider soon band", 1, 3, 2, BandSoon},
+		{"Wider warn band", 6, 7, 1, BandWarn},
+		{"No soon band", 0, 3, 0, BandWarn},
+		{"No bands", 0, -1, 0, BandNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UrgencyBand(tt.days, tt.warnDays, tt.soonDays); got != tt.want {
+				t.Errorf("UrgencyBand(%d, %d, %d) = %v, want %v", tt.days, tt.warnDays, tt.soonDays, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseCompletionTime parses when a todo was completed, for logging work done
+// earlier. It accepts the absolute and anchored deadline formats (e.g.
+// "2025-11-15 20:00" or "today 9am") and rejects times after now.
+func ParseCompletionTime(input string, loc *time.Location, now time.Time) (time.Time, error) {
+	t, err := ParseDeadlineIn(input, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t.After(now) {
+		return time.Time{}, fmt.Errorf("completion time %s is in the future", t.Format("2006-01-02 15:04"))
+	}
+	return *t, nil
+}
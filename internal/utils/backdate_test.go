@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCompletionTime(t *testing.T) {
+	now := time.Date(2025, 11, 16, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"Past absolute", "2025-11-15 20:00", time.Date(2025, 11, 15, 20, 0, 0, 0, time.Local), false},
+		{"Exactly now", "2025-11-16 12:00", now, false},
+		{"Future", "2025-11-16 12:01", time.Time{}, true},
+		{"Invalid", "last week-ish", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCompletionTime(tt.input, time.Local, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCompletionTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseCompletionTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package utils
+
+import "time"
+
+// IsWeekend checks if the time falls on a Saturday or Sunday
+func IsWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// NextBusinessDay returns the same clock time on the next weekday after t
+func NextBusinessDay(t time.Time) time.Time {
+	next := t.AddDate(0, 0, 1)
+	for IsWeekend(next) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
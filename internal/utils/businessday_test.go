@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextBusinessDay(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			name:     "monday to tuesday",
+			from:     time.Date(2025, 11, 17, 9, 0, 0, 0, time.Local),
+			expected: time.Date(2025, 11, 18, 9, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "friday to monday",
+			from:     time.Date(2025, 11, 21, 9, 0, 0, 0, time.Local),
+			expected: time.Date(2025, 11, 24, 9, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "saturday to monday",
+			from:     time.Date(2025, 11, 22, 9, 0, 0, 0, time.Local),
+			expected: time.Date(2025, 11, 24, 9, 0, 0, 0, time.Local),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextBusinessDay(tt.from); !got.Equal(tt.expected) {
+				t.Errorf("NextBusinessDay(%v) = %v, want %v", tt.from, got, tt.expected)
+			}
+		})
+	}
+}
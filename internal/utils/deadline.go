@@ -9,125 +9,500 @@ import (
 )
 
 var (
-	monthRegex = regexp.MustCompile(`(\d+)M`)
-	unitRegex  = regexp.MustCompile(`(\d+)([mhdw])`)
+	clockRegex  = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+	offsetRegex = regexp.MustCompile(`^([+-])(\d{2}):?(\d{2})?$`)
+	// Spelled out units such as "3 hours" and the "and" between them
+	unitWordRegex = regexp.MustCompile(`(?i)(\d+)\s*(seconds?|secs?|minutes?|mins?|hours?|hrs?|days?|weeks?|months?|years?)\b`)
+	andRegex      = regexp.MustCompile(`(?i)\band\b`)
 )
 
 // ParseDeadline accepts multiple deadline formats:
 // 1. Absolute: "YYYY-MM-DD HH:MM" (e.g., "2025-11-16 14:30")
 // 2. Single units: "1d", "2h", "3w", "4m", "1M" (from now)
 // 3. Combinations: "2d 1h", "1w 2d" (from now)
+// 4. Anchored: "2025-12-01 +2d", "tomorrow 3pm" (offset or clock applied to a date)
+// 5. Zoned absolute: "2025-11-16 14:30 UTC", "2025-11-16 14:30 +02:00"
+// 6. Named times: "noon", "midnight", "tomorrow noon" (the next one from now
+// on their own)
 func ParseDeadline(input string) (*time.Time, error) {
+	return ParseDeadlineIn(input, time.Local)
+}
+
+// ParseDeadlineIn works like ParseDeadline but interprets absolute dates and
+// times without an explicit zone in the given location
+func ParseDeadlineIn(input string, loc *time.Location) (*time.Time, error) {
+	return ParseDeadlineWith(input, DeadlineOptions{Location: loc})
+}
+
+// DeadlineOptions adjusts how ParseDeadlineWith reads a deadline
+type DeadlineOptions struct {
+	// Location is where absolute dates and times without a zone are, nil
+	// for local time
+	Location *time.Location
+	// FromMidnight counts relative deadlines with days, weeks, months or
+	// years from the start of today rather than from now, so "2d" is the
+	// midnight two days ahead whatever the time is
+	FromMidnight bool
+}
+
+// ParseDeadlineWith works like ParseDeadline with the given options
+func ParseDeadlineWith(input string, opts DeadlineOptions) (*time.Time, error) {
+	return parseDeadlineAt(input, opts, time.Now())
+}
+
+func parseDeadlineAt(input string, opts DeadlineOptions, now time.Time) (*time.Time, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
 	input = strings.TrimSpace(input)
 	if input == "" {
 		return nil, fmt.Errorf("deadline cannot be empty")
 	}
 
-	if t, err := time.ParseInLocation("2006-01-02 15:04", input, time.Local); err == nil {
-		return &t, nil
+	if startsWithDate(input) {
+		if t, err := time.ParseInLocation("2006-01-02 15:04", input, loc); err == nil {
+			return &t, nil
+		}
 	}
 
-	duration, err := parseRelativeTime(input)
+	if t, ok, err := parseZoned(input); ok {
+		return t, err
+	}
+
+	if t, ok, err := parseAnchored(input, now.In(loc)); ok {
+		return t, err
+	}
+
+	if t, ok := parseNamedTime(input, now.In(loc)); ok {
+		return t, nil
+	}
+
+	from := now
+	if opts.FromMidnight && hasCalendarUnits(input) {
+		local := now.In(loc)
+		from = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	}
+
+	duration, err := parseRelativeTimeFrom(input, from)
 	if err != nil {
 		return nil, fmt.Errorf("invalid deadline format: %v\nSupported formats:\n  - Absolute: YYYY-MM-DD HH:MM (e.g., 2025-11-16 14:30)\n  - Relative: 1d, 2h, 3w, 1M (e.g., 2d 3h 20m)", err)
 	}
 
-	deadline := time.Now().Add(duration)
+	deadline := from.Add(duration)
 	return &deadline, nil
 }
 
-func parseRelativeTime(input string) (time.Duration, error) {
-	originalInput := input
+// hasCalendarUnits reports whether a relative deadline has a day, week, month
+// or year term, which FromMidnight counts from the start of today. Hours and
+// smaller units alone stay relative to now.
+func hasCalendarUnits(input string) bool {
+	if needsNormalizing(input) {
+		input = normalizeRelative(input)
+	}
+	var buf [8]relativeTerm
+	terms, _ := scanRelative(input, buf[:0])
+	for _, term := range terms {
+		switch term.unit {
+		case 'd', 'w', 'M', 'y':
+			return true
+		}
+	}
+	return false
+}
 
-	input = strings.ToLower(input)
+// parseZoned parses an absolute date and time followed by a zone, such as
+// "2025-11-16 14:30 UTC", "2025-11-16 14:30Z", "2025-11-16 14:30 -05:00" or
+// "2025-11-16 14:30 Europe/Berlin". It reports false when the input does not
+// start with an absolute date and time.
+func parseZoned(input string) (*time.Time, bool, error) {
+	const layout = "2006-01-02 15:04"
+	if len(input) <= len(layout) || !startsWithDate(input) {
+		return nil, false, nil
+	}
 
-	months := 0
+	if _, err := time.Parse(layout, input[:len(layout)]); err != nil {
+		return nil, false, nil
+	}
+
+	zone := strings.TrimSpace(input[len(layout):])
+	loc, err := ParseLocation(zone)
+	if err != nil {
+		// Leave it to the anchored parser, e.g. "2025-12-01 09:00 +1h"
+		return nil, false, nil
+	}
+
+	t, err := time.ParseInLocation(layout, input[:len(layout)], loc)
+	return &t, true, err
+}
+
+// ParseLocation resolves a zone name such as "UTC", "Z", "+02:00" or an IANA
+// name like "America/New_York"
+func ParseLocation(zone string) (*time.Location, error) {
+	switch strings.ToUpper(zone) {
+	case "":
+		return nil, fmt.Errorf("timezone cannot be empty")
+	case "Z", "UTC", "GMT":
+		return time.UTC, nil
+	case "LOCAL":
+		return time.Local, nil
+	}
+
+	if match := offsetRegex.FindStringSubmatch(zone); match != nil {
+		hours, _ := strconv.Atoi(match[2])
+		minutes, _ := strconv.Atoi(match[3])
+		if hours > 14 || minutes > 59 {
+			return nil, fmt.Errorf("invalid timezone offset: %s", zone)
+		}
+		offset := hours*3600 + minutes*60
+		if match[1] == "-" {
+			offset = -offset
+		}
+		return time.FixedZone(zone, offset), nil
+	}
 
-	monthMatches := monthRegex.FindAllStringSubmatch(originalInput, -1)
-	for _, match := range monthMatches {
-		value, err := strconv.Atoi(match[1])
+	if strings.Contains(zone, "/") {
+		loc, err := time.LoadLocation(zone)
 		if err != nil {
-			return 0, fmt.Errorf("invalid number, %s", match[1])
+			return nil, fmt.Errorf("unknown timezone: %s", zone)
 		}
-		if value <= 0 {
-			return 0, fmt.Errorf("time values must be postivie")
+		return loc, nil
+	}
+
+	return nil, fmt.Errorf("unknown timezone: %s (use UTC, an offset like +02:00 or a name like Europe/Berlin)", zone)
+}
+
+// parseAnchored splits the input into a leading anchor (an absolute date or
+// "today"/"tomorrow") and a trailing offset or clock time. It reports false
+// when the input does not start with an anchor followed by a remainder.
+func parseAnchored(input string, now time.Time) (*time.Time, bool, error) {
+	anchor, remainder, ok := splitAnchor(input, now)
+	if !ok {
+		return nil, false, nil
+	}
+
+	remainder = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(remainder), "+"))
+	if remainder == "" {
+		return nil, true, fmt.Errorf("missing offset or time after the date")
+	}
+
+	if hour, minute, ok := parseClock(remainder); ok {
+		t := time.Date(anchor.Year(), anchor.Month(), anchor.Day(), hour, minute, 0, 0, now.Location())
+		return &t, true, nil
+	}
+
+	duration, err := parseRelativeTimeFrom(remainder, anchor)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid offset %q: %v (use a clock time like 3pm or 15:00, or units like 2d 3h)", remainder, err)
+	}
+
+	t := anchor.Add(duration)
+	return &t, true, nil
+}
+
+// splitAnchor returns the anchor time and the text following it
+func splitAnchor(input string, now time.Time) (time.Time, string, bool) {
+	lower := strings.ToLower(input)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	for word, days := range map[string]int{"today": 0, "tomorrow": 1} {
+		if rest, found := strings.CutPrefix(lower, word); found && rest != "" && (rest[0] == ' ' || rest[0] == '+') {
+			return today.AddDate(0, 0, days), input[len(word):], true
 		}
-		months += value
 	}
 
-	processedInput := monthRegex.ReplaceAllString(originalInput, "")
-	processedInput = strings.ToLower(processedInput)
+	if !startsWithDate(input) {
+		return time.Time{}, "", false
+	}
+
+	if len(input) > len("2006-01-02 15:04") {
+		if t, err := time.ParseInLocation("2006-01-02 15:04", input[:16], now.Location()); err == nil {
+			return t, input[16:], true
+		}
+	}
+
+	if len(input) > len("2006-01-02") {
+		if t, err := time.ParseInLocation("2006-01-02", input[:10], now.Location()); err == nil {
+			return t, input[10:], true
+		}
+	}
+
+	return time.Time{}, "", false
+}
+
+// namedTimes are the clock times that can be given by name
+var namedTimes = map[string]int{"noon": 12, "midnight": 0}
+
+// parseNamedTime resolves "noon" or "midnight" on its own to the next one
+// after now: noon today until it has passed, and the midnight that starts
+// tomorrow
+func parseNamedTime(input string, now time.Time) (*time.Time, bool) {
+	hour, ok := namedTimes[strings.ToLower(input)]
+	if !ok {
+		return nil, false
+	}
+
+	t := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, true
+}
+
+// startsWithDate is a cheap check for a leading YYYY-MM-DD, which every
+// absolute format needs, so relative input doesn't go through time.Parse
+func startsWithDate(input string) bool {
+	return len(input) >= len("2006-01-02") && input[4] == '-' && input[7] == '-'
+}
+
+// parseClock parses a clock time such as "3pm", "3:30pm", "15:00", "noon"
+// or "midnight", the start of the day
+func parseClock(input string) (int, int, bool) {
+	if hour, ok := namedTimes[strings.ToLower(input)]; ok {
+		return hour, 0, true
+	}
+
+	match := clockRegex.FindStringSubmatch(strings.ToLower(input))
+	if match == nil || (match[2] == "" && match[3] == "") {
+		return 0, 0, false
+	}
+
+	hour, _ := strconv.Atoi(match[1])
+	minute := 0
+	if match[2] != "" {
+		minute, _ = strconv.Atoi(match[2])
+	}
+	if minute > 59 {
+		return 0, 0, false
+	}
+
+	switch match[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if match[3] == "pm" {
+			hour += 12
+		}
+	default:
+		if hour > 23 {
+			return 0, 0, false
+		}
+	}
+
+	return hour, minute, true
+}
+
+// ParseLeadTime parses how long before a deadline to be reminded, in the
+// relative deadline format such as "1h" or "2d 3h"
+func ParseLeadTime(input string) (time.Duration, error) {
+	lead, err := parseRelativeTime(strings.TrimSpace(input))
+	if err != nil {
+		return 0, err
+	}
+	if lead <= 0 {
+		return 0, fmt.Errorf("reminder lead time must be positive")
+	}
+	return lead, nil
+}
+
+func parseRelativeTime(input string) (time.Duration, error) {
+	return parseRelativeTimeFrom(input, time.Now())
+}
+
+// relativeTerm is one "<number><unit>" term of a relative deadline, where
+// unit is one of s, m, h, d, w, M (months) or y
+type relativeTerm struct {
+	digits string
+	unit   byte
+}
+
+// scanRelative splits input into terms in a single pass without copying it.
+// Units are case-insensitive except for M (months), which is told apart from
+// m (minutes). It reports false when anything other than terms, spaces and
+// tabs is found; the terms around such characters are still returned, so
+// errors are reported in the same order as for well-formed input.
+func scanRelative(input string, terms []relativeTerm) ([]relativeTerm, bool) {
+	valid := true
+	for i := 0; i < len(input); {
+		c := input[i]
+		if c == ' ' || c == '\t' {
+			i++
+			continue
+		}
+		if c < '0' || c > '9' {
+			valid = false
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(input) && input[j] >= '0' && input[j] <= '9' {
+			j++
+		}
+		if j < len(input) {
+			if unit, ok := relativeUnit(input[j]); ok {
+				terms = append(terms, relativeTerm{digits: input[i:j], unit: unit})
+				i = j + 1
+				continue
+			}
+		}
+		valid = false
+		i = j
+	}
+	return terms, valid
+}
 
-	matches := unitRegex.FindAllStringSubmatch(processedInput, -1)
-	if len(matches) == 0 && months == 0 {
-		return 0, fmt.Errorf("no valid time units found (use: m, h, d, w, M)")
+// relativeUnit maps a unit character to its canonical unit
+func relativeUnit(c byte) (byte, bool) {
+	if c == 'M' {
+		return c, true
+	}
+	if c >= 'A' && c <= 'Z' {
+		c += 'a' - 'A'
 	}
+	switch c {
+	case 's', 'm', 'h', 'd', 'w', 'y':
+		return c, true
+	}
+	return 0, false
+}
 
-	reconstructed := ""
-	for _, match := range matches {
-		reconstructed += match[0]
+// parseRelativeTimeFrom parses a relative duration, resolving months against
+// the given start time
+func parseRelativeTimeFrom(input string, from time.Time) (time.Duration, error) {
+	if needsNormalizing(input) {
+		input = normalizeRelative(input)
 	}
-	for i := 0; i < months; i++ {
-		reconstructed += "M"
+
+	// Enough for any realistic input without allocating
+	var buf [8]relativeTerm
+	terms, valid := scanRelative(input, buf[:0])
+
+	// Months and years are calendar units, so they are added with AddDate
+	months := 0
+	for _, term := range terms {
+		if term.unit != 'M' {
+			continue
+		}
+		value, err := strconv.Atoi(term.digits)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number, %s", term.digits)
+		}
+		if value <= 0 {
+			return 0, fmt.Errorf("time values must be postivie")
+		}
+		months += value
 	}
 
-	inputNoSpace := strings.ReplaceAll(strings.ReplaceAll(originalInput, " ", ""), "\t", "")
-	inputNoSpace = strings.ToLower(inputNoSpace)
-	for _, match := range monthMatches {
-		inputNoSpace = strings.Replace(inputNoSpace, strings.ToLower(match[0]), "M", 1)
+	years := 0
+	for _, term := range terms {
+		if term.unit != 'y' {
+			continue
+		}
+		value, err := strconv.Atoi(term.digits)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number, %s", term.digits)
+		}
+		if value <= 0 {
+			return 0, fmt.Errorf("time values must be positive")
+		}
+		years += value
 	}
-	reconstructedNoSpaces := strings.ToLower(reconstructed)
 
-	if len(reconstructedNoSpaces) != len(inputNoSpace) {
+	if len(terms) == 0 {
+		return 0, fmt.Errorf("no valid time units found (use: s, m, h, d, w, M, y)")
+	}
+	if !valid {
 		return 0, fmt.Errorf("contains invalid characters or format")
 	}
 
 	var totalDuration time.Duration
-	for _, match := range matches {
-		value, err := strconv.Atoi(match[1])
+	for _, term := range terms {
+		if term.unit == 'M' || term.unit == 'y' {
+			continue
+		}
+		value, err := strconv.Atoi(term.digits)
 		if err != nil {
-			return 0, fmt.Errorf("invalid number: %s", match[1])
+			return 0, fmt.Errorf("invalid number: %s", term.digits)
 		}
 
 		if value <= 0 {
 			return 0, fmt.Errorf("time values must be positive")
 		}
 
-		unit := match[2]
-
-		unitDuration, err := parseTimeUnit(value, unit)
+		unitDuration, err := parseTimeUnit(value, term.unit)
 		if err != nil {
 			return 0, err
 		}
 		totalDuration += unitDuration
 	}
 
-	if months > 0 {
-		now := time.Now()
-		targetTime := now.AddDate(0, months, 0)
-		monthsDuration := targetTime.Sub(now)
-		totalDuration += monthsDuration
+	if months > 0 || years > 0 {
+		targetTime := from.AddDate(years, months, 0)
+		calendarDuration := targetTime.Sub(from)
+		totalDuration += calendarDuration
 	}
 
-	if totalDuration <= 0 && months == 0 {
+	if totalDuration <= 0 && months == 0 && years == 0 {
 		return 0, fmt.Errorf("total duration must be positive")
 	}
 
 	return totalDuration, nil
 }
 
-func parseTimeUnit(value int, unit string) (time.Duration, error) {
+// unitLetters maps the first letters of spelled out units to the unit letters
+// the relative parser understands
+var unitLetters = map[string]string{
+	"se": "s", "mi": "m", "ho": "h", "hr": "h", "da": "d", "we": "w", "mo": "M", "ye": "y",
+}
+
+// needsNormalizing checks for commas or words, which the compact "2d 3h" form
+// never contains, so normalizeRelative only runs when it can change something
+func needsNormalizing(input string) bool {
+	for i := 0; i < len(input); i++ {
+		if input[i] == ',' {
+			return true
+		}
+		if i > 0 && isASCIILetter(input[i]) && isASCIILetter(input[i-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// normalizeRelative rewrites "2 days and 3 hours" or "2d, 3h" to "2d 3h".
+// Anything it does not recognize is left alone, so malformed input such as
+// "2 dayz" is still rejected.
+func normalizeRelative(input string) string {
+	input = strings.ReplaceAll(input, ",", " ")
+	input = andRegex.ReplaceAllString(input, " ")
+	return unitWordRegex.ReplaceAllStringFunc(input, func(match string) string {
+		parts := unitWordRegex.FindStringSubmatch(match)
+		return parts[1] + unitLetters[strings.ToLower(parts[2][:2])]
+	})
+}
+
+func parseTimeUnit(value int, unit byte) (time.Duration, error) {
 	switch unit {
-	case "m":
+	case 's':
+		return time.Duration(value) * time.Second, nil
+	case 'm':
 		return time.Duration(value) * time.Minute, nil
-	case "h":
+	case 'h':
 		return time.Duration(value) * time.Hour, nil
-	case "d":
+	case 'd':
 		return time.Duration(value) * 24 * time.Hour, nil
-	case "w":
+	case 'w':
 		return time.Duration(value) * 7 * 24 * time.Hour, nil
 	default:
-		return 0, fmt.Errorf("invalid time unit: %s (use: m, h, d, w, M)", unit)
+		return 0, fmt.Errorf("invalid time unit: %c (use: m, h, d, w, M)", unit)
 	}
 }
 
@@ -135,11 +510,17 @@ func parseTimeUnit(value int, unit string) (time.Duration, error) {
 func FormatDeadlineHelp() string {
 	return `Deadline formats:
 	- Absolute: YYYY-MM-DD HH:MM (e.g., 2025-11-16 14:30)
+	- Zoned: YYYY-MM-DD HH:MM ZONE (e.g., 2025-11-16 14:30 UTC, +02:00)
 	- Relative units:
+		• s: seconds (30s = 30 seconds from now)
 		• m: minutes (30m = 30 minutes from now)
 		• h: hours (2h = 2 hours from now)
 		• d: days (1d = 1 day from now)
 		• w: weeks (2w = 2 weeks from now)
 		• M: months (1M = 1 month from now)
-	- Combinations: 2d 3h 30m (2days, 3hours, 30 minutes from now)`
+		• y: years (1y = 1 year from now)
+	- Combinations: 2d 3h 30m (2days, 3hours, 30 minutes from now)
+	- Spelled out: 2 days and 3 hours, 2d, 3h
+	- Anchored: 2025-12-01 +2d, tomorrow 3pm (offset or time from a date)
+	- Named times: noon, midnight, tomorrow noon (the next one on their own)`
 }
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// DateFormatEnv is the environment variable holding the date format setting
+const DateFormatEnv = "DOIT_DATE_FORMAT"
+
+// FormatOpts controls how times are rendered
+type FormatOpts struct {
+	Clock24  bool
+	DayFirst bool
+}
+
+// FormatTime formats a time using the given options, e.g. "Jan 2, 3:04 PM"
+// or "2 Jan, 15:04"
+func FormatTime(t time.Time, opts FormatOpts) string {
+	date := "Jan 2"
+	if opts.DayFirst {
+		date = "2 Jan"
+	}
+
+	return t.Format(date) + ", " + FormatClock(t, opts)
+}
+
+// FormatDate formats the day of a time with its weekday using the given
+// options, e.g. "Monday, Jan 2" or "Monday, 2 Jan"
+func FormatDate(t time.Time, opts FormatOpts) string {
+	if opts.DayFirst {
+		return t.Format("Monday, 2 Jan")
+	}
+	return t.Format("Monday, Jan 2")
+}
+
+// FormatClock formats the time of day using the given options, e.g.
+// "3:04 PM" or "15:04"
+func FormatClock(t time.Time, opts FormatOpts) string {
+	if opts.Clock24 {
+		return t.Format("15:04")
+	}
+	return t.Format("3:04 PM")
+}
+
+// ParseFormatOpts parses a comma separated format setting such as
+// "24h,day-first". Accepted values are 12h, 24h, day-first and month-first.
+func ParseFormatOpts(value string) (FormatOpts, error) {
+	var opts FormatOpts
+
+	for part := range strings.SplitSeq(value, ",") {
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "":
+		case "12h":
+			opts.Clock24 = false
+		case "24h":
+			opts.Clock24 = true
+		case "day-first":
+			opts.DayFirst = true
+		case "month-first":
+			opts.DayFirst = false
+		default:
+			return FormatOpts{}, fmt.Errorf("unknown date format option: %s (use: 12h, 24h, day-first, month-first)", part)
+		}
+	}
+
+	return opts, nil
+}
+
+// CheckFormatEnv reports an invalid DOIT_DATE_FORMAT, which
+// FormatOptsFromEnv ignores
+func CheckFormatEnv() error {
+	if _, err := ParseFormatOpts(os.Getenv(DateFormatEnv)); err != nil {
+		return fmt.Errorf("%s: %w", DateFormatEnv, err)
+	}
+	return nil
+}
+
+// FormatOptsFromEnv reads the format options from DOIT_DATE_FORMAT, falling
+// back to the defaults when it is unset or invalid
+func FormatOptsFromEnv() FormatOpts {
+	opts, err := ParseFormatOpts(os.Getenv(DateFormatEnv))
+	if err != nil {
+		return FormatOpts{}
+	}
+	return opts
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTime(t *testing.T) {
+	tm := time.Date(2025, 11, 16, 14, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		opts     FormatOpts
+		expected string
+	}{
+		{
+			name:     "12h month-first",
+			opts:     FormatOpts{},
+			expected: "Nov 16, 2:30 PM",
+		},
+		{
+			name:     "24h month-first",
+			opts:     FormatOpts{Clock24: true},
+			expected: "Nov 16, 14:30",
+		},
+		{
+			name:     "12h day-first",
+			opts:     FormatOpts{DayFirst: true},
+			expected: "16 Nov, 2:30 PM",
+		},
+		{
+			name:     "24h day-first",
+			opts:     FormatOpts{Clock24: true, DayFirst: true},
+			expected: "16 Nov, 14:30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatTime(tm, tt.opts); got != tt.expected {
+				t.Errorf("FormatTime() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseFormatOpts(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  FormatOpts
+		wantError bool
+	}{
+		{
+			name:     "empty uses defaults",
+			input:    "",
+			expected: FormatOpts{},
+		},
+		{
+			name:     "24h day-first",
+			input:    "24h,day-first",
+			expected: FormatOpts{Clock24: true, DayFirst: true},
+		},
+		{
+			name:     "case and spaces ignored",
+			input:    " 24H , Month-First ",
+			expected: FormatOpts{Clock24: true},
+		},
+		{
+			name:      "unknown option",
+			input:     "36h",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormatOpts(tt.input)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("ParseFormatOpts(%q) expected error but got nil", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ParseFormatOpts(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseFormatOpts(%q) = %+v, want %+v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	tm := time.Date(2025, 11, 16, 14, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		opts     FormatOpts
+		expected string
+	}{
+		{
+			name:     "month-first",
+			opts:     FormatOpts{},
+			expected: "Sunday, Nov 16",
+		},
+		{
+			name:     "day-first",
+			opts:     FormatOpts{DayFirst: true},
+			expected: "Sunday, 16 Nov",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDate(tm, tt.opts); got != tt.expected {
+				t.Errorf("FormatDate() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckFormatEnv(t *testing.T) {
+	t.Setenv(DateFormatEnv, "24h,day-first")
+	if err := CheckFormatEnv(); err != nil {
+		t.Errorf("CheckFormatEnv() error = %v, want nil", err)
+	}
+
+	t.Setenv(DateFormatEnv, "36h")
+	if err := CheckFormatEnv(); err == nil {
+		t.Error("CheckFormatEnv() expected error but got nil")
+	}
+}
@@ -0,0 +1,35 @@
+package utils
+
+import "time"
+
+// NudgeDeadline moves a deadline by delta. Unless allowPast is set, a result
+// before now is clamped to now.
+func NudgeDeadline(t time.Time, delta time.Duration, allowPast bool) time.Time {
+	return nudgeDeadlineFrom(t, delta, allowPast, time.Now())
+}
+
+func nudgeDeadlineFrom(t time.Time, delta time.Duration, allowPast bool, now time.Time) time.Time {
+	nudged := t.Add(delta)
+	if !allowPast && nudged.Before(now) {
+		return now.In(t.Location())
+	}
+	return nudged
+}
+
+// DefaultMorningHour is the hour TomorrowMorning uses unless configured
+const DefaultMorningHour = 9
+
+// SnoozeUntil returns the deadline a todo snoozed by the given duration gets:
+// by after now, or the end of today (23:59) when by is 0
+func SnoozeUntil(now time.Time, by time.Duration) time.Time {
+	if by > 0 {
+		return now.Add(by)
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 0, 0, now.Location())
+}
+
+// TomorrowMorning returns the start of the given hour on the day after now, in
+// now's location
+func TomorrowMorning(now time.Time, hour int) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day()+1, hour, 0, 0, 0, now.Location())
+}
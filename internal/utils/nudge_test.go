@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNudgeDeadline(t *testing.T) {
+	now := time.Date(2025, 11, 16, 12, 0, 0, 0, time.UTC)
+	deadline := time.Date(2025, 11, 18, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		deadline  time.Time
+		delta     time.Duration
+		allowPast bool
+		want      time.Time
+	}{
+		{"increment a day", deadline, 24 * time.Hour, false, deadline.AddDate(0, 0, 1)},
+		{"decrement a day", deadline, -24 * time.Hour, false, deadline.AddDate(0, 0, -1)},
+		{"increment an hour", deadline, time.Hour, false, deadline.Add(time.Hour)},
+		{"decrement an hour", deadline, -time.Hour, false, deadline.Add(-time.Hour)},
+		{"clamped to now", deadline, -3 * 24 * time.Hour, false, now},
+		{"past allowed", deadline, -3 * 24 * time.Hour, true, deadline.AddDate(0, 0, -3)},
+		{"overdue still in the past is clamped", now.Add(-48 * time.Hour), 24 * time.Hour, false, now},
+		{"exactly now is kept", now.Add(time.Hour), -time.Hour, false, now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nudgeDeadlineFrom(tt.deadline, tt.delta, tt.allowPast, now)
+			if !got.Equal(tt.want) {
+				t.Errorf("NudgeDeadline(%v, %v, %v) = %v, want %v", tt.deadline, tt.delta, tt.allowPast, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnoozeUntil(t *testing.T) {
+	now := time.Date(2025, 11, 16, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		by   time.Duration
+		want time.Time
+	}{
+		{"a day", 24 * time.Hour, time.Date(2025, 11, 17, 12, 30, 0, 0, time.UTC)},
+		{"two hours", 2 * time.Hour, time.Date(2025, 11, 16, 14, 30, 0, 0, time.UTC)},
+		{"end of today", 0, time.Date(2025, 11, 16, 23, 59, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SnoozeUntil(now, tt.by); !got.Equal(tt.want) {
+				t.Errorf("SnoozeUntil(%v) = %v, want %v", tt.by, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTomorrowMorning(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		hour int
+		want time.Time
+	}{
+		{"Early morning", time.Date(2025, 11, 16, 0, 5, 0, 0, time.UTC), 9, time.Date(2025, 11, 17, 9, 0, 0, 0, time.UTC)},
+		{"After the hour", time.Date(2025, 11, 16, 14, 30, 0, 0, time.UTC), 9, time.Date(2025, 11, 17, 9, 0, 0, 0, time.UTC)},
+		{"Late night", time.Date(2025, 11, 16, 23, 59, 59, 0, time.UTC), 7, time.Date(2025, 11, 17, 7, 0, 0, 0, time.UTC)},
+		{"End of month", time.Date(2025, 11, 30, 12, 0, 0, 0, time.UTC), 9, time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)},
+		{"End of year", time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC), 9, time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TomorrowMorning(tt.now, tt.hour); !got.Equal(tt.want) {
+				t.Errorf("TomorrowMorning(%v, %d) = %v, want %v", tt.now, tt.hour, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Period is a calendar range deadlines can be filtered by
+type Period int
+
+const (
+	// PeriodNone matches no range
+	PeriodNone Period = iota
+	// PeriodWeek is the Monday to Sunday week
+	PeriodWeek
+	// PeriodMonth is the calendar month
+	PeriodMonth
+)
+
+// String returns how the period is shown in the list, e.g. "this week"
+func (p Period) String() string {
+	switch p {
+	case PeriodWeek:
+		return "this week"
+	case PeriodMonth:
+		return "this month"
+	}
+	return ""
+}
+
+// PeriodBounds returns the start and exclusive end of the period containing
+// now, in local time. Weeks start on Monday.
+func PeriodBounds(period Period, now time.Time) (start, end time.Time) {
+	now = now.In(time.Local)
+	year, month, day := now.Date()
+	switch period {
+	case PeriodWeek:
+		// Days since Monday, with Sunday as the last day of the week
+		offset := (int(now.Weekday()) + 6) % 7
+		start = time.Date(year, month, day-offset, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(0, 0, 7)
+	case PeriodMonth:
+		start = time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(0, 1, 0)
+	}
+	return time.Time{}, time.Time{}
+}
+
+// InPeriod checks if t falls within the period containing now
+func InPeriod(t time.Time, period Period, now time.Time) bool {
+	if period == PeriodNone {
+		return false
+	}
+	start, end := PeriodBounds(period, now)
+	return !t.Before(start) && t.Before(end)
+}
+
+// ParseDay parses a calendar day given as YYYY-MM-DD, today, tomorrow or
+// yesterday, returning its local midnight
+func ParseDay(input string, now time.Time) (time.Time, error) {
+	now = now.In(time.Local)
+	year, month, day := now.Date()
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "today":
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local), nil
+	case "tomorrow":
+		return time.Date(year, month, day+1, 0, 0, 0, 0, time.Local), nil
+	case "yesterday":
+		return time.Date(year, month, day-1, 0, 0, 0, 0, time.Local), nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(input), time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid day %q (use YYYY-MM-DD, today, tomorrow or yesterday)", input)
+	}
+	return t, nil
+}
+
+// ParseWeekday parses the English name of a weekday or its first three
+// letters, ignoring case
+func ParseWeekday(input string) (time.Weekday, error) {
+	name := strings.ToLower(strings.TrimSpace(input))
+	if len(name) >= 3 {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			full := strings.ToLower(day.String())
+			if name == full || name == full[:3] {
+				return day, nil
+			}
+		}
+	}
+	return time.Sunday, fmt.Errorf("invalid weekday %q (use e.g. monday or mon)", input)
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInPeriod(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 11, 19, 15, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name   string
+		t      time.Time
+		period Period
+		want   bool
+	}{
+		{"Week start Monday midnight", time.Date(2025, 11, 17, 0, 0, 0, 0, time.Local), PeriodWeek, true},
+		{"Sunday before the week", time.Date(2025, 11, 16, 23, 59, 0, 0, time.Local), PeriodWeek, false},
+		{"Sunday end of week", time.Date(2025, 11, 23, 23, 59, 0, 0, time.Local), PeriodWeek, true},
+		{"Next Monday", time.Date(2025, 11, 24, 0, 0, 0, 0, time.Local), PeriodWeek, false},
+		{"Month start", time.Date(2025, 11, 1, 0, 0, 0, 0, time.Local), PeriodMonth, true},
+		{"Last month", time.Date(2025, 10, 31, 23, 59, 0, 0, time.Local), PeriodMonth, false},
+		{"Month end", time.Date(2025, 11, 30, 23, 59, 0, 0, time.Local), PeriodMonth, true},
+		{"Next month", time.Date(2025, 12, 1, 0, 0, 0, 0, time.Local), PeriodMonth, false},
+		{"No period", now, PeriodNone, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InPeriod(tt.t, tt.period, now); got != tt.want {
+				t.Errorf("InPeriod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriodBounds_SundayBelongsToEndingWeek(t *testing.T) {
+	sunday := time.Date(2025, 11, 23, 10, 0, 0, 0, time.Local)
+
+	start, end := PeriodBounds(PeriodWeek, sunday)
+
+	wantStart := time.Date(2025, 11, 17, 0, 0, 0, 0, time.Local)
+	if !start.Equal(wantStart) || !end.Equal(wantStart.AddDate(0, 0, 7)) {
+		t.Errorf("PeriodBounds() = %v, %v, want week starting %v", start, end, wantStart)
+	}
+}
+
+func TestParseDay(t *testing.T) {
+	now := time.Date(2025, 11, 30, 22, 15, 0, 0, time.Local)
+
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"today", time.Date(2025, 11, 30, 0, 0, 0, 0, time.Local), false},
+		{"Tomorrow", time.Date(2025, 12, 1, 0, 0, 0, 0, time.Local), false},
+		{"yesterday", time.Date(2025, 11, 29, 0, 0, 0, 0, time.Local), false},
+		{"2025-11-16", time.Date(2025, 11, 16, 0, 0, 0, 0, time.Local), false},
+		{"16/11/2025", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDay(tt.input, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDay(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDay(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Weekday
+		wantErr bool
+	}{
+		{"monday", time.Monday, false},
+		{"Sunday", time.Sunday, false},
+		{"sat", time.Saturday, false},
+		{" WED ", time.Wednesday, false},
+		{"mo", time.Sunday, true},
+		{"someday", time.Sunday, true},
+		{"", time.Sunday, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseWeekday(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseWeekday(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseWeekday(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// QuietHoursEnv is the environment variable holding the quiet hours window,
+// e.g. "22:00-07:00"
+const QuietHoursEnv = "DOIT_QUIET_HOURS"
+
+// InQuietHours reports whether now falls inside the window from start
+// (inclusive) to end (exclusive), both given as "HH:MM". A window whose end is
+// before its start spans midnight. Equal or invalid bounds never match.
+func InQuietHours(now time.Time, start, end string) bool {
+	from, ok := minuteOfDay(start)
+	if !ok {
+		return false
+	}
+	to, ok := minuteOfDay(end)
+	if !ok {
+		return false
+	}
+
+	current := now.Hour()*60 + now.Minute()
+	if from <= to {
+		return current >= from && current < to
+	}
+	return current >= from || current < to
+}
+
+// QuietHoursFromEnv reads the quiet hours window from DOIT_QUIET_HOURS,
+// returning empty bounds when it is unset or malformed
+func QuietHoursFromEnv() (start, end string) {
+	start, end, ok := strings.Cut(os.Getenv(QuietHoursEnv), "-")
+	if !ok {
+		return "", ""
+	}
+	return strings.TrimSpace(start), strings.TrimSpace(end)
+}
+
+func minuteOfDay(clock string) (int, bool) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInQuietHours(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 11, 16, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		start, end string
+		want       bool
+	}{
+		{"within a day inside", at(13, 30), "12:00", "14:00", true},
+		{"within a day before", at(11, 59), "12:00", "14:00", false},
+		{"within a day at start", at(12, 0), "12:00", "14:00", true},
+		{"within a day at end", at(14, 0), "12:00", "14:00", false},
+		{"spanning midnight late evening", at(23, 15), "22:00", "07:00", true},
+		{"spanning midnight early morning", at(3, 0), "22:00", "07:00", true},
+		{"spanning midnight at midnight", at(0, 0), "22:00", "07:00", true},
+		{"spanning midnight at start", at(22, 0), "22:00", "07:00", true},
+		{"spanning midnight just before start", at(21, 59), "22:00", "07:00", false},
+		{"spanning midnight at end", at(7, 0), "22:00", "07:00", false},
+		{"spanning midnight just before end", at(6, 59), "22:00", "07:00", true},
+		{"spanning midnight midday", at(12, 0), "22:00", "07:00", false},
+		{"empty window", at(12, 0), "12:00", "12:00", false},
+		{"invalid start", at(12, 0), "noon", "14:00", false},
+		{"unset", at(12, 0), "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InQuietHours(tt.now, tt.start, tt.end); got != tt.want {
+				t.Errorf("InQuietHours(%s, %q, %q) = %v, want %v", tt.now.Format("15:04"), tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuietHoursFromEnv(t *testing.T) {
+	tests := []struct {
+		value      string
+		start, end string
+	}{
+		{"22:00-07:00", "22:00", "07:00"},
+		{" 22:00 - 07:00 ", "22:00", "07:00"},
+		{"", "", ""},
+		{"22:00", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Setenv(QuietHoursEnv, tt.value)
+		start, end := QuietHoursFromEnv()
+		if start != tt.start || end != tt.end {
+			t.Errorf("QuietHoursFromEnv() with %q = %q, %q, want %q, %q", tt.value, start, end, tt.start, tt.end)
+		}
+	}
+}
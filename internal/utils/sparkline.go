@@ -0,0 +1,23 @@
+package utils
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws one block per value, scaled so the largest value is a full
+// block and zero is the lowest one. All zero values give a flat line.
+func Sparkline(values []int) string {
+	highest := 0
+	for _, v := range values {
+		highest = max(highest, v)
+	}
+
+	line := make([]rune, len(values))
+	for i, v := range values {
+		level := 0
+		if highest > 0 && v > 0 {
+			// Round to the nearest level
+			level = (v*(len(sparkBlocks)-1) + highest/2) / highest
+		}
+		line[i] = sparkBlocks[level]
+	}
+	return string(line)
+}
@@ -0,0 +1,27 @@
+package utils
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"all zero", []int{0, 0, 0, 0, 0, 0, 0}, "▁▁▁▁▁▁▁"},
+		{"single spike", []int{0, 0, 0, 4, 0, 0, 0}, "▁▁▁█▁▁▁"},
+		{"every level", []int{0, 1, 2, 3, 4, 5, 6, 7}, "▁▂▃▄▅▆▇█"},
+		{"scaled", []int{1, 2, 4, 8, 4, 2, 0}, "▂▃▅█▅▃▁"},
+		{"equal values", []int{3, 3, 3}, "███"},
+		{"negative as zero", []int{-2, 2}, "▁█"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values); got != tt.want {
+				t.Errorf("Sparkline(%v) = %q, want %q", tt.values, got, tt.want)
+			}
+		})
+	}
+}
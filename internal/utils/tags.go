@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+)
+
+// TagSeparator splits hierarchical tags such as "work/clientA"
+const TagSeparator = "/"
+
+// ParseTags splits a comma separated tag list, dropping blanks and
+// duplicates. A tag in double quotes may contain commas, and the parts of a
+// hierarchical tag are trimmed, so ` "next quarter", work / clientA ` gives
+// "next quarter" and "work/clientA".
+func ParseTags(input string) []string {
+	var result []string
+	add := func(tag string) {
+		if tag = normalizeTag(tag); tag != "" && !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+
+	var current strings.Builder
+	quoted := false
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			add(current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	add(current.String())
+
+	return result
+}
+
+// normalizeTag trims a tag and each level of a hierarchical tag, dropping
+// empty levels
+func normalizeTag(tag string) string {
+	var levels []string
+	for level := range strings.SplitSeq(tag, TagSeparator) {
+		if level = strings.Join(strings.Fields(level), " "); level != "" {
+			levels = append(levels, level)
+		}
+	}
+	return strings.Join(levels, TagSeparator)
+}
+
+// TagMatchesFilter reports whether tag is the filter tag or nested under it,
+// so the filter "work" matches "work" and "work/clientA" but not "workshop"
+func TagMatchesFilter(tag, filter string) bool {
+	return tag == filter || strings.HasPrefix(tag, filter+TagSeparator)
+}
+
+// TagAncestors returns a hierarchical tag and every tag above it, from the
+// top level down, so "work/clientA" gives "work" and "work/clientA"
+func TagAncestors(tag string) []string {
+	var ancestors []string
+	for i, r := range tag {
+		if string(r) == TagSeparator {
+			ancestors = append(ancestors, tag[:i])
+		}
+	}
+	return append(ancestors, tag)
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"empty", "", nil},
+		{"single", "work", []string{"work"}},
+		{"trims and drops blanks", " work , ,home ", []string{"work", "home"}},
+		{"removes duplicates", "work,home,work", []string{"work", "home"}},
+		{"multi-word", "next quarter", []string{"next quarter"}},
+		{"quoted multi-word", `"next quarter",work`, []string{"next quarter", "work"}},
+		{"quoted comma", `"Smith, John", work`, []string{"Smith, John", "work"}},
+		{"quoted spaces collapsed", `"  next   quarter "`, []string{"next quarter"}},
+		{"unterminated quote", `"next quarter, work`, []string{"next quarter, work"}},
+		{"hierarchical", "work/clientA", []string{"work/clientA"}},
+		{"hierarchical trimmed", " work / clientA /", []string{"work/clientA"}},
+		{"hierarchical duplicate", "work/clientA, work / clientA", []string{"work/clientA"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseTags(tt.input); !slices.Equal(got, tt.want) {
+				t.Errorf("ParseTags(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagMatchesFilter(t *testing.T) {
+	tests := []struct {
+		tag    string
+		filter string
+		want   bool
+	}{
+		{"work", "work", true},
+		{"work/clientA", "work", true},
+		{"work/clientA/billing", "work", true},
+		{"work/clientA/billing", "work/clientA", true},
+		{"work/clientA", "work/clientB", false},
+		{"workshop", "work", false},
+		{"work", "work/clientA", false},
+		{"home", "work", false},
+	}
+
+	for _, tt := range tests {
+		if got := TagMatchesFilter(tt.tag, tt.filter); got != tt.want {
+			t.Errorf("TagMatchesFilter(%q, %q) = %v, want %v", tt.tag, tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestTagAncestors(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{"work", []string{"work"}},
+		{"work/clientA", []string{"work", "work/clientA"}},
+		{"a/b/c", []string{"a", "a/b", "a/b/c"}},
+	}
+
+	for _, tt := range tests {
+		if got := TagAncestors(tt.tag); !slices.Equal(got, tt.want) {
+			t.Errorf("TagAncestors(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
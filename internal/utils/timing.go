@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Timer logs how long each stage of a run takes, for diagnosing slowness. A
+// nil Timer is disabled and does nothing, so callers don't need to check.
+type Timer struct {
+	w   io.Writer
+	now func() time.Time
+}
+
+// NewTimer creates a timer logging to w, reading the time from now
+func NewTimer(w io.Writer, now func() time.Time) *Timer {
+	return &Timer{w: w, now: now}
+}
+
+// Stage starts timing a stage and returns the function that ends it, logging
+// a line such as "open: 1.2ms"
+func (t *Timer) Stage(name string) func() {
+	if t == nil {
+		return func() {}
+	}
+	start := t.now()
+	return func() {
+		fmt.Fprintf(t.w, "%s: %s\n", name, t.now().Sub(start))
+	}
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestTimer_Stage(t *testing.T) {
+	// Each reading of the clock is 5ms after the last
+	clock := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+	now := func() time.Time {
+		clock = clock.Add(5 * time.Millisecond)
+		return clock
+	}
+
+	var out bytes.Buffer
+	timer := NewTimer(&out, now)
+	stopOpen := timer.Stage("open")
+	stopOpen()
+	stopRender := timer.Stage("render")
+	stopRender()
+
+	want := "open: 5ms\nrender: 5ms\n"
+	if out.String() != want {
+		t.Errorf("Timer output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestTimer_Disabled(t *testing.T) {
+	var timer *Timer
+	// A nil timer must not panic
+	timer.Stage("open")()
+}